@@ -0,0 +1,203 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
+	"github.com/lnsp/sox/events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// maxActivities is the number of cluster activities kept in memory.
+	maxActivities           = 1000
+	defaultActivityPageSize = 100
+	maxActivityPageSize     = 1000
+)
+
+// activityLog keeps the activities of cluster operations which are not owned by a single node.
+type activityLog struct {
+	mu         sync.Mutex
+	lastID     uint64
+	activities []*api.Activity
+	// events receives the recorded activities.
+	events *events.Bus
+}
+
+// record keeps the activity together with the user named in ctx and publishes it.
+func (l *activityLog) record(ctx context.Context, activityType api.Activity_Type, kind, subject, node string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lastID++
+	activity := &api.Activity{
+		Id:        strconv.FormatUint(l.lastID, 10),
+		Type:      activityType,
+		Kind:      kind,
+		Subject:   subject,
+		Timestamp: timestamppb.Now(),
+		Node:      node,
+		Actor:     auth.FromIncomingContext(ctx).User,
+	}
+	l.activities = append(l.activities, activity)
+	if len(l.activities) > maxActivities {
+		l.activities = l.activities[len(l.activities)-maxActivities:]
+	}
+	l.events.Publish(&api.Event{
+		Type:      api.Event_ACTIVITY_RECORDED,
+		Timestamp: activity.Timestamp,
+		Kind:      kind,
+		Subject:   subject,
+		Activity:  activity,
+	})
+}
+
+// list returns a page of the activities matching the request following the activity with the given ID,
+// and if there are more.
+func (l *activityLog) list(request *api.ListActivitiesRequest, after string, size int) ([]*api.Activity, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	afterID, _ := strconv.ParseUint(after, 10, 64)
+	types := make(map[api.Activity_Type]bool)
+	for _, t := range request.Types {
+		types[t] = true
+	}
+	var page []*api.Activity
+	for i := range l.activities {
+		activity := l.activities[len(l.activities)-1-i]
+		if request.OldestFirst {
+			activity = l.activities[i]
+		}
+		id, _ := strconv.ParseUint(activity.Id, 10, 64)
+		switch {
+		case request.OldestFirst && id <= afterID:
+		case !request.OldestFirst && afterID > 0 && id >= afterID:
+		case request.Subject != "" && activity.Subject != request.Subject:
+		case len(types) > 0 && !types[activity.Type]:
+		case request.Since != nil && activity.Timestamp.AsTime().Before(request.Since.AsTime()):
+		case request.Until != nil && !activity.Timestamp.AsTime().Before(request.Until.AsTime()):
+		default:
+			page = append(page, activity)
+		}
+		if len(page) > size {
+			return page[:size], true
+		}
+	}
+	return page, false
+}
+
+func activityPageSize(requested int32) int {
+	switch {
+	case requested <= 0:
+		return defaultActivityPageSize
+	case requested > maxActivityPageSize:
+		return maxActivityPageSize
+	}
+	return int(requested)
+}
+
+// cursor holds the token each node continues a list or watch with. The activities and
+// events of the aggregator itself are continued with the token stored for the empty node name.
+type cursor map[string]string
+
+func decodeCursor(token string) (cursor, error) {
+	c := cursor{}
+	if token == "" {
+		return c, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// activityPage is a page of activities listed by a single node.
+type activityPage struct {
+	node       string
+	activities []*api.Activity
+	more       bool
+}
+
+// ListActivities merges pages of the activities of all nodes. The page token holds
+// the position of every node, which is continued after its last merged activity.
+func (server *Server) ListActivities(ctx context.Context, request *api.ListActivitiesRequest) (*api.ListActivitiesResponse, error) {
+	positions, err := decodeCursor(request.PageToken)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page token")
+	}
+	size := activityPageSize(request.PageSize)
+	local, more := server.activities.list(request, positions[""], size)
+	pages := []activityPage{{activities: local, more: more}}
+	var mu sync.Mutex
+	if err := server.broadcast(func(n *node) error {
+		nodeRequest := proto.Clone(request).(*api.ListActivitiesRequest)
+		nodeRequest.PageSize = int32(size)
+		nodeRequest.PageToken = positions[n.name]
+		resp, err := n.client.ListActivities(ctx, nodeRequest)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		pages = append(pages, activityPage{
+			node:       n.name,
+			activities: resp.Activities,
+			more:       resp.NextPageToken != "",
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	type entry struct {
+		node     string
+		activity *api.Activity
+	}
+	var merged []entry
+	for _, page := range pages {
+		for _, activity := range page.activities {
+			merged = append(merged, entry{node: page.node, activity: activity})
+		}
+		more = more || page.more
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		a, b := merged[i].activity.Timestamp.AsTime(), merged[j].activity.Timestamp.AsTime()
+		if request.OldestFirst {
+			return a.Before(b)
+		}
+		return b.Before(a)
+	})
+	if len(merged) > size {
+		merged = merged[:size]
+		more = true
+	}
+	activities := make([]*api.Activity, len(merged))
+	for i := range merged {
+		activities[i] = merged[i].activity
+		positions[merged[i].node] = merged[i].activity.Id
+	}
+	var nextPageToken string
+	if more {
+		nextPageToken = positions.encode()
+	}
+	return &api.ListActivitiesResponse{
+		Activities:    activities,
+		NextPageToken: nextPageToken,
+	}, nil
+}
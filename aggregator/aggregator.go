@@ -0,0 +1,256 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// NodeMetadataKey selects the node requests for node-local resources are forwarded to.
+const NodeMetadataKey = "sox-node"
+
+// Server exposes the sox API on top of a cluster of nodes. Machine requests are forwarded
+// to the node owning the machine, lists are merged across all nodes.
+type Server struct {
+	api.UnimplementedSoxServer
+
+	registry  *Registry
+	scheduler *Scheduler
+	join      JoinPolicy
+
+	// owners maps machine IDs and names to the name of their node.
+	ownersMu sync.Mutex
+	owners   map[string]string
+
+	activities activityLog
+
+	// moving is set while a drain or rebalance moves machines.
+	movingMu sync.Mutex
+	moving   bool
+
+	// identityMu serializes changes and syncs of the identity records.
+	identityMu sync.Mutex
+	// syncUser is the admin identity records are replicated as, empty to use the client certificate.
+	syncUser string
+	// source names the node which held the latest identity records in the last sync.
+	sourceMu sync.Mutex
+	source   string
+
+	// quotaLocks serialize the allocations of each project.
+	quotaMu    sync.Mutex
+	quotaLocks map[string]*sync.Mutex
+}
+
+// New creates an aggregator on top of the registered nodes. Identity records are replicated
+// between the nodes as syncUser, or as the aggregator itself if empty.
+func New(registry *Registry, scheduler *Scheduler, join JoinPolicy, syncUser string) *Server {
+	server := &Server{
+		registry:   registry,
+		scheduler:  scheduler,
+		join:       join,
+		syncUser:   syncUser,
+		owners:     make(map[string]string),
+		quotaLocks: make(map[string]*sync.Mutex),
+		activities: activityLog{
+			events: events.NewBus(""),
+		},
+	}
+	go server.replicateIdentity()
+	return server
+}
+
+// requestedNode returns the node name given in the request metadata.
+func requestedNode(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(NodeMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// targetNode picks the node for requests on node-local resources. It is either given
+// in the request metadata or the only node of the cluster.
+func (server *Server) targetNode(ctx context.Context) (*node, error) {
+	if name := requestedNode(ctx); name != "" {
+		n, ok := server.registry.Get(name)
+		if !ok {
+			return nil, status.Errorf(codes.Unavailable, "node %s is not ready", name)
+		}
+		return n, nil
+	}
+	nodes := server.registry.Ready()
+	switch len(nodes) {
+	case 0:
+		return nil, status.Errorf(codes.Unavailable, "no node is ready")
+	case 1:
+		return nodes[0], nil
+	}
+	return nil, status.Errorf(codes.FailedPrecondition, "multiple nodes available, select one using the %s metadata", NodeMetadataKey)
+}
+
+// broadcast calls fn for all ready nodes in parallel. Merged results would be incomplete if a node
+// failed, so the error of the first failed node by name is returned with its status code.
+func (server *Server) broadcast(fn func(n *node) error) error {
+	nodes := server.registry.Ready()
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		wg.Add(1)
+		go func(i int, n *node) {
+			defer wg.Done()
+			errs[i] = fn(n)
+		}(i, n)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			s := status.Convert(err)
+			return status.Errorf(s.Code(), "node %s: %s", nodes[i].name, s.Message())
+		}
+	}
+	return nil
+}
+
+// refreshOwners rebuilds the machine owner map from the machine lists of all nodes.
+func (server *Server) refreshOwners(ctx context.Context) {
+	owners := make(map[string]string)
+	var mu sync.Mutex
+	err := server.broadcast(func(n *node) error {
+		resp, err := n.client.ListMachines(ctx, &api.ListMachinesRequest{})
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, machine := range resp.Machines {
+			owners[machine.Id] = n.name
+			owners[machine.Name] = n.name
+		}
+		return nil
+	})
+	server.ownersMu.Lock()
+	defer server.ownersMu.Unlock()
+	if err != nil {
+		// Keep the owners known from the failed nodes
+		log.Println("refresh machine owners:", err)
+		for id, name := range owners {
+			server.owners[id] = name
+		}
+		return
+	}
+	server.owners = owners
+}
+
+func (server *Server) owner(id string) (*node, bool) {
+	server.ownersMu.Lock()
+	name, ok := server.owners[id]
+	server.ownersMu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return server.registry.Get(name)
+}
+
+// forMachine calls fn with the client of the node owning the machine. Stale owner
+// entries are refreshed once the node does not know the machine anymore.
+func (server *Server) forMachine(ctx context.Context, id string, fn func(client api.SoxClient) error) error {
+	n, ok := server.owner(id)
+	if ok {
+		err := fn(n.client)
+		if status.Code(err) != codes.NotFound {
+			return err
+		}
+	}
+	server.refreshOwners(ctx)
+	n, ok = server.owner(id)
+	if !ok {
+		return status.Errorf(codes.NotFound, "machine %s not found on any node", id)
+	}
+	return fn(n.client)
+}
+
+// placeMachine picks the node for a new machine using the scheduler. A node given
+// in the request metadata is treated like the node constraint of the request.
+func (server *Server) placeMachine(ctx context.Context, request *api.CreateMachineRequest) (*node, error) {
+	if name := requestedNode(ctx); name != "" && request.Node == "" {
+		request = proto.Clone(request).(*api.CreateMachineRequest)
+		request.Node = name
+	}
+	var ready []*api.Node
+	for _, info := range server.registry.List() {
+		if info.Status == api.Node_READY {
+			ready = append(ready, info)
+		}
+	}
+	best, err := server.scheduler.Schedule(ready, request)
+	if err != nil {
+		return nil, status.Errorf(codes.ResourceExhausted, "schedule machine: %v", err)
+	}
+	n, ok := server.registry.Get(best.Name)
+	if !ok {
+		return nil, status.Errorf(codes.Unavailable, "node %s is not ready", best.Name)
+	}
+	return n, nil
+}
+
+func (server *Server) RegisterNode(ctx context.Context, request *api.RegisterNodeRequest) (*api.RegisterNodeResponse, error) {
+	if request.Node == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "node must be given")
+	}
+	if err := server.join.authorize(ctx, request.Node.Name); err != nil {
+		return nil, err
+	}
+	if err := server.registry.Register(request.Node); errors.Is(err, errAddressChanged) {
+		return nil, status.Errorf(codes.AlreadyExists, "register node: %v", err)
+	} else if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "register node: %v", err)
+	}
+	return &api.RegisterNodeResponse{}, nil
+}
+
+func (server *Server) ListNodes(ctx context.Context, request *api.ListNodesRequest) (*api.ListNodesResponse, error) {
+	return &api.ListNodesResponse{
+		Nodes: server.registry.List(),
+	}, nil
+}
+
+func (server *Server) GetHostInfo(ctx context.Context, request *api.GetHostInfoRequest) (*api.GetHostInfoResponse, error) {
+	if request.Node != "" {
+		n, ok := server.registry.Get(request.Node)
+		if !ok {
+			return nil, status.Errorf(codes.Unavailable, "node %s is not ready", request.Node)
+		}
+		return n.client.GetHostInfo(ctx, request)
+	}
+	var mu sync.Mutex
+	hosts := []*api.HostInfo{}
+	if err := server.broadcast(func(n *node) error {
+		resp, err := n.client.GetHostInfo(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		hosts = append(hosts, resp.Hosts...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Node < hosts[j].Node })
+	return &api.GetHostInfoResponse{
+		Hosts: hosts,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package aggregator
+
+import (
+	"context"
+
+	"github.com/lnsp/sox/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authenticate names the user of a verified client certificate in the request metadata, so nodes
+// trusting the certificate of the aggregator act on its behalf. Tokens are passed on and checked by
+// the nodes. Without credentials the request is rejected if authentication is required.
+func authenticate(ctx context.Context, required bool) (context.Context, error) {
+	if auth.FromIncomingContext(ctx).Token != "" {
+		return ctx, nil
+	}
+	if user := auth.UserFromPeer(ctx); user != "" {
+		return auth.WithUser(ctx, user), nil
+	}
+	if required {
+		return nil, status.Errorf(codes.Unauthenticated, "missing token or client certificate")
+	}
+	return ctx, nil
+}
+
+// JoinPolicy decides which callers may register nodes. The aggregator dials registered nodes
+// and forwards the identity of callers to them, so registrations have to be authenticated.
+type JoinPolicy struct {
+	// Identities are common names of client certificates which may register nodes of any name.
+	// Nodes presenting a certificate for their own name are always accepted.
+	Identities []string
+	// Insecure accepts registrations without client certificate, which is only safe on trusted networks.
+	Insecure bool
+}
+
+// authorize checks that the caller may register the node with the given name.
+func (policy JoinPolicy) authorize(ctx context.Context, name string) error {
+	peer := auth.UserFromPeer(ctx)
+	if peer == "" {
+		if policy.Insecure {
+			return nil
+		}
+		return status.Errorf(codes.Unauthenticated, "nodes must register with a client certificate")
+	}
+	if peer == name {
+		return nil
+	}
+	for _, identity := range policy.Identities {
+		if peer == identity {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "certificate of %s may not register node %s", peer, name)
+}
+
+// UnaryInterceptor authenticates requests before they are handled by the aggregator.
+func UnaryInterceptor(required bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, required)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor authenticates streaming requests before they are handled by the aggregator.
+func StreamInterceptor(required bool) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), required)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authenticatedStream) Context() context.Context {
+	return stream.ctx
+}
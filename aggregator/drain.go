@@ -0,0 +1,335 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/lnsp/sox/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rebalanceThreshold is the difference in utilisation between nodes a rebalance tolerates.
+const rebalanceThreshold = 0.1
+
+// candidate is a machine which may be moved, described as the request needed to place it.
+type candidate struct {
+	machine *api.Machine
+	request *api.CreateMachineRequest
+	// pinned tells why the machine can not leave its node, empty if it can.
+	pinned string
+}
+
+// readyNodes returns copies of all ready nodes to plan moves on.
+func (server *Server) readyNodes() []*api.Node {
+	var nodes []*api.Node
+	for _, info := range server.registry.List() {
+		if info.Status == api.Node_READY {
+			if info.Allocated == nil {
+				info.Allocated = &api.Node_Resources{}
+			}
+			nodes = append(nodes, info)
+		}
+	}
+	return nodes
+}
+
+// readyNode returns a copy of the ready node with the given name.
+func (server *Server) readyNode(name string) (*api.Node, bool) {
+	for _, info := range server.readyNodes() {
+		if info.Name == name {
+			return info, true
+		}
+	}
+	return nil, false
+}
+
+// pinned returns the reasons machines of the node can not be moved by machine ID. Port forwards
+// and floating IPs are bound to the host addresses of the node, so their machines are not exported.
+func (server *Server) pinned(ctx context.Context, n *node) (map[string][]string, error) {
+	forwards, err := n.client.ListPortForwards(ctx, &api.ListPortForwardsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("list port forwards on %s: %w", n.name, err)
+	}
+	fips, err := n.client.ListFloatingIPs(ctx, &api.ListFloatingIPsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("list floating ips on %s: %w", n.name, err)
+	}
+	reasons := make(map[string][]string)
+	for _, pf := range forwards.PortForwards {
+		reasons[pf.MachineId] = append(reasons[pf.MachineId], fmt.Sprintf("port forward %d/%s", pf.HostPort, pf.Protocol))
+	}
+	for _, fip := range fips.FloatingIps {
+		if fip.MachineId != "" {
+			reasons[fip.MachineId] = append(reasons[fip.MachineId], "floating ip "+fip.Address)
+		}
+	}
+	return reasons, nil
+}
+
+// candidates lists the machines of a node, largest first.
+func (server *Server) candidates(ctx context.Context, n *node) ([]candidate, error) {
+	resp, err := n.client.ListMachines(ctx, &api.ListMachinesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("list machines on %s: %w", n.name, err)
+	}
+	pinned, err := server.pinned(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]candidate, len(resp.Machines))
+	for i := range resp.Machines {
+		details, err := n.client.GetMachineDetails(ctx, &api.GetMachineDetailsRequest{
+			Id: resp.Machines[i].Id,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get machine %s: %w", resp.Machines[i].Name, err)
+		}
+		machine := details.Machine
+		networkIds := make([]string, len(machine.Networks))
+		for j := range machine.Networks {
+			networkIds[j] = machine.Networks[j].NetworkId
+		}
+		// Images are copied along with the machine, so they are not required on the target
+		candidates[i] = candidate{
+			machine: machine,
+			request: &api.CreateMachineRequest{
+				Name:       machine.Name,
+				Specs:      machine.Specs,
+				NetworkIds: networkIds,
+			},
+		}
+		if reasons := pinned[machine.Id]; len(reasons) > 0 {
+			candidates[i].pinned = "bound to " + strings.Join(reasons, ", ")
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].machine.Specs.GetMemory() > candidates[j].machine.Specs.GetMemory()
+	})
+	return candidates, nil
+}
+
+// allocate adds the specs times sign to the allocated resources of a planned node.
+func allocate(node *api.Node, specs *api.Machine_Specs, sign int64) {
+	node.Allocated.Cpus += sign * specs.GetCpus()
+	node.Allocated.Memory += sign * specs.GetMemory()
+	node.Allocated.Disk += sign * specs.GetDisk()
+}
+
+// utilisation is the average share of allocated vCPUs and memory.
+func utilisation(node *api.Node) float64 {
+	capacity, allocated := node.GetCapacity(), node.GetAllocated()
+	return (share(allocated.GetCpus(), capacity.GetCpus()) + share(allocated.GetMemory(), capacity.GetMemory())) / 2
+}
+
+// newMove plans a live migration if the machine is running and the target supports it.
+func newMove(c candidate, source string, target *api.Node) *api.MachineMove {
+	method := api.MachineMove_COLD
+	if c.machine.Status == api.Machine_RUNNING && target.MigrationUri != "" {
+		method = api.MachineMove_LIVE
+	}
+	return &api.MachineMove{
+		MachineId:   c.machine.Id,
+		MachineName: c.machine.Name,
+		SourceNode:  source,
+		TargetNode:  target.Name,
+		Method:      method,
+	}
+}
+
+// planDrain places every machine of the source node on one of the other nodes.
+func (server *Server) planDrain(ctx context.Context, source *node) ([]*api.MachineMove, error) {
+	var targets []*api.Node
+	for _, info := range server.readyNodes() {
+		if info.Name != source.name {
+			targets = append(targets, info)
+		}
+	}
+	candidates, err := server.candidates(ctx, source)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	moves := make([]*api.MachineMove, 0, len(candidates))
+	for _, c := range candidates {
+		target, err := server.scheduler.Schedule(targets, c.request)
+		if err != nil {
+			return nil, status.Errorf(codes.ResourceExhausted, "place machine %s: %v", c.machine.Name, err)
+		}
+		allocate(target, c.machine.Specs, 1)
+		move := newMove(c, source.name, target)
+		move.BlockedReason = c.pinned
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
+
+// planRebalance repeatedly moves a machine from the most to the least utilised node
+// as long as it narrows the gap between them. Every machine is moved at most once.
+func (server *Server) planRebalance(ctx context.Context) ([]*api.MachineMove, error) {
+	var nodes []*api.Node
+	candidates := make(map[string][]candidate)
+	for _, info := range server.readyNodes() {
+		if info.Cordoned {
+			continue
+		}
+		n, ok := server.registry.Get(info.Name)
+		if !ok {
+			continue
+		}
+		machines, err := server.candidates(ctx, n)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "%v", err)
+		}
+		nodes = append(nodes, info)
+		candidates[info.Name] = machines
+	}
+	moves := []*api.MachineMove{}
+	if len(nodes) < 2 {
+		return moves, nil
+	}
+	moved := make(map[string]bool)
+	for {
+		sort.SliceStable(nodes, func(i, j int) bool { return utilisation(nodes[i]) < utilisation(nodes[j]) })
+		target, source := nodes[0], nodes[len(nodes)-1]
+		gap := utilisation(source) - utilisation(target)
+		if gap < rebalanceThreshold {
+			break
+		}
+		var best *candidate
+		for i, c := range candidates[source.Name] {
+			if moved[c.machine.Id] || c.pinned != "" || server.scheduler.filter(target, c.request) != nil {
+				continue
+			}
+			allocate(source, c.machine.Specs, -1)
+			allocate(target, c.machine.Specs, 1)
+			newGap := math.Abs(utilisation(source) - utilisation(target))
+			allocate(source, c.machine.Specs, 1)
+			allocate(target, c.machine.Specs, -1)
+			if newGap < gap {
+				best, gap = &candidates[source.Name][i], newGap
+			}
+		}
+		if best == nil {
+			break
+		}
+		allocate(source, best.machine.Specs, -1)
+		allocate(target, best.machine.Specs, 1)
+		moved[best.machine.Id] = true
+		moves = append(moves, newMove(*best, source.Name, target))
+	}
+	return moves, nil
+}
+
+// startMoving makes sure only one drain or rebalance moves machines at a time.
+func (server *Server) startMoving() bool {
+	server.movingMu.Lock()
+	defer server.movingMu.Unlock()
+	if server.moving {
+		return false
+	}
+	server.moving = true
+	return true
+}
+
+func (server *Server) stopMoving() {
+	server.movingMu.Lock()
+	server.moving = false
+	server.movingMu.Unlock()
+}
+
+// executeMoves runs the moves one after another and reports if all of them succeeded.
+func (server *Server) executeMoves(ctx context.Context, moves []*api.MachineMove) bool {
+	ok := true
+	for _, move := range moves {
+		if err := server.runMove(ctx, move, true); err != nil {
+			ok = false
+		}
+	}
+	return ok
+}
+
+func (server *Server) cordon(ctx context.Context, name string, cordoned bool) error {
+	n, ok := server.registry.Get(name)
+	if !ok {
+		return status.Errorf(codes.Unavailable, "node %s is not ready", name)
+	}
+	var err error
+	if cordoned {
+		_, err = n.client.CordonNode(ctx, &api.CordonNodeRequest{Name: name})
+	} else {
+		_, err = n.client.UncordonNode(ctx, &api.UncordonNodeRequest{Name: name})
+	}
+	if err != nil {
+		return err
+	}
+	server.registry.SetCordoned(name, cordoned)
+	return nil
+}
+
+func (server *Server) CordonNode(ctx context.Context, request *api.CordonNodeRequest) (*api.CordonNodeResponse, error) {
+	if err := server.cordon(ctx, request.Name, true); err != nil {
+		return nil, err
+	}
+	return &api.CordonNodeResponse{}, nil
+}
+
+func (server *Server) UncordonNode(ctx context.Context, request *api.UncordonNodeRequest) (*api.UncordonNodeResponse, error) {
+	if err := server.cordon(ctx, request.Name, false); err != nil {
+		return nil, err
+	}
+	return &api.UncordonNodeResponse{}, nil
+}
+
+func (server *Server) DrainNode(ctx context.Context, request *api.DrainNodeRequest) (*api.DrainNodeResponse, error) {
+	source, ok := server.registry.Get(request.Name)
+	if !ok {
+		return nil, status.Errorf(codes.Unavailable, "node %s is not ready", request.Name)
+	}
+	moves, err := server.planDrain(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if request.DryRun {
+		return &api.DrainNodeResponse{Moves: moves}, nil
+	}
+	for _, move := range moves {
+		if move.BlockedReason != "" {
+			return nil, status.Errorf(codes.FailedPrecondition, "machine %s can not be moved, it is %s", move.MachineName, move.BlockedReason)
+		}
+	}
+	if !server.startMoving() {
+		return nil, status.Errorf(codes.Aborted, "another drain or rebalance is in progress")
+	}
+	if err := server.cordon(ctx, request.Name, true); err != nil {
+		server.stopMoving()
+		return nil, err
+	}
+	go func() {
+		defer server.stopMoving()
+		if server.executeMoves(ctx, moves) {
+			server.activities.record(ctx, api.Activity_NODE_DRAINED, "node", request.Name, request.Name)
+		}
+	}()
+	return &api.DrainNodeResponse{Moves: moves}, nil
+}
+
+func (server *Server) Rebalance(ctx context.Context, request *api.RebalanceRequest) (*api.RebalanceResponse, error) {
+	moves, err := server.planRebalance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if request.DryRun {
+		return &api.RebalanceResponse{Moves: moves}, nil
+	}
+	if !server.startMoving() {
+		return nil, status.Errorf(codes.Aborted, "another drain or rebalance is in progress")
+	}
+	go func() {
+		defer server.stopMoving()
+		server.executeMoves(ctx, moves)
+	}()
+	return &api.RebalanceResponse{Moves: moves}, nil
+}
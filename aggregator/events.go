@@ -0,0 +1,119 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// nodeWatchInterval is the time between checks for nodes to watch which became ready or failed during a watch.
+const nodeWatchInterval = 10 * time.Second
+
+// nodeEvent is an event received from a node, or the error its watch ended with.
+type nodeEvent struct {
+	node  string
+	event *api.Event
+	token string
+	err   error
+}
+
+// WatchEvents merges the event streams of all ready nodes with the events of the aggregator.
+// The resume token holds the position of every node, which is resumed after its last sent event.
+func (server *Server) WatchEvents(request *api.WatchEventsRequest, stream api.Sox_WatchEventsServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	positions, err := decodeCursor(request.ResumeToken)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid resume token")
+	}
+	watcher, err := server.activities.events.Watch(positions[""])
+	if errors.Is(err, events.ErrExpired) {
+		return status.Errorf(codes.OutOfRange, "%v", err)
+	} else if err != nil {
+		return status.Errorf(codes.Internal, "watch events: %v", err)
+	}
+	defer server.activities.events.Stop(watcher)
+	types := make(map[api.Event_Type]bool)
+	for _, t := range request.Types {
+		types[t] = true
+	}
+	received := make(chan nodeEvent)
+	watching := make(map[string]bool)
+	watchNodes := func() {
+		for _, n := range server.registry.Ready() {
+			if watching[n.name] {
+				continue
+			}
+			watching[n.name] = true
+			go watchNode(ctx, n, &api.WatchEventsRequest{
+				ResumeToken: positions[n.name],
+				Types:       request.Types,
+			}, received)
+		}
+	}
+	watchNodes()
+	ticker := time.NewTicker(nodeWatchInterval)
+	defer ticker.Stop()
+	for {
+		var event *api.Event
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			watchNodes()
+			continue
+		case e, ok := <-watcher.C:
+			if !ok {
+				return status.Errorf(codes.Aborted, "watch fell behind, resume it with the last token")
+			}
+			if len(types) > 0 && !types[e.Type] {
+				continue
+			}
+			event = e
+			positions[""] = e.Id
+		case r := <-received:
+			if status.Code(r.err) == codes.OutOfRange {
+				return status.Errorf(codes.OutOfRange, "watch node %s: %s", r.node, status.Convert(r.err).Message())
+			} else if r.err != nil {
+				// Watch the node again once it is ready, continuing after its last sent event
+				log.Println("watch events of node", r.node, "failed:", r.err)
+				delete(watching, r.node)
+				continue
+			}
+			event = r.event
+			positions[r.node] = r.token
+		}
+		if err := stream.Send(&api.WatchEventsResponse{
+			Event:       event,
+			ResumeToken: positions.encode(),
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// watchNode hands the events of the node to received until its watch fails or ctx is done.
+func watchNode(ctx context.Context, n *node, request *api.WatchEventsRequest, received chan<- nodeEvent) {
+	stream, err := n.client.WatchEvents(ctx, request)
+	for err == nil {
+		var resp *api.WatchEventsResponse
+		if resp, err = stream.Recv(); err != nil {
+			break
+		}
+		select {
+		case received <- nodeEvent{node: n.name, event: resp.Event, token: resp.ResumeToken}:
+		case <-ctx.Done():
+			return
+		}
+	}
+	select {
+	case received <- nodeEvent{node: n.name, err: err}:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,629 @@
+package aggregator
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/lnsp/sox/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Machines are forwarded to their owning node.
+
+func (server *Server) CreateMachine(ctx context.Context, request *api.CreateMachineRequest) (*api.CreateMachineResponse, error) {
+	// Non-positive specs would count as free resources in scheduling and quotas
+	if specs := request.Specs; specs.GetCpus() <= 0 || specs.GetMemory() <= 0 || specs.GetDisk() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "specs must be given and positive")
+	}
+	release, err := server.reserveQuota(ctx, &api.Quota{
+		Cpus:     request.Specs.GetCpus(),
+		Memory:   request.Specs.GetMemory(),
+		Disk:     request.Specs.GetDisk(),
+		Machines: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	n, err := server.placeMachine(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := n.client.CreateMachine(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	// Account for the machine until the node reports it in its next heartbeat
+	server.registry.Allocate(n.name, request.Specs)
+	server.ownersMu.Lock()
+	server.owners[resp.Id] = n.name
+	server.owners[request.Name] = n.name
+	server.ownersMu.Unlock()
+	return resp, nil
+}
+
+func (server *Server) ListMachines(ctx context.Context, request *api.ListMachinesRequest) (*api.ListMachinesResponse, error) {
+	var mu sync.Mutex
+	machines := []*api.Machine{}
+	if err := server.broadcast(func(n *node) error {
+		resp, err := n.client.ListMachines(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		machines = append(machines, resp.Machines...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(machines, func(i, j int) bool { return machines[i].Name < machines[j].Name })
+	return &api.ListMachinesResponse{
+		Machines: machines,
+	}, nil
+}
+
+func (server *Server) GetMachineDetails(ctx context.Context, request *api.GetMachineDetailsRequest) (resp *api.GetMachineDetailsResponse, err error) {
+	err = server.forMachine(ctx, request.Id, func(client api.SoxClient) error {
+		resp, err = client.GetMachineDetails(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) GetMachineStats(ctx context.Context, request *api.GetMachineStatsRequest) (resp *api.GetMachineStatsResponse, err error) {
+	err = server.forMachine(ctx, request.Id, func(client api.SoxClient) error {
+		resp, err = client.GetMachineStats(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) DeleteMachine(ctx context.Context, request *api.DeleteMachineRequest) (resp *api.DeleteMachineResponse, err error) {
+	err = server.forMachine(ctx, request.Id, func(client api.SoxClient) error {
+		resp, err = client.DeleteMachine(ctx, request)
+		return err
+	})
+	if err == nil {
+		server.ownersMu.Lock()
+		delete(server.owners, request.Id)
+		server.ownersMu.Unlock()
+	}
+	return resp, err
+}
+
+func (server *Server) TriggerMachine(ctx context.Context, request *api.TriggerMachineRequest) (resp *api.TriggerMachineResponse, err error) {
+	err = server.forMachine(ctx, request.Id, func(client api.SoxClient) error {
+		resp, err = client.TriggerMachine(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) AttachSecurityGroup(ctx context.Context, request *api.AttachSecurityGroupRequest) (resp *api.AttachSecurityGroupResponse, err error) {
+	err = server.forMachine(ctx, request.MachineId, func(client api.SoxClient) error {
+		resp, err = client.AttachSecurityGroup(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) DetachSecurityGroup(ctx context.Context, request *api.DetachSecurityGroupRequest) (resp *api.DetachSecurityGroupResponse, err error) {
+	err = server.forMachine(ctx, request.MachineId, func(client api.SoxClient) error {
+		resp, err = client.DetachSecurityGroup(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) CreatePortForward(ctx context.Context, request *api.CreatePortForwardRequest) (resp *api.CreatePortForwardResponse, err error) {
+	err = server.forMachine(ctx, request.MachineId, func(client api.SoxClient) error {
+		resp, err = client.CreatePortForward(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) ListPortForwards(ctx context.Context, request *api.ListPortForwardsRequest) (resp *api.ListPortForwardsResponse, err error) {
+	if request.MachineId != "" {
+		err = server.forMachine(ctx, request.MachineId, func(client api.SoxClient) error {
+			resp, err = client.ListPortForwards(ctx, request)
+			return err
+		})
+		return resp, err
+	}
+	var mu sync.Mutex
+	forwards := []*api.PortForward{}
+	if err := server.broadcast(func(n *node) error {
+		resp, err := n.client.ListPortForwards(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		forwards = append(forwards, resp.PortForwards...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(forwards, func(i, j int) bool { return forwards[i].Id < forwards[j].Id })
+	return &api.ListPortForwardsResponse{
+		PortForwards: forwards,
+	}, nil
+}
+
+func (server *Server) AssociateFloatingIP(ctx context.Context, request *api.AssociateFloatingIPRequest) (resp *api.AssociateFloatingIPResponse, err error) {
+	// Floating IPs can only be associated with machines on their own node
+	if request.MachineId != "" && requestedNode(ctx) == "" {
+		err = server.forMachine(ctx, request.MachineId, func(client api.SoxClient) error {
+			resp, err = client.AssociateFloatingIP(ctx, request)
+			return err
+		})
+		return resp, err
+	}
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.AssociateFloatingIP(ctx, request)
+}
+
+func (server *Server) AttachNetworkInterface(ctx context.Context, request *api.AttachNetworkInterfaceRequest) (resp *api.AttachNetworkInterfaceResponse, err error) {
+	err = server.forMachine(ctx, request.MachineId, func(client api.SoxClient) error {
+		resp, err = client.AttachNetworkInterface(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) DetachNetworkInterface(ctx context.Context, request *api.DetachNetworkInterfaceRequest) (resp *api.DetachNetworkInterfaceResponse, err error) {
+	err = server.forMachine(ctx, request.MachineId, func(client api.SoxClient) error {
+		resp, err = client.DetachNetworkInterface(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) UpdateNetworkInterfaceQoS(ctx context.Context, request *api.UpdateNetworkInterfaceQoSRequest) (resp *api.UpdateNetworkInterfaceQoSResponse, err error) {
+	err = server.forMachine(ctx, request.MachineId, func(client api.SoxClient) error {
+		resp, err = client.UpdateNetworkInterfaceQoS(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+// Lists of node-local resources are merged, resources sharing an ID across nodes are listed once.
+
+func (server *Server) ListSSHKeys(ctx context.Context, request *api.ListSSHKeysRequest) (*api.ListSSHKeysResponse, error) {
+	var mu sync.Mutex
+	keys := make(map[string]*api.SSHKey)
+	if err := server.broadcast(func(n *node) error {
+		resp, err := n.client.ListSSHKeys(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, key := range resp.Keys {
+			keys[key.Id] = key
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	merged := make([]*api.SSHKey, 0, len(keys))
+	for _, key := range keys {
+		merged = append(merged, key)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return &api.ListSSHKeysResponse{
+		Keys: merged,
+	}, nil
+}
+
+func (server *Server) ListImages(ctx context.Context, request *api.ListImagesRequest) (*api.ListImagesResponse, error) {
+	var mu sync.Mutex
+	images := make(map[string]*api.Image)
+	if err := server.broadcast(func(n *node) error {
+		resp, err := n.client.ListImages(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, image := range resp.Images {
+			images[image.Id] = image
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	merged := make([]*api.Image, 0, len(images))
+	for _, image := range images {
+		merged = append(merged, image)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return &api.ListImagesResponse{
+		Images: merged,
+	}, nil
+}
+
+func (server *Server) ListNetworks(ctx context.Context, request *api.ListNetworksRequest) (*api.ListNetworksResponse, error) {
+	var mu sync.Mutex
+	networks := make(map[string]*api.Network)
+	if err := server.broadcast(func(n *node) error {
+		resp, err := n.client.ListNetworks(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, network := range resp.Networks {
+			networks[network.Id] = network
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	merged := make([]*api.Network, 0, len(networks))
+	for _, network := range networks {
+		merged = append(merged, network)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return &api.ListNetworksResponse{
+		Networks: merged,
+	}, nil
+}
+
+func (server *Server) ListSecurityGroups(ctx context.Context, request *api.ListSecurityGroupsRequest) (*api.ListSecurityGroupsResponse, error) {
+	var mu sync.Mutex
+	groups := make(map[string]*api.SecurityGroup)
+	if err := server.broadcast(func(n *node) error {
+		resp, err := n.client.ListSecurityGroups(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, group := range resp.SecurityGroups {
+			groups[group.Id] = group
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	merged := make([]*api.SecurityGroup, 0, len(groups))
+	for _, group := range groups {
+		merged = append(merged, group)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return &api.ListSecurityGroupsResponse{
+		SecurityGroups: merged,
+	}, nil
+}
+
+func (server *Server) ListFloatingIPs(ctx context.Context, request *api.ListFloatingIPsRequest) (*api.ListFloatingIPsResponse, error) {
+	var mu sync.Mutex
+	fips := make(map[string]*api.FloatingIP)
+	if err := server.broadcast(func(n *node) error {
+		resp, err := n.client.ListFloatingIPs(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, fip := range resp.FloatingIps {
+			fips[fip.Id] = fip
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	merged := make([]*api.FloatingIP, 0, len(fips))
+	for _, fip := range fips {
+		merged = append(merged, fip)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Address < merged[j].Address })
+	return &api.ListFloatingIPsResponse{
+		FloatingIps: merged,
+	}, nil
+}
+
+func (server *Server) ListVPNPeers(ctx context.Context, request *api.ListVPNPeersRequest) (*api.ListVPNPeersResponse, error) {
+	var mu sync.Mutex
+	peers := make(map[string]*api.VPNPeer)
+	if err := server.broadcast(func(n *node) error {
+		resp, err := n.client.ListVPNPeers(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, peer := range resp.VpnPeers {
+			peers[peer.Id] = peer
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	merged := make([]*api.VPNPeer, 0, len(peers))
+	for _, peer := range peers {
+		merged = append(merged, peer)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return &api.ListVPNPeersResponse{
+		VpnPeers: merged,
+	}, nil
+}
+
+func (server *Server) ListWebhooks(ctx context.Context, request *api.ListWebhooksRequest) (*api.ListWebhooksResponse, error) {
+	var mu sync.Mutex
+	hooks := make(map[string]*api.Webhook)
+	if err := server.broadcast(func(n *node) error {
+		resp, err := n.client.ListWebhooks(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, hook := range resp.Webhooks {
+			hooks[hook.Id] = hook
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	merged := make([]*api.Webhook, 0, len(hooks))
+	for _, hook := range hooks {
+		merged = append(merged, hook)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return &api.ListWebhooksResponse{
+		Webhooks: merged,
+	}, nil
+}
+
+// Changes to node-local resources go to the node selected by the request metadata.
+
+func (server *Server) CreateSSHKey(ctx context.Context, request *api.CreateSSHKeyRequest) (*api.CreateSSHKeyResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.CreateSSHKey(ctx, request)
+}
+
+func (server *Server) DeleteSSHKey(ctx context.Context, request *api.DeleteSSHKeyRequest) (*api.DeleteSSHKeyResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.DeleteSSHKey(ctx, request)
+}
+
+func (server *Server) CreateNetwork(ctx context.Context, request *api.CreateNetworkRequest) (*api.CreateNetworkResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	release, err := server.reserveQuota(ctx, &api.Quota{Networks: 1})
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return n.client.CreateNetwork(ctx, request)
+}
+
+func (server *Server) CreateSecurityGroup(ctx context.Context, request *api.CreateSecurityGroupRequest) (*api.CreateSecurityGroupResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.CreateSecurityGroup(ctx, request)
+}
+
+func (server *Server) UpdateSecurityGroup(ctx context.Context, request *api.UpdateSecurityGroupRequest) (*api.UpdateSecurityGroupResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.UpdateSecurityGroup(ctx, request)
+}
+
+func (server *Server) DeleteSecurityGroup(ctx context.Context, request *api.DeleteSecurityGroupRequest) (*api.DeleteSecurityGroupResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.DeleteSecurityGroup(ctx, request)
+}
+
+func (server *Server) DeletePortForward(ctx context.Context, request *api.DeletePortForwardRequest) (*api.DeletePortForwardResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.DeletePortForward(ctx, request)
+}
+
+func (server *Server) AllocateFloatingIP(ctx context.Context, request *api.AllocateFloatingIPRequest) (*api.AllocateFloatingIPResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	release, err := server.reserveQuota(ctx, &api.Quota{FloatingIps: 1})
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return n.client.AllocateFloatingIP(ctx, request)
+}
+
+func (server *Server) ReleaseFloatingIP(ctx context.Context, request *api.ReleaseFloatingIPRequest) (*api.ReleaseFloatingIPResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.ReleaseFloatingIP(ctx, request)
+}
+
+func (server *Server) CreateVPNPeer(ctx context.Context, request *api.CreateVPNPeerRequest) (*api.CreateVPNPeerResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.CreateVPNPeer(ctx, request)
+}
+
+func (server *Server) DeleteVPNPeer(ctx context.Context, request *api.DeleteVPNPeerRequest) (*api.DeleteVPNPeerResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.DeleteVPNPeer(ctx, request)
+}
+
+func (server *Server) CreateWebhook(ctx context.Context, request *api.CreateWebhookRequest) (*api.CreateWebhookResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.CreateWebhook(ctx, request)
+}
+
+func (server *Server) DeleteWebhook(ctx context.Context, request *api.DeleteWebhookRequest) (*api.DeleteWebhookResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.DeleteWebhook(ctx, request)
+}
+
+func (server *Server) ListWebhookDeliveries(ctx context.Context, request *api.ListWebhookDeliveriesRequest) (*api.ListWebhookDeliveriesResponse, error) {
+	n, err := server.targetNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.client.ListWebhookDeliveries(ctx, request)
+}
+
+// Users, projects, memberships, quotas and tokens are read from and changed on the node holding
+// the latest records, see identity.go.
+
+func (server *Server) CreateUser(ctx context.Context, request *api.CreateUserRequest) (resp *api.CreateUserResponse, err error) {
+	err = server.changeIdentity(ctx, func(client api.SoxClient, latest *api.IdentitySnapshot) error {
+		resp, err = client.CreateUser(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) ListUsers(ctx context.Context, request *api.ListUsersRequest) (*api.ListUsersResponse, error) {
+	n, err := server.identityNode()
+	if err != nil {
+		return nil, err
+	}
+	return n.client.ListUsers(ctx, request)
+}
+
+func (server *Server) DeleteUser(ctx context.Context, request *api.DeleteUserRequest) (resp *api.DeleteUserResponse, err error) {
+	err = server.changeIdentity(ctx, func(client api.SoxClient, latest *api.IdentitySnapshot) error {
+		resp, err = client.DeleteUser(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) CreateProject(ctx context.Context, request *api.CreateProjectRequest) (resp *api.CreateProjectResponse, err error) {
+	err = server.changeIdentity(ctx, func(client api.SoxClient, latest *api.IdentitySnapshot) error {
+		resp, err = client.CreateProject(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) ListProjects(ctx context.Context, request *api.ListProjectsRequest) (*api.ListProjectsResponse, error) {
+	n, err := server.identityNode()
+	if err != nil {
+		return nil, err
+	}
+	return n.client.ListProjects(ctx, request)
+}
+
+func (server *Server) DeleteProject(ctx context.Context, request *api.DeleteProjectRequest) (resp *api.DeleteProjectResponse, err error) {
+	err = server.changeIdentity(ctx, func(client api.SoxClient, latest *api.IdentitySnapshot) error {
+		// Any node may still hold resources of the project
+		if err := server.checkProjectRemoval(ctx, latest, request.Id); err != nil {
+			return err
+		}
+		resp, err = client.DeleteProject(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) ListMemberships(ctx context.Context, request *api.ListMembershipsRequest) (*api.ListMembershipsResponse, error) {
+	n, err := server.identityNode()
+	if err != nil {
+		return nil, err
+	}
+	return n.client.ListMemberships(ctx, request)
+}
+
+func (server *Server) SetMembership(ctx context.Context, request *api.SetMembershipRequest) (resp *api.SetMembershipResponse, err error) {
+	err = server.changeIdentity(ctx, func(client api.SoxClient, latest *api.IdentitySnapshot) error {
+		resp, err = client.SetMembership(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) RemoveMembership(ctx context.Context, request *api.RemoveMembershipRequest) (resp *api.RemoveMembershipResponse, err error) {
+	err = server.changeIdentity(ctx, func(client api.SoxClient, latest *api.IdentitySnapshot) error {
+		resp, err = client.RemoveMembership(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) GetCurrentUser(ctx context.Context, request *api.GetCurrentUserRequest) (*api.GetCurrentUserResponse, error) {
+	n, err := server.identityNode()
+	if err != nil {
+		return nil, err
+	}
+	return n.client.GetCurrentUser(ctx, request)
+}
+
+func (server *Server) CreateToken(ctx context.Context, request *api.CreateTokenRequest) (resp *api.CreateTokenResponse, err error) {
+	err = server.changeIdentity(ctx, func(client api.SoxClient, latest *api.IdentitySnapshot) error {
+		resp, err = client.CreateToken(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) ListTokens(ctx context.Context, request *api.ListTokensRequest) (*api.ListTokensResponse, error) {
+	n, err := server.identityNode()
+	if err != nil {
+		return nil, err
+	}
+	return n.client.ListTokens(ctx, request)
+}
+
+func (server *Server) DeleteToken(ctx context.Context, request *api.DeleteTokenRequest) (resp *api.DeleteTokenResponse, err error) {
+	err = server.changeIdentity(ctx, func(client api.SoxClient, latest *api.IdentitySnapshot) error {
+		resp, err = client.DeleteToken(ctx, request)
+		return err
+	})
+	return resp, err
+}
+
+func (server *Server) SetQuota(ctx context.Context, request *api.SetQuotaRequest) (resp *api.SetQuotaResponse, err error) {
+	err = server.changeIdentity(ctx, func(client api.SoxClient, latest *api.IdentitySnapshot) error {
+		resp, err = client.SetQuota(ctx, request)
+		return err
+	})
+	return resp, err
+}
@@ -0,0 +1,177 @@
+package aggregator
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Users, projects, memberships, quotas and tokens are replicated to all nodes, so tokens are accepted
+// by every node a request is forwarded to. Changes are made on the node holding the latest records,
+// the one with the highest identity revision, and then copied to all other nodes. Nodes joining later
+// receive the records with the next periodic sync. Nodes holding different records of the same revision
+// were changed directly, none of them is overwritten until one of the changes is repeated on its node,
+// which bumps its revision past the others.
+
+const (
+	// identityInterval is the time between periodic syncs of the identity records.
+	identityInterval = 30 * time.Second
+	// identityTimeout bounds the requests of a periodic sync.
+	identityTimeout = 10 * time.Second
+)
+
+// syncContext returns a context for replication requests. They are made as the configured sync user,
+// or without user so nodes identify the aggregator by its client certificate.
+func (server *Server) syncContext(ctx context.Context) context.Context {
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{})
+	return auth.NewOutgoingContext(ctx, auth.Identity{User: server.syncUser})
+}
+
+// syncIdentity exports the records of all ready nodes and imports the latest ones on every node
+// whose records differ. It returns the node holding the latest records along with its snapshot.
+// Nodes failing to import are only logged, they are retried by the next sync. The caller must
+// hold identityMu.
+func (server *Server) syncIdentity(ctx context.Context) (*node, *api.IdentitySnapshot, error) {
+	ctx = server.syncContext(ctx)
+	nodes := server.registry.Ready()
+	if len(nodes) == 0 {
+		return nil, nil, status.Errorf(codes.Unavailable, "no node is ready")
+	}
+	snapshots := make([]*api.IdentitySnapshot, len(nodes))
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		wg.Add(1)
+		go func(i int, n *node) {
+			defer wg.Done()
+			resp, err := n.client.ExportIdentity(ctx, &api.ExportIdentityRequest{})
+			snapshots[i], errs[i] = resp.GetSnapshot(), err
+		}(i, n)
+	}
+	wg.Wait()
+	// Without the records of every node, the latest ones are not known
+	source := 0
+	for i, err := range errs {
+		if err != nil {
+			s := status.Convert(err)
+			return nil, nil, status.Errorf(s.Code(), "export identity of node %s: %s", nodes[i].name, s.Message())
+		}
+		if snapshots[i].Revision > snapshots[source].Revision {
+			source = i
+		}
+	}
+	latest := snapshots[source]
+	for i, snapshot := range snapshots {
+		if snapshot.Revision == latest.Revision && !proto.Equal(snapshot, latest) {
+			return nil, nil, status.Errorf(codes.Aborted, "nodes %s and %s hold different identity records of revision %d, make another change on the node whose records should be kept",
+				nodes[source].name, nodes[i].name, latest.Revision)
+		}
+	}
+	for i, n := range nodes {
+		if proto.Equal(snapshots[i], latest) {
+			continue
+		}
+		if _, err := n.client.ImportIdentity(ctx, &api.ImportIdentityRequest{Snapshot: latest}); err != nil {
+			log.Println("import identity on node", n.name+":", err)
+			continue
+		}
+		log.Println("replicated identity revision", latest.Revision, "to node", n.name)
+	}
+	server.sourceMu.Lock()
+	server.source = nodes[source].name
+	server.sourceMu.Unlock()
+	return nodes[source], latest, nil
+}
+
+// replicateIdentity syncs the identity records periodically.
+func (server *Server) replicateIdentity() {
+	ticker := time.NewTicker(identityInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), identityTimeout)
+		server.identityMu.Lock()
+		if _, _, err := server.syncIdentity(ctx); err != nil {
+			log.Println("sync identity:", err)
+		}
+		server.identityMu.Unlock()
+		cancel()
+	}
+}
+
+// changeIdentity makes the change on the node holding the latest records and replicates them afterwards.
+// Changes are serialized, so they are never made on two nodes at once.
+func (server *Server) changeIdentity(ctx context.Context, fn func(client api.SoxClient, latest *api.IdentitySnapshot) error) error {
+	server.identityMu.Lock()
+	defer server.identityMu.Unlock()
+	source, latest, err := server.syncIdentity(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(source.client, latest); err != nil {
+		return err
+	}
+	if _, _, err := server.syncIdentity(ctx); err != nil {
+		log.Println("replicate identity:", err)
+	}
+	return nil
+}
+
+// identityNode returns the node identity records are read from, the one which held the latest records
+// in the last sync.
+func (server *Server) identityNode() (*node, error) {
+	server.sourceMu.Lock()
+	name := server.source
+	server.sourceMu.Unlock()
+	if n, ok := server.registry.Get(name); ok {
+		return n, nil
+	}
+	nodes := server.registry.Ready()
+	if len(nodes) == 0 {
+		return nil, status.Errorf(codes.Unavailable, "no node is ready")
+	}
+	return nodes[0], nil
+}
+
+// checkProjectRemoval asks all nodes whether they could drop the project, which fails while
+// they still hold resources of the project.
+func (server *Server) checkProjectRemoval(ctx context.Context, latest *api.IdentitySnapshot, id string) error {
+	snapshot := proto.Clone(latest).(*api.IdentitySnapshot)
+	snapshot.Revision++
+	snapshot.Projects = nil
+	for _, project := range latest.Projects {
+		if project.Id == id || project.Name == id {
+			id = project.Id
+			continue
+		}
+		snapshot.Projects = append(snapshot.Projects, project)
+	}
+	if len(snapshot.Projects) == len(latest.Projects) {
+		// Unknown projects are reported by the node
+		return nil
+	}
+	snapshot.Memberships = nil
+	for _, membership := range latest.Memberships {
+		if membership.ProjectId != id {
+			snapshot.Memberships = append(snapshot.Memberships, membership)
+		}
+	}
+	snapshot.Quotas = nil
+	for _, quota := range latest.Quotas {
+		if quota.ProjectId != id {
+			snapshot.Quotas = append(snapshot.Quotas, quota)
+		}
+	}
+	ctx = server.syncContext(ctx)
+	return server.broadcast(func(n *node) error {
+		_, err := n.client.ImportIdentity(ctx, &api.ImportIdentityRequest{Snapshot: snapshot, ValidateOnly: true})
+		return err
+	})
+}
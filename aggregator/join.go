@@ -0,0 +1,46 @@
+package aggregator
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lnsp/sox/api"
+	"google.golang.org/grpc"
+)
+
+// Join registers the node with the aggregator and keeps sending heartbeats until ctx is done.
+// The node info is refreshed on every heartbeat to report the current allocation.
+func Join(ctx context.Context, address string, interval time.Duration, info func() (*api.Node, error), dial ...grpc.DialOption) error {
+	conn, err := grpc.Dial(address, dial...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := api.NewSoxClient(conn)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := heartbeat(ctx, client, interval, info); err != nil {
+			log.Println("heartbeat to aggregator", address, "failed:", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func heartbeat(ctx context.Context, client api.SoxClient, timeout time.Duration, info func() (*api.Node, error)) error {
+	node, err := info()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, err = client.RegisterNode(ctx, &api.RegisterNodeRequest{
+		Node: node,
+	})
+	return err
+}
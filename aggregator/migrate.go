@@ -0,0 +1,215 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// moveTimeout bounds a single machine move including the disk copy.
+const moveTimeout = time.Hour
+
+// moveMachine moves the machine to the target node, either live or by stopping it,
+// copying its disk and starting it there. The addresses and MACs of the machine are kept.
+func (server *Server) moveMachine(ctx context.Context, move *api.MachineMove, copyStorage bool) error {
+	source, ok := server.registry.Get(move.SourceNode)
+	if !ok {
+		return fmt.Errorf("node %s is not ready", move.SourceNode)
+	}
+	target, ok := server.registry.Get(move.TargetNode)
+	if !ok {
+		return fmt.Errorf("node %s is not ready", move.TargetNode)
+	}
+	export, err := source.client.ExportMachine(ctx, &api.ExportMachineRequest{
+		Id: move.MachineId,
+	})
+	if err != nil {
+		return fmt.Errorf("export machine: %w", err)
+	}
+	if move.Method == api.MachineMove_LIVE {
+		err = server.migrateLive(ctx, source, target, export, copyStorage)
+	} else {
+		err = server.migrateCold(ctx, source, target, export)
+	}
+	if err != nil {
+		return err
+	}
+	server.ownersMu.Lock()
+	server.owners[move.MachineId] = move.TargetNode
+	server.owners[move.MachineName] = move.TargetNode
+	server.ownersMu.Unlock()
+	server.registry.Allocate(move.TargetNode, export.Machine.Specs)
+	server.registry.Release(move.SourceNode, export.Machine.Specs)
+	// Other nodes still forward traffic for the machine to the source node
+	macs := make([]string, len(export.Machine.Networks))
+	for i := range export.Machine.Networks {
+		macs[i] = export.Machine.Networks[i].MacAddress
+	}
+	if err := server.broadcast(func(n *node) error {
+		_, err := n.client.FlushForwarding(ctx, &api.FlushForwardingRequest{
+			MacAddresses: macs,
+		})
+		return err
+	}); err != nil {
+		// The machine has moved, stale entries only delay traffic until they expire
+		log.Println("flush forwarding of machine", move.MachineId, "failed:", err)
+	}
+	return nil
+}
+
+// migrateLive prepares the machine on the target and lets libvirt on the source migrate it.
+func (server *Server) migrateLive(ctx context.Context, source, target *node, export *api.ExportMachineResponse, copyStorage bool) error {
+	info, ok := server.readyNode(target.name)
+	if !ok {
+		return fmt.Errorf("node %s is not ready", target.name)
+	}
+	if info.MigrationUri == "" {
+		return fmt.Errorf("node %s does not support live migration", target.name)
+	}
+	prepared, err := target.client.ImportMachine(ctx, &api.ImportMachineRequest{
+		Export:        export,
+		SourceAddress: source.address,
+		Live:          true,
+		CopyStorage:   copyStorage,
+	})
+	if err != nil {
+		return fmt.Errorf("prepare machine: %w", err)
+	}
+	if _, err := source.client.SendMachine(ctx, &api.SendMachineRequest{
+		Id:             export.Machine.Id,
+		DestinationUri: info.MigrationUri,
+		DomainXml:      prepared.DomainXml,
+		CopyStorage:    copyStorage,
+	}); err != nil {
+		// Drop the prepared machine, it keeps running on the source
+		if _, err := target.client.AbortImport(ctx, &api.AbortImportRequest{
+			Id:          export.Machine.Id,
+			CopyStorage: copyStorage,
+		}); err != nil {
+			log.Println("drop prepared machine", export.Machine.Id, "on", target.name, "failed:", err)
+		}
+		return fmt.Errorf("send machine: %w", err)
+	}
+	return nil
+}
+
+// migrateCold stops the machine, copies it to the target and deletes it on the source.
+func (server *Server) migrateCold(ctx context.Context, source, target *node, export *api.ExportMachineResponse) error {
+	details, err := source.client.GetMachineDetails(ctx, &api.GetMachineDetailsRequest{
+		Id: export.Machine.Id,
+	})
+	if err != nil {
+		return fmt.Errorf("get machine: %w", err)
+	}
+	running := details.Machine.Status == api.Machine_RUNNING
+	if running {
+		if _, err := source.client.TriggerMachine(ctx, &api.TriggerMachineRequest{
+			Id:    export.Machine.Id,
+			Event: api.TriggerMachineRequest_POWEROFF,
+		}); err != nil {
+			return fmt.Errorf("stop machine: %w", err)
+		}
+	}
+	if _, err := target.client.ImportMachine(ctx, &api.ImportMachineRequest{
+		Export:        export,
+		SourceAddress: source.address,
+		Start:         running,
+	}); err != nil {
+		// Bring the machine back up where it was
+		if running {
+			if _, err := source.client.TriggerMachine(ctx, &api.TriggerMachineRequest{
+				Id:    export.Machine.Id,
+				Event: api.TriggerMachineRequest_POWERON,
+			}); err != nil {
+				log.Println("restart machine", export.Machine.Id, "on", source.name, "failed:", err)
+			}
+		}
+		return fmt.Errorf("import machine: %w", err)
+	}
+	if _, err := source.client.DeleteMachine(ctx, &api.DeleteMachineRequest{
+		Id: export.Machine.Id,
+	}); err != nil {
+		return fmt.Errorf("delete machine on source: %w", err)
+	}
+	return nil
+}
+
+// runMove moves a machine on behalf of the caller of the request and reports the progress as activities.
+// The move continues when the request is canceled.
+func (server *Server) runMove(ctx context.Context, move *api.MachineMove, copyStorage bool) error {
+	server.activities.record(ctx, api.Activity_MACHINE_MIGRATION_STARTED, "machine", move.MachineId, move.SourceNode)
+	ctx, cancel := context.WithTimeout(auth.Detach(ctx), moveTimeout)
+	defer cancel()
+	if err := server.moveMachine(ctx, move, copyStorage); err != nil {
+		log.Println("move machine", move.MachineName, "from", move.SourceNode, "to", move.TargetNode, "failed:", err)
+		server.activities.record(ctx, api.Activity_MACHINE_MIGRATION_FAILED, "machine", move.MachineId, move.SourceNode)
+		return err
+	}
+	log.Println("moved machine", move.MachineName, "from", move.SourceNode, "to", move.TargetNode, "using", move.Method, "migration")
+	server.activities.record(ctx, api.Activity_MACHINE_MIGRATED, "machine", move.MachineId, move.TargetNode)
+	return nil
+}
+
+func (server *Server) MigrateMachine(ctx context.Context, request *api.MigrateMachineRequest) (*api.MigrateMachineResponse, error) {
+	if !request.Live && !request.CopyStorage {
+		return nil, status.Errorf(codes.InvalidArgument, "cold migrations have to copy storage")
+	}
+	var details *api.GetMachineDetailsResponse
+	if err := server.forMachine(ctx, request.Id, func(client api.SoxClient) (err error) {
+		details, err = client.GetMachineDetails(ctx, &api.GetMachineDetailsRequest{Id: request.Id})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	machine := details.Machine
+	if machine.Node == request.TargetNode {
+		return nil, status.Errorf(codes.InvalidArgument, "machine %s already runs on %s", machine.Name, machine.Node)
+	}
+	if request.Live && machine.Status != api.Machine_RUNNING {
+		return nil, status.Errorf(codes.FailedPrecondition, "only running machines can be migrated live")
+	}
+	target, ok := server.readyNode(request.TargetNode)
+	if !ok {
+		return nil, status.Errorf(codes.Unavailable, "node %s is not ready", request.TargetNode)
+	}
+	// Check that the target fits the machine, its image is copied if missing
+	networkIds := make([]string, len(machine.Networks))
+	for i := range machine.Networks {
+		networkIds[i] = machine.Networks[i].NetworkId
+	}
+	placement := &api.CreateMachineRequest{
+		Specs:      machine.Specs,
+		NetworkIds: networkIds,
+		Node:       request.TargetNode,
+	}
+	if _, err := server.scheduler.Schedule([]*api.Node{target}, placement); err != nil {
+		return nil, status.Errorf(codes.ResourceExhausted, "%v", err)
+	}
+	method := api.MachineMove_COLD
+	if request.Live {
+		method = api.MachineMove_LIVE
+	}
+	move := &api.MachineMove{
+		MachineId:   machine.Id,
+		MachineName: machine.Name,
+		SourceNode:  machine.Node,
+		TargetNode:  request.TargetNode,
+		Method:      method,
+	}
+	if !server.startMoving() {
+		return nil, status.Errorf(codes.Aborted, "another migration, drain or rebalance is in progress")
+	}
+	defer server.stopMoving()
+	if err := server.runMove(ctx, move, request.CopyStorage); err != nil {
+		return nil, status.Errorf(codes.Internal, "migrate machine: %v", err)
+	}
+	return &api.MigrateMachineResponse{
+		Move: move,
+	}, nil
+}
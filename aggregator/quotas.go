@@ -0,0 +1,96 @@
+package aggregator
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lnsp/sox/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Quotas limit the resources of a project across all nodes. Nodes only know their own usage, so the
+// aggregator checks the summed usage before forwarding requests which allocate resources.
+
+func (server *Server) GetQuotaUsage(ctx context.Context, request *api.GetQuotaUsageRequest) (*api.GetQuotaUsageResponse, error) {
+	var mu sync.Mutex
+	var merged *api.GetQuotaUsageResponse
+	usage := &api.Quota{}
+	if err := server.broadcast(func(n *node) error {
+		resp, err := n.client.GetQuotaUsage(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		// The quota is replicated, so all nodes report the same one
+		if merged == nil {
+			merged = &api.GetQuotaUsageResponse{ProjectId: resp.ProjectId, Quota: resp.Quota}
+		}
+		usage.Cpus += resp.Usage.GetCpus()
+		usage.Memory += resp.Usage.GetMemory()
+		usage.Disk += resp.Usage.GetDisk()
+		usage.Machines += resp.Usage.GetMachines()
+		usage.FloatingIps += resp.Usage.GetFloatingIps()
+		usage.Networks += resp.Usage.GetNetworks()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if merged == nil {
+		return nil, status.Errorf(codes.Unavailable, "no node is ready")
+	}
+	merged.Usage = usage
+	return merged, nil
+}
+
+// projectLock returns the lock serializing the allocations of the project.
+func (server *Server) projectLock(project string) *sync.Mutex {
+	server.quotaMu.Lock()
+	defer server.quotaMu.Unlock()
+	mu, ok := server.quotaLocks[project]
+	if !ok {
+		mu = &sync.Mutex{}
+		server.quotaLocks[project] = mu
+	}
+	return mu
+}
+
+// reserveQuota checks that the project the request allocates resources in has room for them across
+// all nodes. The returned function must be called once the resources are allocated, concurrent
+// reservations of the project wait for it.
+func (server *Server) reserveQuota(ctx context.Context, requested *api.Quota) (func(), error) {
+	usage, err := server.GetQuotaUsage(ctx, &api.GetQuotaUsageRequest{})
+	if status.Code(err) == codes.InvalidArgument {
+		// Without a project the nodes reject the request themselves
+		return func() {}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	mu := server.projectLock(usage.ProjectId)
+	mu.Lock()
+	// Allocations may have finished while waiting
+	usage, err = server.GetQuotaUsage(ctx, &api.GetQuotaUsageRequest{ProjectId: usage.ProjectId})
+	if err != nil {
+		mu.Unlock()
+		return nil, err
+	}
+	quota, used := usage.Quota, usage.Usage
+	for _, limit := range []struct {
+		name                   string
+		quota, used, requested int64
+	}{
+		{"vCPUs", quota.GetCpus(), used.GetCpus(), requested.Cpus},
+		{"MB memory", quota.GetMemory(), used.GetMemory(), requested.Memory},
+		{"GB disk", quota.GetDisk(), used.GetDisk(), requested.Disk},
+		{"machines", quota.GetMachines(), used.GetMachines(), requested.Machines},
+		{"floating ips", quota.GetFloatingIps(), used.GetFloatingIps(), requested.FloatingIps},
+		{"networks", quota.GetNetworks(), used.GetNetworks(), requested.Networks},
+	} {
+		if limit.quota > 0 && limit.requested > 0 && limit.used+limit.requested > limit.quota {
+			mu.Unlock()
+			return nil, status.Errorf(codes.ResourceExhausted, "quota of project %s exceeded: %d of %d %s in use across all nodes, %d requested", usage.ProjectId, limit.used, limit.quota, limit.name, limit.requested)
+		}
+	}
+	return mu.Unlock, nil
+}
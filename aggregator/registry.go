@@ -0,0 +1,195 @@
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// node is a registered sox node and the connection requests are forwarded on.
+type node struct {
+	// name and address never change, the info is replaced by heartbeats and
+	// must only be read holding the registry lock.
+	name     string
+	address  string
+	info     *api.Node
+	lastSeen time.Time
+	conn     *grpc.ClientConn
+	client   api.SoxClient
+}
+
+// Registry keeps track of the nodes in the cluster and their heartbeats.
+type Registry struct {
+	mu    sync.RWMutex
+	nodes map[string]*node
+	// timeout after which a node without heartbeat is considered unreachable.
+	timeout time.Duration
+	// expiry after which a node without heartbeat is removed, so its name may register again.
+	expiry time.Duration
+	dial   []grpc.DialOption
+}
+
+// NewRegistry creates an empty registry. Nodes are unreachable after the timeout and removed after
+// the expiry, which is raised to the timeout if shorter. Nodes are dialed with the given options,
+// requests to them carry the identity of the caller.
+func NewRegistry(timeout, expiry time.Duration, dial ...grpc.DialOption) *Registry {
+	if expiry < timeout {
+		expiry = timeout
+	}
+	return &Registry{
+		nodes:   make(map[string]*node),
+		timeout: timeout,
+		expiry:  expiry,
+		dial:    append(dial, auth.Forward()...),
+	}
+}
+
+// errAddressChanged is returned when a registered node name is announced with another address.
+var errAddressChanged = errors.New("node is registered with another address")
+
+// Register adds the node or refreshes its heartbeat and capacity. The address of a registered
+// node can not be changed until it expired, so requests for its machines can not be redirected.
+func (registry *Registry) Register(info *api.Node) error {
+	if info.Name == "" || info.Address == "" {
+		return fmt.Errorf("node name and address must not be empty")
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.expire()
+	if existing, ok := registry.nodes[info.Name]; ok {
+		if existing.address != info.Address {
+			return fmt.Errorf("%w %s", errAddressChanged, existing.address)
+		}
+		existing.info = info
+		existing.lastSeen = time.Now()
+		return nil
+	}
+	conn, err := grpc.Dial(info.Address, registry.dial...)
+	if err != nil {
+		return fmt.Errorf("dial node: %w", err)
+	}
+	registry.nodes[info.Name] = &node{
+		name:     info.Name,
+		address:  info.Address,
+		info:     info,
+		lastSeen: time.Now(),
+		conn:     conn,
+		client:   api.NewSoxClient(conn),
+	}
+	log.Println("registered node", info.Name, "at", info.Address)
+	return nil
+}
+
+// expire removes nodes without heartbeat for longer than the expiry and closes their connection.
+// The registry lock must be held.
+func (registry *Registry) expire() {
+	for name, n := range registry.nodes {
+		if time.Since(n.lastSeen) < registry.expiry {
+			continue
+		}
+		delete(registry.nodes, name)
+		if err := n.conn.Close(); err != nil {
+			log.Println("close connection to node", name, ":", err)
+		}
+		log.Println("removed node", name, "at", n.address, "after missing heartbeats since", n.lastSeen.Format(time.RFC3339))
+	}
+}
+
+// update changes the node info until the node sends its next heartbeat.
+func (registry *Registry) update(name string, fn func(info *api.Node)) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	n, ok := registry.nodes[name]
+	if !ok {
+		return
+	}
+	info := proto.Clone(n.info).(*api.Node)
+	if info.Allocated == nil {
+		info.Allocated = &api.Node_Resources{}
+	}
+	fn(info)
+	n.info = info
+}
+
+// Allocate adds the specs to the resources allocated on the node.
+func (registry *Registry) Allocate(name string, specs *api.Machine_Specs) {
+	registry.update(name, func(info *api.Node) {
+		info.Allocated.Cpus += specs.GetCpus()
+		info.Allocated.Memory += specs.GetMemory()
+		info.Allocated.Disk += specs.GetDisk()
+	})
+}
+
+// Release removes the specs from the resources allocated on the node.
+func (registry *Registry) Release(name string, specs *api.Machine_Specs) {
+	registry.update(name, func(info *api.Node) {
+		info.Allocated.Cpus -= specs.GetCpus()
+		info.Allocated.Memory -= specs.GetMemory()
+		info.Allocated.Disk -= specs.GetDisk()
+	})
+}
+
+// SetCordoned marks the node as cordoned.
+func (registry *Registry) SetCordoned(name string, cordoned bool) {
+	registry.update(name, func(info *api.Node) {
+		info.Cordoned = cordoned
+	})
+}
+
+func (registry *Registry) ready(n *node) bool {
+	return time.Since(n.lastSeen) < registry.timeout
+}
+
+// Ready returns all nodes with a recent heartbeat ordered by name.
+func (registry *Registry) Ready() []*node {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	nodes := make([]*node, 0, len(registry.nodes))
+	for _, n := range registry.nodes {
+		if registry.ready(n) {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].name < nodes[j].name })
+	return nodes
+}
+
+// Get returns the node with the given name if it is ready.
+func (registry *Registry) Get(name string) (*node, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	n, ok := registry.nodes[name]
+	if !ok || !registry.ready(n) {
+		return nil, false
+	}
+	return n, true
+}
+
+// List returns a snapshot of all nodes including unreachable ones ordered by name.
+// Expired nodes are removed first.
+func (registry *Registry) List() []*api.Node {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.expire()
+	nodes := make([]*api.Node, 0, len(registry.nodes))
+	for _, n := range registry.nodes {
+		info := proto.Clone(n.info).(*api.Node)
+		info.LastHeartbeat = timestamppb.New(n.lastSeen)
+		info.Status = api.Node_READY
+		if !registry.ready(n) {
+			info.Status = api.Node_UNREACHABLE
+		}
+		nodes = append(nodes, info)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes
+}
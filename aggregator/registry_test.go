@@ -0,0 +1,41 @@
+package aggregator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lnsp/sox/api"
+	"google.golang.org/grpc"
+)
+
+func TestRegistryExpiry(t *testing.T) {
+	registry := NewRegistry(time.Minute, time.Hour, grpc.WithInsecure())
+	if err := registry.Register(&api.Node{Name: "node-1", Address: "10.0.0.1:9876"}); err != nil {
+		t.Fatalf("register node-1: %v", err)
+	}
+	if err := registry.Register(&api.Node{Name: "node-1", Address: "10.0.0.2:9876"}); !errors.Is(err, errAddressChanged) {
+		t.Fatalf("register node-1 at another address = %v, want %v", err, errAddressChanged)
+	}
+
+	// Unreachable nodes are still listed and keep their address
+	registry.nodes["node-1"].lastSeen = time.Now().Add(-2 * time.Minute)
+	if nodes := registry.List(); len(nodes) != 1 || nodes[0].Status != api.Node_UNREACHABLE {
+		t.Fatalf("List() = %v, want node-1 unreachable", nodes)
+	}
+	if err := registry.Register(&api.Node{Name: "node-1", Address: "10.0.0.2:9876"}); !errors.Is(err, errAddressChanged) {
+		t.Fatalf("register unreachable node-1 at another address = %v, want %v", err, errAddressChanged)
+	}
+
+	// Expired nodes are removed and their name may register again
+	registry.nodes["node-1"].lastSeen = time.Now().Add(-2 * time.Hour)
+	if nodes := registry.List(); len(nodes) != 0 {
+		t.Fatalf("List() = %v, want no nodes", nodes)
+	}
+	if err := registry.Register(&api.Node{Name: "node-1", Address: "10.0.0.2:9876"}); err != nil {
+		t.Fatalf("register expired node-1 at another address: %v", err)
+	}
+	if n, ok := registry.Get("node-1"); !ok || n.address != "10.0.0.2:9876" {
+		t.Errorf("Get(node-1) = %v, want node at 10.0.0.2:9876", n)
+	}
+}
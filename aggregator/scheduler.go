@@ -0,0 +1,215 @@
+package aggregator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lnsp/sox/api"
+)
+
+// Placement policies supported by the scheduler.
+const (
+	// PolicySpread places machines on the node with the most free resources.
+	PolicySpread = "spread"
+	// PolicyPack fills up nodes before using the next one.
+	PolicyPack = "pack"
+)
+
+// Filter rules out nodes which can not run the requested machine.
+type Filter interface {
+	// Filter returns a reason if the node does not fit the request.
+	Filter(node *api.Node, request *api.CreateMachineRequest) error
+}
+
+// Weigher scores nodes which passed all filters, higher scores win.
+type Weigher interface {
+	// Weigh returns a score between 0 and 1.
+	Weigh(node *api.Node, request *api.CreateMachineRequest) float64
+}
+
+// WeightedWeigher scales the score of a weigher, negative multipliers invert its preference.
+type WeightedWeigher struct {
+	Weigher    Weigher
+	Multiplier float64
+}
+
+// Scheduler picks the node a new machine is placed on.
+type Scheduler struct {
+	Filters  []Filter
+	Weighers []WeightedWeigher
+}
+
+// NewScheduler creates a scheduler with the default filters and the weighers of the given policy.
+func NewScheduler(policy string) (*Scheduler, error) {
+	multiplier := 1.0
+	switch policy {
+	case PolicySpread, "":
+	case PolicyPack:
+		multiplier = -1.0
+	default:
+		return nil, fmt.Errorf("unknown placement policy %q", policy)
+	}
+	return &Scheduler{
+		Filters: []Filter{
+			CordonFilter{},
+			NodeFilter{},
+			LabelFilter{},
+			ResourceFilter{},
+			ImageFilter{},
+			NetworkFilter{},
+		},
+		Weighers: []WeightedWeigher{
+			{Weigher: FreeResourceWeigher{}, Multiplier: multiplier},
+		},
+	}, nil
+}
+
+// Schedule returns the best node for the request. Ties are broken by node order.
+func (scheduler *Scheduler) Schedule(nodes []*api.Node, request *api.CreateMachineRequest) (*api.Node, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no node is ready")
+	}
+	var (
+		best      *api.Node
+		bestScore float64
+		reasons   []string
+	)
+	for _, node := range nodes {
+		if err := scheduler.filter(node, request); err != nil {
+			reasons = append(reasons, fmt.Sprintf("%s: %v", node.Name, err))
+			continue
+		}
+		score := scheduler.weigh(node, request)
+		if best == nil || score > bestScore {
+			best, bestScore = node, score
+		}
+	}
+	if best == nil {
+		sort.Strings(reasons)
+		return nil, fmt.Errorf("no node fits the machine (%s)", strings.Join(reasons, "; "))
+	}
+	return best, nil
+}
+
+func (scheduler *Scheduler) filter(node *api.Node, request *api.CreateMachineRequest) error {
+	for _, filter := range scheduler.Filters {
+		if err := filter.Filter(node, request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (scheduler *Scheduler) weigh(node *api.Node, request *api.CreateMachineRequest) float64 {
+	var score float64
+	for _, weigher := range scheduler.Weighers {
+		score += weigher.Multiplier * weigher.Weigher.Weigh(node, request)
+	}
+	return score
+}
+
+// free returns the resources left on the node.
+func free(node *api.Node) (cpus, memory, disk int64) {
+	capacity, allocated := node.GetCapacity(), node.GetAllocated()
+	return capacity.GetCpus() - allocated.GetCpus(),
+		capacity.GetMemory() - allocated.GetMemory(),
+		capacity.GetDisk() - allocated.GetDisk()
+}
+
+// CordonFilter rules out cordoned nodes.
+type CordonFilter struct{}
+
+func (CordonFilter) Filter(node *api.Node, request *api.CreateMachineRequest) error {
+	if node.Cordoned {
+		return fmt.Errorf("node is cordoned")
+	}
+	return nil
+}
+
+// NodeFilter only passes the node requested by name.
+type NodeFilter struct{}
+
+func (NodeFilter) Filter(node *api.Node, request *api.CreateMachineRequest) error {
+	if request.Node != "" && request.Node != node.Name {
+		return fmt.Errorf("not requested node")
+	}
+	return nil
+}
+
+// LabelFilter only passes nodes carrying all requested labels.
+type LabelFilter struct{}
+
+func (LabelFilter) Filter(node *api.Node, request *api.CreateMachineRequest) error {
+	for key, value := range request.NodeLabels {
+		if node.Labels[key] != value {
+			return fmt.Errorf("label %s=%s missing", key, value)
+		}
+	}
+	return nil
+}
+
+// ResourceFilter only passes nodes with enough free vCPUs, memory and storage pool space.
+type ResourceFilter struct{}
+
+func (ResourceFilter) Filter(node *api.Node, request *api.CreateMachineRequest) error {
+	specs := request.GetSpecs()
+	cpus, memory, disk := free(node)
+	if specs.GetCpus() > cpus {
+		return fmt.Errorf("not enough vcpus (%d free, %d requested)", cpus, specs.GetCpus())
+	}
+	if specs.GetMemory() > memory {
+		return fmt.Errorf("not enough memory (%d MB free, %d MB requested)", memory, specs.GetMemory())
+	}
+	if specs.GetDisk() > disk {
+		return fmt.Errorf("not enough disk space (%d GB free, %d GB requested)", disk, specs.GetDisk())
+	}
+	return nil
+}
+
+// ImageFilter only passes nodes which have the requested image.
+type ImageFilter struct{}
+
+func (ImageFilter) Filter(node *api.Node, request *api.CreateMachineRequest) error {
+	if request.ImageId == "" || contains(node.ImageIds, request.ImageId) {
+		return nil
+	}
+	return fmt.Errorf("image %s not available", request.ImageId)
+}
+
+// NetworkFilter only passes nodes which have all requested networks.
+type NetworkFilter struct{}
+
+func (NetworkFilter) Filter(node *api.Node, request *api.CreateMachineRequest) error {
+	for _, id := range request.NetworkIds {
+		if !contains(node.NetworkIds, id) {
+			return fmt.Errorf("network %s not available", id)
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// FreeResourceWeigher prefers nodes with a high share of vCPUs and memory left after placing the machine.
+type FreeResourceWeigher struct{}
+
+func (FreeResourceWeigher) Weigh(node *api.Node, request *api.CreateMachineRequest) float64 {
+	cpus, memory, _ := free(node)
+	specs, capacity := request.GetSpecs(), node.GetCapacity()
+	return (share(cpus-specs.GetCpus(), capacity.GetCpus()) + share(memory-specs.GetMemory(), capacity.GetMemory())) / 2
+}
+
+func share(value, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(value) / float64(total)
+}
@@ -0,0 +1,183 @@
+package aggregator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lnsp/sox/api"
+)
+
+// testNode returns a node with the given capacity and allocation, the debian image and the default network.
+func testNode(name string, cpus, memory, disk, allocatedCPUs, allocatedMemory int64) *api.Node {
+	return &api.Node{
+		Name:       name,
+		Capacity:   &api.Node_Resources{Cpus: cpus, Memory: memory, Disk: disk},
+		Allocated:  &api.Node_Resources{Cpus: allocatedCPUs, Memory: allocatedMemory},
+		ImageIds:   []string{"debian"},
+		NetworkIds: []string{"default"},
+	}
+}
+
+func testRequest(cpus, memory, disk int64) *api.CreateMachineRequest {
+	return &api.CreateMachineRequest{
+		Name:       "machine",
+		Specs:      &api.Machine_Specs{Cpus: cpus, Memory: memory, Disk: disk},
+		ImageId:    "debian",
+		NetworkIds: []string{"default"},
+	}
+}
+
+func TestFilters(t *testing.T) {
+	labelled := testNode("node", 4, 4096, 100, 0, 0)
+	labelled.Labels = map[string]string{"zone": "a", "gpu": "true"}
+	cordoned := testNode("node", 4, 4096, 100, 0, 0)
+	cordoned.Cordoned = true
+	withNode := testRequest(1, 1024, 10)
+	withNode.Node = "other"
+	withLabels := testRequest(1, 1024, 10)
+	withLabels.NodeLabels = map[string]string{"zone": "a", "gpu": "true"}
+	withMissingLabel := testRequest(1, 1024, 10)
+	withMissingLabel.NodeLabels = map[string]string{"zone": "b"}
+	withImage := testRequest(1, 1024, 10)
+	withImage.ImageId = "ubuntu"
+	withoutImage := testRequest(1, 1024, 10)
+	withoutImage.ImageId = ""
+	withNetwork := testRequest(1, 1024, 10)
+	withNetwork.NetworkIds = []string{"default", "private"}
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		node    *api.Node
+		request *api.CreateMachineRequest
+		reason  string
+	}{
+		{"cordon passes", CordonFilter{}, labelled, testRequest(1, 1024, 10), ""},
+		{"cordon rejects", CordonFilter{}, cordoned, testRequest(1, 1024, 10), "node is cordoned"},
+		{"node passes without hint", NodeFilter{}, labelled, testRequest(1, 1024, 10), ""},
+		{"node rejects other", NodeFilter{}, labelled, withNode, "not requested node"},
+		{"label passes", LabelFilter{}, labelled, withLabels, ""},
+		{"label rejects", LabelFilter{}, labelled, withMissingLabel, "label zone=b missing"},
+		{"label rejects unlabelled", LabelFilter{}, cordoned, withLabels, "missing"},
+		{"resource passes exact fit", ResourceFilter{}, testNode("node", 4, 4096, 100, 2, 2048), testRequest(2, 2048, 100), ""},
+		{"resource rejects vcpus", ResourceFilter{}, testNode("node", 4, 4096, 100, 3, 0), testRequest(2, 1024, 10), "not enough vcpus (1 free, 2 requested)"},
+		{"resource rejects memory", ResourceFilter{}, testNode("node", 4, 4096, 100, 0, 4000), testRequest(1, 1024, 10), "not enough memory (96 MB free, 1024 MB requested)"},
+		{"resource rejects disk", ResourceFilter{}, testNode("node", 4, 4096, 5, 0, 0), testRequest(1, 1024, 10), "not enough disk space (5 GB free, 10 GB requested)"},
+		{"image passes", ImageFilter{}, labelled, testRequest(1, 1024, 10), ""},
+		{"image passes without image", ImageFilter{}, labelled, withoutImage, ""},
+		{"image rejects", ImageFilter{}, labelled, withImage, "image ubuntu not available"},
+		{"network passes", NetworkFilter{}, labelled, testRequest(1, 1024, 10), ""},
+		{"network rejects", NetworkFilter{}, labelled, withNetwork, "network private not available"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.filter.Filter(test.node, test.request)
+			switch {
+			case test.reason == "" && err != nil:
+				t.Errorf("Filter() = %v, want node to pass", err)
+			case test.reason != "" && err == nil:
+				t.Errorf("Filter() passed node, want %q", test.reason)
+			case test.reason != "" && !strings.Contains(err.Error(), test.reason):
+				t.Errorf("Filter() = %v, want %q", err, test.reason)
+			}
+		})
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	// Node a has the most free resources, node c the least
+	nodes := func() []*api.Node {
+		return []*api.Node{
+			testNode("a", 8, 8192, 100, 1, 1024),
+			testNode("b", 8, 8192, 100, 4, 4096),
+			testNode("c", 8, 8192, 100, 6, 6144),
+		}
+	}
+	labelled := nodes()
+	labelled[1].Labels = map[string]string{"zone": "b"}
+	cordoned := nodes()
+	cordoned[0].Cordoned = true
+	withNode := testRequest(1, 1024, 10)
+	withNode.Node = "c"
+	withLabels := testRequest(1, 1024, 10)
+	withLabels.NodeLabels = map[string]string{"zone": "b"}
+	withUnknownNode := testRequest(1, 1024, 10)
+	withUnknownNode.Node = "d"
+
+	tests := []struct {
+		name    string
+		policy  string
+		nodes   []*api.Node
+		request *api.CreateMachineRequest
+		want    string
+		reasons []string
+	}{
+		{name: "spread picks most free", policy: PolicySpread, nodes: nodes(), request: testRequest(1, 1024, 10), want: "a"},
+		{name: "default policy spreads", policy: "", nodes: nodes(), request: testRequest(1, 1024, 10), want: "a"},
+		{name: "pack picks least free", policy: PolicyPack, nodes: nodes(), request: testRequest(1, 1024, 10), want: "c"},
+		{name: "pack skips full node", policy: PolicyPack, nodes: nodes(), request: testRequest(3, 1024, 10), want: "b"},
+		{name: "spread ties broken by order", policy: PolicySpread, nodes: []*api.Node{testNode("x", 4, 4096, 100, 0, 0), testNode("y", 4, 4096, 100, 0, 0)}, request: testRequest(1, 1024, 10), want: "x"},
+		{name: "spread skips cordoned", policy: PolicySpread, nodes: cordoned, request: testRequest(1, 1024, 10), want: "b"},
+		{name: "node hint overrides policy", policy: PolicySpread, nodes: nodes(), request: withNode, want: "c"},
+		{name: "labels constrain nodes", policy: PolicySpread, nodes: labelled, request: withLabels, want: "b"},
+		{
+			name:    "no node fits resources",
+			policy:  PolicySpread,
+			nodes:   nodes(),
+			request: testRequest(8, 1024, 10),
+			reasons: []string{"a: not enough vcpus (7 free, 8 requested)", "b: not enough vcpus", "c: not enough vcpus"},
+		},
+		{
+			name:    "no node has the label",
+			policy:  PolicyPack,
+			nodes:   nodes(),
+			request: withLabels,
+			reasons: []string{"a: label zone=b missing", "b: label zone=b missing", "c: label zone=b missing"},
+		},
+		{
+			name:    "unknown node hint",
+			policy:  PolicySpread,
+			nodes:   nodes(),
+			request: withUnknownNode,
+			reasons: []string{"a: not requested node", "b: not requested node", "c: not requested node"},
+		},
+		{
+			name:    "no node is ready",
+			policy:  PolicySpread,
+			request: testRequest(1, 1024, 10),
+			reasons: []string{"no node is ready"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scheduler, err := NewScheduler(test.policy)
+			if err != nil {
+				t.Fatalf("NewScheduler(%q) = %v", test.policy, err)
+			}
+			node, err := scheduler.Schedule(test.nodes, test.request)
+			if test.reasons != nil {
+				if err == nil {
+					t.Fatalf("Schedule() = %s, want error", node.Name)
+				}
+				for _, reason := range test.reasons {
+					if !strings.Contains(err.Error(), reason) {
+						t.Errorf("Schedule() = %v, want reason %q", err, reason)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Schedule() = %v, want %s", err, test.want)
+			}
+			if node.Name != test.want {
+				t.Errorf("Schedule() = %s, want %s", node.Name, test.want)
+			}
+		})
+	}
+}
+
+func TestNewSchedulerUnknownPolicy(t *testing.T) {
+	if _, err := NewScheduler("random"); err == nil {
+		t.Error("NewScheduler(random) succeeded, want error")
+	}
+}
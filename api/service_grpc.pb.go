@@ -30,6 +30,65 @@ type SoxClient interface {
 	ListNetworks(ctx context.Context, in *ListNetworksRequest, opts ...grpc.CallOption) (*ListNetworksResponse, error)
 	CreateNetwork(ctx context.Context, in *CreateNetworkRequest, opts ...grpc.CallOption) (*CreateNetworkResponse, error)
 	ListActivities(ctx context.Context, in *ListActivitiesRequest, opts ...grpc.CallOption) (*ListActivitiesResponse, error)
+	CreateSecurityGroup(ctx context.Context, in *CreateSecurityGroupRequest, opts ...grpc.CallOption) (*CreateSecurityGroupResponse, error)
+	ListSecurityGroups(ctx context.Context, in *ListSecurityGroupsRequest, opts ...grpc.CallOption) (*ListSecurityGroupsResponse, error)
+	UpdateSecurityGroup(ctx context.Context, in *UpdateSecurityGroupRequest, opts ...grpc.CallOption) (*UpdateSecurityGroupResponse, error)
+	DeleteSecurityGroup(ctx context.Context, in *DeleteSecurityGroupRequest, opts ...grpc.CallOption) (*DeleteSecurityGroupResponse, error)
+	AttachSecurityGroup(ctx context.Context, in *AttachSecurityGroupRequest, opts ...grpc.CallOption) (*AttachSecurityGroupResponse, error)
+	DetachSecurityGroup(ctx context.Context, in *DetachSecurityGroupRequest, opts ...grpc.CallOption) (*DetachSecurityGroupResponse, error)
+	CreatePortForward(ctx context.Context, in *CreatePortForwardRequest, opts ...grpc.CallOption) (*CreatePortForwardResponse, error)
+	ListPortForwards(ctx context.Context, in *ListPortForwardsRequest, opts ...grpc.CallOption) (*ListPortForwardsResponse, error)
+	DeletePortForward(ctx context.Context, in *DeletePortForwardRequest, opts ...grpc.CallOption) (*DeletePortForwardResponse, error)
+	AllocateFloatingIP(ctx context.Context, in *AllocateFloatingIPRequest, opts ...grpc.CallOption) (*AllocateFloatingIPResponse, error)
+	ListFloatingIPs(ctx context.Context, in *ListFloatingIPsRequest, opts ...grpc.CallOption) (*ListFloatingIPsResponse, error)
+	AssociateFloatingIP(ctx context.Context, in *AssociateFloatingIPRequest, opts ...grpc.CallOption) (*AssociateFloatingIPResponse, error)
+	ReleaseFloatingIP(ctx context.Context, in *ReleaseFloatingIPRequest, opts ...grpc.CallOption) (*ReleaseFloatingIPResponse, error)
+	CreateVPNPeer(ctx context.Context, in *CreateVPNPeerRequest, opts ...grpc.CallOption) (*CreateVPNPeerResponse, error)
+	ListVPNPeers(ctx context.Context, in *ListVPNPeersRequest, opts ...grpc.CallOption) (*ListVPNPeersResponse, error)
+	DeleteVPNPeer(ctx context.Context, in *DeleteVPNPeerRequest, opts ...grpc.CallOption) (*DeleteVPNPeerResponse, error)
+	AttachNetworkInterface(ctx context.Context, in *AttachNetworkInterfaceRequest, opts ...grpc.CallOption) (*AttachNetworkInterfaceResponse, error)
+	DetachNetworkInterface(ctx context.Context, in *DetachNetworkInterfaceRequest, opts ...grpc.CallOption) (*DetachNetworkInterfaceResponse, error)
+	UpdateNetworkInterfaceQoS(ctx context.Context, in *UpdateNetworkInterfaceQoSRequest, opts ...grpc.CallOption) (*UpdateNetworkInterfaceQoSResponse, error)
+	RegisterNode(ctx context.Context, in *RegisterNodeRequest, opts ...grpc.CallOption) (*RegisterNodeResponse, error)
+	ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error)
+	CordonNode(ctx context.Context, in *CordonNodeRequest, opts ...grpc.CallOption) (*CordonNodeResponse, error)
+	UncordonNode(ctx context.Context, in *UncordonNodeRequest, opts ...grpc.CallOption) (*UncordonNodeResponse, error)
+	DrainNode(ctx context.Context, in *DrainNodeRequest, opts ...grpc.CallOption) (*DrainNodeResponse, error)
+	Rebalance(ctx context.Context, in *RebalanceRequest, opts ...grpc.CallOption) (*RebalanceResponse, error)
+	MigrateMachine(ctx context.Context, in *MigrateMachineRequest, opts ...grpc.CallOption) (*MigrateMachineResponse, error)
+	// Used between nodes to move machines.
+	ExportMachine(ctx context.Context, in *ExportMachineRequest, opts ...grpc.CallOption) (*ExportMachineResponse, error)
+	ReadMachineDisk(ctx context.Context, in *ReadMachineDiskRequest, opts ...grpc.CallOption) (Sox_ReadMachineDiskClient, error)
+	ImportMachine(ctx context.Context, in *ImportMachineRequest, opts ...grpc.CallOption) (*ImportMachineResponse, error)
+	ReadImage(ctx context.Context, in *ReadImageRequest, opts ...grpc.CallOption) (Sox_ReadImageClient, error)
+	SendMachine(ctx context.Context, in *SendMachineRequest, opts ...grpc.CallOption) (*SendMachineResponse, error)
+	AbortImport(ctx context.Context, in *AbortImportRequest, opts ...grpc.CallOption) (*AbortImportResponse, error)
+	FlushForwarding(ctx context.Context, in *FlushForwardingRequest, opts ...grpc.CallOption) (*FlushForwardingResponse, error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error)
+	CreateProject(ctx context.Context, in *CreateProjectRequest, opts ...grpc.CallOption) (*CreateProjectResponse, error)
+	ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error)
+	DeleteProject(ctx context.Context, in *DeleteProjectRequest, opts ...grpc.CallOption) (*DeleteProjectResponse, error)
+	ListMemberships(ctx context.Context, in *ListMembershipsRequest, opts ...grpc.CallOption) (*ListMembershipsResponse, error)
+	SetMembership(ctx context.Context, in *SetMembershipRequest, opts ...grpc.CallOption) (*SetMembershipResponse, error)
+	RemoveMembership(ctx context.Context, in *RemoveMembershipRequest, opts ...grpc.CallOption) (*RemoveMembershipResponse, error)
+	GetCurrentUser(ctx context.Context, in *GetCurrentUserRequest, opts ...grpc.CallOption) (*GetCurrentUserResponse, error)
+	CreateToken(ctx context.Context, in *CreateTokenRequest, opts ...grpc.CallOption) (*CreateTokenResponse, error)
+	ListTokens(ctx context.Context, in *ListTokensRequest, opts ...grpc.CallOption) (*ListTokensResponse, error)
+	DeleteToken(ctx context.Context, in *DeleteTokenRequest, opts ...grpc.CallOption) (*DeleteTokenResponse, error)
+	GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*GetQuotaUsageResponse, error)
+	SetQuota(ctx context.Context, in *SetQuotaRequest, opts ...grpc.CallOption) (*SetQuotaResponse, error)
+	// Used by aggregators to keep users, projects, memberships, quotas and tokens the same on all nodes.
+	ExportIdentity(ctx context.Context, in *ExportIdentityRequest, opts ...grpc.CallOption) (*ExportIdentityResponse, error)
+	ImportIdentity(ctx context.Context, in *ImportIdentityRequest, opts ...grpc.CallOption) (*ImportIdentityResponse, error)
+	GetHostInfo(ctx context.Context, in *GetHostInfoRequest, opts ...grpc.CallOption) (*GetHostInfoResponse, error)
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (Sox_WatchEventsClient, error)
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	GetMachineStats(ctx context.Context, in *GetMachineStatsRequest, opts ...grpc.CallOption) (*GetMachineStatsResponse, error)
 }
 
 type soxClient struct {
@@ -148,6 +207,588 @@ func (c *soxClient) ListActivities(ctx context.Context, in *ListActivitiesReques
 	return out, nil
 }
 
+func (c *soxClient) CreateSecurityGroup(ctx context.Context, in *CreateSecurityGroupRequest, opts ...grpc.CallOption) (*CreateSecurityGroupResponse, error) {
+	out := new(CreateSecurityGroupResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/CreateSecurityGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ListSecurityGroups(ctx context.Context, in *ListSecurityGroupsRequest, opts ...grpc.CallOption) (*ListSecurityGroupsResponse, error) {
+	out := new(ListSecurityGroupsResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ListSecurityGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) UpdateSecurityGroup(ctx context.Context, in *UpdateSecurityGroupRequest, opts ...grpc.CallOption) (*UpdateSecurityGroupResponse, error) {
+	out := new(UpdateSecurityGroupResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/UpdateSecurityGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) DeleteSecurityGroup(ctx context.Context, in *DeleteSecurityGroupRequest, opts ...grpc.CallOption) (*DeleteSecurityGroupResponse, error) {
+	out := new(DeleteSecurityGroupResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/DeleteSecurityGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) AttachSecurityGroup(ctx context.Context, in *AttachSecurityGroupRequest, opts ...grpc.CallOption) (*AttachSecurityGroupResponse, error) {
+	out := new(AttachSecurityGroupResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/AttachSecurityGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) DetachSecurityGroup(ctx context.Context, in *DetachSecurityGroupRequest, opts ...grpc.CallOption) (*DetachSecurityGroupResponse, error) {
+	out := new(DetachSecurityGroupResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/DetachSecurityGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) CreatePortForward(ctx context.Context, in *CreatePortForwardRequest, opts ...grpc.CallOption) (*CreatePortForwardResponse, error) {
+	out := new(CreatePortForwardResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/CreatePortForward", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ListPortForwards(ctx context.Context, in *ListPortForwardsRequest, opts ...grpc.CallOption) (*ListPortForwardsResponse, error) {
+	out := new(ListPortForwardsResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ListPortForwards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) DeletePortForward(ctx context.Context, in *DeletePortForwardRequest, opts ...grpc.CallOption) (*DeletePortForwardResponse, error) {
+	out := new(DeletePortForwardResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/DeletePortForward", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) AllocateFloatingIP(ctx context.Context, in *AllocateFloatingIPRequest, opts ...grpc.CallOption) (*AllocateFloatingIPResponse, error) {
+	out := new(AllocateFloatingIPResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/AllocateFloatingIP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ListFloatingIPs(ctx context.Context, in *ListFloatingIPsRequest, opts ...grpc.CallOption) (*ListFloatingIPsResponse, error) {
+	out := new(ListFloatingIPsResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ListFloatingIPs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) AssociateFloatingIP(ctx context.Context, in *AssociateFloatingIPRequest, opts ...grpc.CallOption) (*AssociateFloatingIPResponse, error) {
+	out := new(AssociateFloatingIPResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/AssociateFloatingIP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ReleaseFloatingIP(ctx context.Context, in *ReleaseFloatingIPRequest, opts ...grpc.CallOption) (*ReleaseFloatingIPResponse, error) {
+	out := new(ReleaseFloatingIPResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ReleaseFloatingIP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) CreateVPNPeer(ctx context.Context, in *CreateVPNPeerRequest, opts ...grpc.CallOption) (*CreateVPNPeerResponse, error) {
+	out := new(CreateVPNPeerResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/CreateVPNPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ListVPNPeers(ctx context.Context, in *ListVPNPeersRequest, opts ...grpc.CallOption) (*ListVPNPeersResponse, error) {
+	out := new(ListVPNPeersResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ListVPNPeers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) DeleteVPNPeer(ctx context.Context, in *DeleteVPNPeerRequest, opts ...grpc.CallOption) (*DeleteVPNPeerResponse, error) {
+	out := new(DeleteVPNPeerResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/DeleteVPNPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) AttachNetworkInterface(ctx context.Context, in *AttachNetworkInterfaceRequest, opts ...grpc.CallOption) (*AttachNetworkInterfaceResponse, error) {
+	out := new(AttachNetworkInterfaceResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/AttachNetworkInterface", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) DetachNetworkInterface(ctx context.Context, in *DetachNetworkInterfaceRequest, opts ...grpc.CallOption) (*DetachNetworkInterfaceResponse, error) {
+	out := new(DetachNetworkInterfaceResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/DetachNetworkInterface", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) UpdateNetworkInterfaceQoS(ctx context.Context, in *UpdateNetworkInterfaceQoSRequest, opts ...grpc.CallOption) (*UpdateNetworkInterfaceQoSResponse, error) {
+	out := new(UpdateNetworkInterfaceQoSResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/UpdateNetworkInterfaceQoS", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) RegisterNode(ctx context.Context, in *RegisterNodeRequest, opts ...grpc.CallOption) (*RegisterNodeResponse, error) {
+	out := new(RegisterNodeResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/RegisterNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error) {
+	out := new(ListNodesResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ListNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) CordonNode(ctx context.Context, in *CordonNodeRequest, opts ...grpc.CallOption) (*CordonNodeResponse, error) {
+	out := new(CordonNodeResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/CordonNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) UncordonNode(ctx context.Context, in *UncordonNodeRequest, opts ...grpc.CallOption) (*UncordonNodeResponse, error) {
+	out := new(UncordonNodeResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/UncordonNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) DrainNode(ctx context.Context, in *DrainNodeRequest, opts ...grpc.CallOption) (*DrainNodeResponse, error) {
+	out := new(DrainNodeResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/DrainNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) Rebalance(ctx context.Context, in *RebalanceRequest, opts ...grpc.CallOption) (*RebalanceResponse, error) {
+	out := new(RebalanceResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/Rebalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) MigrateMachine(ctx context.Context, in *MigrateMachineRequest, opts ...grpc.CallOption) (*MigrateMachineResponse, error) {
+	out := new(MigrateMachineResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/MigrateMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ExportMachine(ctx context.Context, in *ExportMachineRequest, opts ...grpc.CallOption) (*ExportMachineResponse, error) {
+	out := new(ExportMachineResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ExportMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ReadMachineDisk(ctx context.Context, in *ReadMachineDiskRequest, opts ...grpc.CallOption) (Sox_ReadMachineDiskClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Sox_ServiceDesc.Streams[0], "/sox.v1.Sox/ReadMachineDisk", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &soxReadMachineDiskClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Sox_ReadMachineDiskClient interface {
+	Recv() (*ReadMachineDiskResponse, error)
+	grpc.ClientStream
+}
+
+type soxReadMachineDiskClient struct {
+	grpc.ClientStream
+}
+
+func (x *soxReadMachineDiskClient) Recv() (*ReadMachineDiskResponse, error) {
+	m := new(ReadMachineDiskResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *soxClient) ImportMachine(ctx context.Context, in *ImportMachineRequest, opts ...grpc.CallOption) (*ImportMachineResponse, error) {
+	out := new(ImportMachineResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ImportMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ReadImage(ctx context.Context, in *ReadImageRequest, opts ...grpc.CallOption) (Sox_ReadImageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Sox_ServiceDesc.Streams[1], "/sox.v1.Sox/ReadImage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &soxReadImageClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Sox_ReadImageClient interface {
+	Recv() (*ReadImageResponse, error)
+	grpc.ClientStream
+}
+
+type soxReadImageClient struct {
+	grpc.ClientStream
+}
+
+func (x *soxReadImageClient) Recv() (*ReadImageResponse, error) {
+	m := new(ReadImageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *soxClient) SendMachine(ctx context.Context, in *SendMachineRequest, opts ...grpc.CallOption) (*SendMachineResponse, error) {
+	out := new(SendMachineResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/SendMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) AbortImport(ctx context.Context, in *AbortImportRequest, opts ...grpc.CallOption) (*AbortImportResponse, error) {
+	out := new(AbortImportResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/AbortImport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) FlushForwarding(ctx context.Context, in *FlushForwardingRequest, opts ...grpc.CallOption) (*FlushForwardingResponse, error) {
+	out := new(FlushForwardingResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/FlushForwarding", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error) {
+	out := new(CreateUserResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/CreateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error) {
+	out := new(DeleteUserResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/DeleteUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) CreateProject(ctx context.Context, in *CreateProjectRequest, opts ...grpc.CallOption) (*CreateProjectResponse, error) {
+	out := new(CreateProjectResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/CreateProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error) {
+	out := new(ListProjectsResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ListProjects", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) DeleteProject(ctx context.Context, in *DeleteProjectRequest, opts ...grpc.CallOption) (*DeleteProjectResponse, error) {
+	out := new(DeleteProjectResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/DeleteProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ListMemberships(ctx context.Context, in *ListMembershipsRequest, opts ...grpc.CallOption) (*ListMembershipsResponse, error) {
+	out := new(ListMembershipsResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ListMemberships", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) SetMembership(ctx context.Context, in *SetMembershipRequest, opts ...grpc.CallOption) (*SetMembershipResponse, error) {
+	out := new(SetMembershipResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/SetMembership", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) RemoveMembership(ctx context.Context, in *RemoveMembershipRequest, opts ...grpc.CallOption) (*RemoveMembershipResponse, error) {
+	out := new(RemoveMembershipResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/RemoveMembership", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) GetCurrentUser(ctx context.Context, in *GetCurrentUserRequest, opts ...grpc.CallOption) (*GetCurrentUserResponse, error) {
+	out := new(GetCurrentUserResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/GetCurrentUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) CreateToken(ctx context.Context, in *CreateTokenRequest, opts ...grpc.CallOption) (*CreateTokenResponse, error) {
+	out := new(CreateTokenResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/CreateToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ListTokens(ctx context.Context, in *ListTokensRequest, opts ...grpc.CallOption) (*ListTokensResponse, error) {
+	out := new(ListTokensResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ListTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) DeleteToken(ctx context.Context, in *DeleteTokenRequest, opts ...grpc.CallOption) (*DeleteTokenResponse, error) {
+	out := new(DeleteTokenResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/DeleteToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*GetQuotaUsageResponse, error) {
+	out := new(GetQuotaUsageResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/GetQuotaUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) SetQuota(ctx context.Context, in *SetQuotaRequest, opts ...grpc.CallOption) (*SetQuotaResponse, error) {
+	out := new(SetQuotaResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/SetQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ExportIdentity(ctx context.Context, in *ExportIdentityRequest, opts ...grpc.CallOption) (*ExportIdentityResponse, error) {
+	out := new(ExportIdentityResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ExportIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ImportIdentity(ctx context.Context, in *ImportIdentityRequest, opts ...grpc.CallOption) (*ImportIdentityResponse, error) {
+	out := new(ImportIdentityResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ImportIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) GetHostInfo(ctx context.Context, in *GetHostInfoRequest, opts ...grpc.CallOption) (*GetHostInfoResponse, error) {
+	out := new(GetHostInfoResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/GetHostInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (Sox_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Sox_ServiceDesc.Streams[2], "/sox.v1.Sox/WatchEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &soxWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Sox_WatchEventsClient interface {
+	Recv() (*WatchEventsResponse, error)
+	grpc.ClientStream
+}
+
+type soxWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *soxWatchEventsClient) Recv() (*WatchEventsResponse, error) {
+	m := new(WatchEventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *soxClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error) {
+	out := new(CreateWebhookResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/CreateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ListWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/DeleteWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/ListWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *soxClient) GetMachineStats(ctx context.Context, in *GetMachineStatsRequest, opts ...grpc.CallOption) (*GetMachineStatsResponse, error) {
+	out := new(GetMachineStatsResponse)
+	err := c.cc.Invoke(ctx, "/sox.v1.Sox/GetMachineStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SoxServer is the server API for Sox service.
 // All implementations must embed UnimplementedSoxServer
 // for forward compatibility
@@ -164,274 +805,1539 @@ type SoxServer interface {
 	ListNetworks(context.Context, *ListNetworksRequest) (*ListNetworksResponse, error)
 	CreateNetwork(context.Context, *CreateNetworkRequest) (*CreateNetworkResponse, error)
 	ListActivities(context.Context, *ListActivitiesRequest) (*ListActivitiesResponse, error)
+	CreateSecurityGroup(context.Context, *CreateSecurityGroupRequest) (*CreateSecurityGroupResponse, error)
+	ListSecurityGroups(context.Context, *ListSecurityGroupsRequest) (*ListSecurityGroupsResponse, error)
+	UpdateSecurityGroup(context.Context, *UpdateSecurityGroupRequest) (*UpdateSecurityGroupResponse, error)
+	DeleteSecurityGroup(context.Context, *DeleteSecurityGroupRequest) (*DeleteSecurityGroupResponse, error)
+	AttachSecurityGroup(context.Context, *AttachSecurityGroupRequest) (*AttachSecurityGroupResponse, error)
+	DetachSecurityGroup(context.Context, *DetachSecurityGroupRequest) (*DetachSecurityGroupResponse, error)
+	CreatePortForward(context.Context, *CreatePortForwardRequest) (*CreatePortForwardResponse, error)
+	ListPortForwards(context.Context, *ListPortForwardsRequest) (*ListPortForwardsResponse, error)
+	DeletePortForward(context.Context, *DeletePortForwardRequest) (*DeletePortForwardResponse, error)
+	AllocateFloatingIP(context.Context, *AllocateFloatingIPRequest) (*AllocateFloatingIPResponse, error)
+	ListFloatingIPs(context.Context, *ListFloatingIPsRequest) (*ListFloatingIPsResponse, error)
+	AssociateFloatingIP(context.Context, *AssociateFloatingIPRequest) (*AssociateFloatingIPResponse, error)
+	ReleaseFloatingIP(context.Context, *ReleaseFloatingIPRequest) (*ReleaseFloatingIPResponse, error)
+	CreateVPNPeer(context.Context, *CreateVPNPeerRequest) (*CreateVPNPeerResponse, error)
+	ListVPNPeers(context.Context, *ListVPNPeersRequest) (*ListVPNPeersResponse, error)
+	DeleteVPNPeer(context.Context, *DeleteVPNPeerRequest) (*DeleteVPNPeerResponse, error)
+	AttachNetworkInterface(context.Context, *AttachNetworkInterfaceRequest) (*AttachNetworkInterfaceResponse, error)
+	DetachNetworkInterface(context.Context, *DetachNetworkInterfaceRequest) (*DetachNetworkInterfaceResponse, error)
+	UpdateNetworkInterfaceQoS(context.Context, *UpdateNetworkInterfaceQoSRequest) (*UpdateNetworkInterfaceQoSResponse, error)
+	RegisterNode(context.Context, *RegisterNodeRequest) (*RegisterNodeResponse, error)
+	ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error)
+	CordonNode(context.Context, *CordonNodeRequest) (*CordonNodeResponse, error)
+	UncordonNode(context.Context, *UncordonNodeRequest) (*UncordonNodeResponse, error)
+	DrainNode(context.Context, *DrainNodeRequest) (*DrainNodeResponse, error)
+	Rebalance(context.Context, *RebalanceRequest) (*RebalanceResponse, error)
+	MigrateMachine(context.Context, *MigrateMachineRequest) (*MigrateMachineResponse, error)
+	// Used between nodes to move machines.
+	ExportMachine(context.Context, *ExportMachineRequest) (*ExportMachineResponse, error)
+	ReadMachineDisk(*ReadMachineDiskRequest, Sox_ReadMachineDiskServer) error
+	ImportMachine(context.Context, *ImportMachineRequest) (*ImportMachineResponse, error)
+	ReadImage(*ReadImageRequest, Sox_ReadImageServer) error
+	SendMachine(context.Context, *SendMachineRequest) (*SendMachineResponse, error)
+	AbortImport(context.Context, *AbortImportRequest) (*AbortImportResponse, error)
+	FlushForwarding(context.Context, *FlushForwardingRequest) (*FlushForwardingResponse, error)
+	CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error)
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error)
+	CreateProject(context.Context, *CreateProjectRequest) (*CreateProjectResponse, error)
+	ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error)
+	DeleteProject(context.Context, *DeleteProjectRequest) (*DeleteProjectResponse, error)
+	ListMemberships(context.Context, *ListMembershipsRequest) (*ListMembershipsResponse, error)
+	SetMembership(context.Context, *SetMembershipRequest) (*SetMembershipResponse, error)
+	RemoveMembership(context.Context, *RemoveMembershipRequest) (*RemoveMembershipResponse, error)
+	GetCurrentUser(context.Context, *GetCurrentUserRequest) (*GetCurrentUserResponse, error)
+	CreateToken(context.Context, *CreateTokenRequest) (*CreateTokenResponse, error)
+	ListTokens(context.Context, *ListTokensRequest) (*ListTokensResponse, error)
+	DeleteToken(context.Context, *DeleteTokenRequest) (*DeleteTokenResponse, error)
+	GetQuotaUsage(context.Context, *GetQuotaUsageRequest) (*GetQuotaUsageResponse, error)
+	SetQuota(context.Context, *SetQuotaRequest) (*SetQuotaResponse, error)
+	// Used by aggregators to keep users, projects, memberships, quotas and tokens the same on all nodes.
+	ExportIdentity(context.Context, *ExportIdentityRequest) (*ExportIdentityResponse, error)
+	ImportIdentity(context.Context, *ImportIdentityRequest) (*ImportIdentityResponse, error)
+	GetHostInfo(context.Context, *GetHostInfoRequest) (*GetHostInfoResponse, error)
+	WatchEvents(*WatchEventsRequest, Sox_WatchEventsServer) error
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	GetMachineStats(context.Context, *GetMachineStatsRequest) (*GetMachineStatsResponse, error)
+	mustEmbedUnimplementedSoxServer()
+}
+
+// UnimplementedSoxServer must be embedded to have forward compatible implementations.
+type UnimplementedSoxServer struct {
+}
+
+func (UnimplementedSoxServer) CreateMachine(context.Context, *CreateMachineRequest) (*CreateMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMachine not implemented")
+}
+func (UnimplementedSoxServer) ListMachines(context.Context, *ListMachinesRequest) (*ListMachinesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMachines not implemented")
+}
+func (UnimplementedSoxServer) GetMachineDetails(context.Context, *GetMachineDetailsRequest) (*GetMachineDetailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMachineDetails not implemented")
+}
+func (UnimplementedSoxServer) DeleteMachine(context.Context, *DeleteMachineRequest) (*DeleteMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMachine not implemented")
+}
+func (UnimplementedSoxServer) TriggerMachine(context.Context, *TriggerMachineRequest) (*TriggerMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerMachine not implemented")
+}
+func (UnimplementedSoxServer) CreateSSHKey(context.Context, *CreateSSHKeyRequest) (*CreateSSHKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSSHKey not implemented")
+}
+func (UnimplementedSoxServer) ListSSHKeys(context.Context, *ListSSHKeysRequest) (*ListSSHKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSSHKeys not implemented")
+}
+func (UnimplementedSoxServer) DeleteSSHKey(context.Context, *DeleteSSHKeyRequest) (*DeleteSSHKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSSHKey not implemented")
+}
+func (UnimplementedSoxServer) ListImages(context.Context, *ListImagesRequest) (*ListImagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListImages not implemented")
+}
+func (UnimplementedSoxServer) ListNetworks(context.Context, *ListNetworksRequest) (*ListNetworksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNetworks not implemented")
+}
+func (UnimplementedSoxServer) CreateNetwork(context.Context, *CreateNetworkRequest) (*CreateNetworkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNetwork not implemented")
+}
+func (UnimplementedSoxServer) ListActivities(context.Context, *ListActivitiesRequest) (*ListActivitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActivities not implemented")
+}
+func (UnimplementedSoxServer) CreateSecurityGroup(context.Context, *CreateSecurityGroupRequest) (*CreateSecurityGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSecurityGroup not implemented")
+}
+func (UnimplementedSoxServer) ListSecurityGroups(context.Context, *ListSecurityGroupsRequest) (*ListSecurityGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSecurityGroups not implemented")
+}
+func (UnimplementedSoxServer) UpdateSecurityGroup(context.Context, *UpdateSecurityGroupRequest) (*UpdateSecurityGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSecurityGroup not implemented")
+}
+func (UnimplementedSoxServer) DeleteSecurityGroup(context.Context, *DeleteSecurityGroupRequest) (*DeleteSecurityGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSecurityGroup not implemented")
+}
+func (UnimplementedSoxServer) AttachSecurityGroup(context.Context, *AttachSecurityGroupRequest) (*AttachSecurityGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AttachSecurityGroup not implemented")
+}
+func (UnimplementedSoxServer) DetachSecurityGroup(context.Context, *DetachSecurityGroupRequest) (*DetachSecurityGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DetachSecurityGroup not implemented")
+}
+func (UnimplementedSoxServer) CreatePortForward(context.Context, *CreatePortForwardRequest) (*CreatePortForwardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePortForward not implemented")
+}
+func (UnimplementedSoxServer) ListPortForwards(context.Context, *ListPortForwardsRequest) (*ListPortForwardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPortForwards not implemented")
+}
+func (UnimplementedSoxServer) DeletePortForward(context.Context, *DeletePortForwardRequest) (*DeletePortForwardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePortForward not implemented")
+}
+func (UnimplementedSoxServer) AllocateFloatingIP(context.Context, *AllocateFloatingIPRequest) (*AllocateFloatingIPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AllocateFloatingIP not implemented")
+}
+func (UnimplementedSoxServer) ListFloatingIPs(context.Context, *ListFloatingIPsRequest) (*ListFloatingIPsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFloatingIPs not implemented")
+}
+func (UnimplementedSoxServer) AssociateFloatingIP(context.Context, *AssociateFloatingIPRequest) (*AssociateFloatingIPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssociateFloatingIP not implemented")
+}
+func (UnimplementedSoxServer) ReleaseFloatingIP(context.Context, *ReleaseFloatingIPRequest) (*ReleaseFloatingIPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseFloatingIP not implemented")
+}
+func (UnimplementedSoxServer) CreateVPNPeer(context.Context, *CreateVPNPeerRequest) (*CreateVPNPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVPNPeer not implemented")
+}
+func (UnimplementedSoxServer) ListVPNPeers(context.Context, *ListVPNPeersRequest) (*ListVPNPeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVPNPeers not implemented")
+}
+func (UnimplementedSoxServer) DeleteVPNPeer(context.Context, *DeleteVPNPeerRequest) (*DeleteVPNPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVPNPeer not implemented")
+}
+func (UnimplementedSoxServer) AttachNetworkInterface(context.Context, *AttachNetworkInterfaceRequest) (*AttachNetworkInterfaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AttachNetworkInterface not implemented")
+}
+func (UnimplementedSoxServer) DetachNetworkInterface(context.Context, *DetachNetworkInterfaceRequest) (*DetachNetworkInterfaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DetachNetworkInterface not implemented")
+}
+func (UnimplementedSoxServer) UpdateNetworkInterfaceQoS(context.Context, *UpdateNetworkInterfaceQoSRequest) (*UpdateNetworkInterfaceQoSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNetworkInterfaceQoS not implemented")
+}
+func (UnimplementedSoxServer) RegisterNode(context.Context, *RegisterNodeRequest) (*RegisterNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterNode not implemented")
+}
+func (UnimplementedSoxServer) ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNodes not implemented")
+}
+func (UnimplementedSoxServer) CordonNode(context.Context, *CordonNodeRequest) (*CordonNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CordonNode not implemented")
+}
+func (UnimplementedSoxServer) UncordonNode(context.Context, *UncordonNodeRequest) (*UncordonNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UncordonNode not implemented")
+}
+func (UnimplementedSoxServer) DrainNode(context.Context, *DrainNodeRequest) (*DrainNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainNode not implemented")
+}
+func (UnimplementedSoxServer) Rebalance(context.Context, *RebalanceRequest) (*RebalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rebalance not implemented")
+}
+func (UnimplementedSoxServer) MigrateMachine(context.Context, *MigrateMachineRequest) (*MigrateMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MigrateMachine not implemented")
+}
+func (UnimplementedSoxServer) ExportMachine(context.Context, *ExportMachineRequest) (*ExportMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportMachine not implemented")
+}
+func (UnimplementedSoxServer) ReadMachineDisk(*ReadMachineDiskRequest, Sox_ReadMachineDiskServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReadMachineDisk not implemented")
+}
+func (UnimplementedSoxServer) ImportMachine(context.Context, *ImportMachineRequest) (*ImportMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportMachine not implemented")
+}
+func (UnimplementedSoxServer) ReadImage(*ReadImageRequest, Sox_ReadImageServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReadImage not implemented")
+}
+func (UnimplementedSoxServer) SendMachine(context.Context, *SendMachineRequest) (*SendMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMachine not implemented")
+}
+func (UnimplementedSoxServer) AbortImport(context.Context, *AbortImportRequest) (*AbortImportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbortImport not implemented")
+}
+func (UnimplementedSoxServer) FlushForwarding(context.Context, *FlushForwardingRequest) (*FlushForwardingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushForwarding not implemented")
+}
+func (UnimplementedSoxServer) CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedSoxServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedSoxServer) DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedSoxServer) CreateProject(context.Context, *CreateProjectRequest) (*CreateProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProject not implemented")
+}
+func (UnimplementedSoxServer) ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProjects not implemented")
+}
+func (UnimplementedSoxServer) DeleteProject(context.Context, *DeleteProjectRequest) (*DeleteProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProject not implemented")
+}
+func (UnimplementedSoxServer) ListMemberships(context.Context, *ListMembershipsRequest) (*ListMembershipsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMemberships not implemented")
+}
+func (UnimplementedSoxServer) SetMembership(context.Context, *SetMembershipRequest) (*SetMembershipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMembership not implemented")
+}
+func (UnimplementedSoxServer) RemoveMembership(context.Context, *RemoveMembershipRequest) (*RemoveMembershipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMembership not implemented")
+}
+func (UnimplementedSoxServer) GetCurrentUser(context.Context, *GetCurrentUserRequest) (*GetCurrentUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentUser not implemented")
+}
+func (UnimplementedSoxServer) CreateToken(context.Context, *CreateTokenRequest) (*CreateTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateToken not implemented")
+}
+func (UnimplementedSoxServer) ListTokens(context.Context, *ListTokensRequest) (*ListTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTokens not implemented")
+}
+func (UnimplementedSoxServer) DeleteToken(context.Context, *DeleteTokenRequest) (*DeleteTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteToken not implemented")
+}
+func (UnimplementedSoxServer) GetQuotaUsage(context.Context, *GetQuotaUsageRequest) (*GetQuotaUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuotaUsage not implemented")
+}
+func (UnimplementedSoxServer) SetQuota(context.Context, *SetQuotaRequest) (*SetQuotaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetQuota not implemented")
+}
+func (UnimplementedSoxServer) ExportIdentity(context.Context, *ExportIdentityRequest) (*ExportIdentityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportIdentity not implemented")
+}
+func (UnimplementedSoxServer) ImportIdentity(context.Context, *ImportIdentityRequest) (*ImportIdentityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportIdentity not implemented")
+}
+func (UnimplementedSoxServer) GetHostInfo(context.Context, *GetHostInfoRequest) (*GetHostInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHostInfo not implemented")
+}
+func (UnimplementedSoxServer) WatchEvents(*WatchEventsRequest, Sox_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedSoxServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedSoxServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedSoxServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedSoxServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedSoxServer) GetMachineStats(context.Context, *GetMachineStatsRequest) (*GetMachineStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMachineStats not implemented")
+}
+func (UnimplementedSoxServer) mustEmbedUnimplementedSoxServer() {}
+
+// UnsafeSoxServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SoxServer will
+// result in compilation errors.
+type UnsafeSoxServer interface {
 	mustEmbedUnimplementedSoxServer()
 }
 
-// UnimplementedSoxServer must be embedded to have forward compatible implementations.
-type UnimplementedSoxServer struct {
+func RegisterSoxServer(s grpc.ServiceRegistrar, srv SoxServer) {
+	s.RegisterService(&Sox_ServiceDesc, srv)
+}
+
+func _Sox_CreateMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).CreateMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/CreateMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).CreateMachine(ctx, req.(*CreateMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListMachines_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMachinesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListMachines(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListMachines",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListMachines(ctx, req.(*ListMachinesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_GetMachineDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMachineDetailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).GetMachineDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/GetMachineDetails",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).GetMachineDetails(ctx, req.(*GetMachineDetailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_DeleteMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).DeleteMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/DeleteMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).DeleteMachine(ctx, req.(*DeleteMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_TriggerMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).TriggerMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/TriggerMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).TriggerMachine(ctx, req.(*TriggerMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_CreateSSHKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSSHKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).CreateSSHKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/CreateSSHKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).CreateSSHKey(ctx, req.(*CreateSSHKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListSSHKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSSHKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListSSHKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListSSHKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListSSHKeys(ctx, req.(*ListSSHKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_DeleteSSHKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSSHKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).DeleteSSHKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/DeleteSSHKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).DeleteSSHKey(ctx, req.(*DeleteSSHKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListImages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListImages(ctx, req.(*ListImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListNetworks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNetworksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListNetworks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListNetworks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListNetworks(ctx, req.(*ListNetworksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_CreateNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).CreateNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/CreateNetwork",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).CreateNetwork(ctx, req.(*CreateNetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListActivities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActivitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListActivities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListActivities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListActivities(ctx, req.(*ListActivitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_CreateSecurityGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSecurityGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).CreateSecurityGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/CreateSecurityGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).CreateSecurityGroup(ctx, req.(*CreateSecurityGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListSecurityGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSecurityGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListSecurityGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListSecurityGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListSecurityGroups(ctx, req.(*ListSecurityGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_UpdateSecurityGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSecurityGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).UpdateSecurityGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/UpdateSecurityGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).UpdateSecurityGroup(ctx, req.(*UpdateSecurityGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_DeleteSecurityGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSecurityGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).DeleteSecurityGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/DeleteSecurityGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).DeleteSecurityGroup(ctx, req.(*DeleteSecurityGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_AttachSecurityGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachSecurityGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).AttachSecurityGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/AttachSecurityGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).AttachSecurityGroup(ctx, req.(*AttachSecurityGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_DetachSecurityGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetachSecurityGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).DetachSecurityGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/DetachSecurityGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).DetachSecurityGroup(ctx, req.(*DetachSecurityGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_CreatePortForward_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePortForwardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).CreatePortForward(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/CreatePortForward",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).CreatePortForward(ctx, req.(*CreatePortForwardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListPortForwards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPortForwardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListPortForwards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListPortForwards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListPortForwards(ctx, req.(*ListPortForwardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_DeletePortForward_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePortForwardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).DeletePortForward(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/DeletePortForward",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).DeletePortForward(ctx, req.(*DeletePortForwardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_AllocateFloatingIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllocateFloatingIPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).AllocateFloatingIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/AllocateFloatingIP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).AllocateFloatingIP(ctx, req.(*AllocateFloatingIPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListFloatingIPs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFloatingIPsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListFloatingIPs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListFloatingIPs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListFloatingIPs(ctx, req.(*ListFloatingIPsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_AssociateFloatingIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssociateFloatingIPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).AssociateFloatingIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/AssociateFloatingIP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).AssociateFloatingIP(ctx, req.(*AssociateFloatingIPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ReleaseFloatingIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseFloatingIPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ReleaseFloatingIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ReleaseFloatingIP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ReleaseFloatingIP(ctx, req.(*ReleaseFloatingIPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_CreateVPNPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVPNPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).CreateVPNPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/CreateVPNPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).CreateVPNPeer(ctx, req.(*CreateVPNPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListVPNPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVPNPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListVPNPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListVPNPeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListVPNPeers(ctx, req.(*ListVPNPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_DeleteVPNPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVPNPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).DeleteVPNPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/DeleteVPNPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).DeleteVPNPeer(ctx, req.(*DeleteVPNPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_AttachNetworkInterface_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachNetworkInterfaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).AttachNetworkInterface(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/AttachNetworkInterface",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).AttachNetworkInterface(ctx, req.(*AttachNetworkInterfaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_DetachNetworkInterface_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetachNetworkInterfaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).DetachNetworkInterface(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/DetachNetworkInterface",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).DetachNetworkInterface(ctx, req.(*DetachNetworkInterfaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_UpdateNetworkInterfaceQoS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNetworkInterfaceQoSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).UpdateNetworkInterfaceQoS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/UpdateNetworkInterfaceQoS",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).UpdateNetworkInterfaceQoS(ctx, req.(*UpdateNetworkInterfaceQoSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_RegisterNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).RegisterNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/RegisterNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).RegisterNode(ctx, req.(*RegisterNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListNodes(ctx, req.(*ListNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedSoxServer) CreateMachine(context.Context, *CreateMachineRequest) (*CreateMachineResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateMachine not implemented")
+func _Sox_CordonNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CordonNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).CordonNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/CordonNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).CordonNode(ctx, req.(*CordonNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSoxServer) ListMachines(context.Context, *ListMachinesRequest) (*ListMachinesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListMachines not implemented")
+
+func _Sox_UncordonNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UncordonNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).UncordonNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/UncordonNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).UncordonNode(ctx, req.(*UncordonNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSoxServer) GetMachineDetails(context.Context, *GetMachineDetailsRequest) (*GetMachineDetailsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetMachineDetails not implemented")
+
+func _Sox_DrainNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).DrainNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/DrainNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).DrainNode(ctx, req.(*DrainNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSoxServer) DeleteMachine(context.Context, *DeleteMachineRequest) (*DeleteMachineResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteMachine not implemented")
+
+func _Sox_Rebalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).Rebalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/Rebalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).Rebalance(ctx, req.(*RebalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSoxServer) TriggerMachine(context.Context, *TriggerMachineRequest) (*TriggerMachineResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method TriggerMachine not implemented")
+
+func _Sox_MigrateMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrateMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).MigrateMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/MigrateMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).MigrateMachine(ctx, req.(*MigrateMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSoxServer) CreateSSHKey(context.Context, *CreateSSHKeyRequest) (*CreateSSHKeyResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateSSHKey not implemented")
+
+func _Sox_ExportMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ExportMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ExportMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ExportMachine(ctx, req.(*ExportMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSoxServer) ListSSHKeys(context.Context, *ListSSHKeysRequest) (*ListSSHKeysResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListSSHKeys not implemented")
+
+func _Sox_ReadMachineDisk_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadMachineDiskRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SoxServer).ReadMachineDisk(m, &soxReadMachineDiskServer{stream})
 }
-func (UnimplementedSoxServer) DeleteSSHKey(context.Context, *DeleteSSHKeyRequest) (*DeleteSSHKeyResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteSSHKey not implemented")
+
+type Sox_ReadMachineDiskServer interface {
+	Send(*ReadMachineDiskResponse) error
+	grpc.ServerStream
 }
-func (UnimplementedSoxServer) ListImages(context.Context, *ListImagesRequest) (*ListImagesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListImages not implemented")
+
+type soxReadMachineDiskServer struct {
+	grpc.ServerStream
+}
+
+func (x *soxReadMachineDiskServer) Send(m *ReadMachineDiskResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Sox_ImportMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ImportMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ImportMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ImportMachine(ctx, req.(*ImportMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ReadImage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadImageRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SoxServer).ReadImage(m, &soxReadImageServer{stream})
+}
+
+type Sox_ReadImageServer interface {
+	Send(*ReadImageResponse) error
+	grpc.ServerStream
+}
+
+type soxReadImageServer struct {
+	grpc.ServerStream
+}
+
+func (x *soxReadImageServer) Send(m *ReadImageResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Sox_SendMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).SendMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/SendMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).SendMachine(ctx, req.(*SendMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_AbortImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).AbortImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/AbortImport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).AbortImport(ctx, req.(*AbortImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_FlushForwarding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushForwardingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).FlushForwarding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/FlushForwarding",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).FlushForwarding(ctx, req.(*FlushForwardingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/CreateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/DeleteUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_CreateProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).CreateProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/CreateProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).CreateProject(ctx, req.(*CreateProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_ListProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListProjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListProjects",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListProjects(ctx, req.(*ListProjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sox_DeleteProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).DeleteProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/DeleteProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).DeleteProject(ctx, req.(*DeleteProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSoxServer) ListNetworks(context.Context, *ListNetworksRequest) (*ListNetworksResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListNetworks not implemented")
+
+func _Sox_ListMemberships_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMembershipsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).ListMemberships(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/ListMemberships",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).ListMemberships(ctx, req.(*ListMembershipsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSoxServer) CreateNetwork(context.Context, *CreateNetworkRequest) (*CreateNetworkResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateNetwork not implemented")
+
+func _Sox_SetMembership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMembershipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).SetMembership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/SetMembership",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).SetMembership(ctx, req.(*SetMembershipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSoxServer) ListActivities(context.Context, *ListActivitiesRequest) (*ListActivitiesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListActivities not implemented")
+
+func _Sox_RemoveMembership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveMembershipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).RemoveMembership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/RemoveMembership",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).RemoveMembership(ctx, req.(*RemoveMembershipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSoxServer) mustEmbedUnimplementedSoxServer() {}
 
-// UnsafeSoxServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to SoxServer will
-// result in compilation errors.
-type UnsafeSoxServer interface {
-	mustEmbedUnimplementedSoxServer()
+func _Sox_GetCurrentUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurrentUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).GetCurrentUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/GetCurrentUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).GetCurrentUser(ctx, req.(*GetCurrentUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterSoxServer(s grpc.ServiceRegistrar, srv SoxServer) {
-	s.RegisterService(&Sox_ServiceDesc, srv)
+func _Sox_CreateToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SoxServer).CreateToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sox.v1.Sox/CreateToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SoxServer).CreateToken(ctx, req.(*CreateTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_CreateMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateMachineRequest)
+func _Sox_ListTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTokensRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).CreateMachine(ctx, in)
+		return srv.(SoxServer).ListTokens(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/CreateMachine",
+		FullMethod: "/sox.v1.Sox/ListTokens",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).CreateMachine(ctx, req.(*CreateMachineRequest))
+		return srv.(SoxServer).ListTokens(ctx, req.(*ListTokensRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_ListMachines_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListMachinesRequest)
+func _Sox_DeleteToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTokenRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).ListMachines(ctx, in)
+		return srv.(SoxServer).DeleteToken(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/ListMachines",
+		FullMethod: "/sox.v1.Sox/DeleteToken",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).ListMachines(ctx, req.(*ListMachinesRequest))
+		return srv.(SoxServer).DeleteToken(ctx, req.(*DeleteTokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_GetMachineDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetMachineDetailsRequest)
+func _Sox_GetQuotaUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuotaUsageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).GetMachineDetails(ctx, in)
+		return srv.(SoxServer).GetQuotaUsage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/GetMachineDetails",
+		FullMethod: "/sox.v1.Sox/GetQuotaUsage",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).GetMachineDetails(ctx, req.(*GetMachineDetailsRequest))
+		return srv.(SoxServer).GetQuotaUsage(ctx, req.(*GetQuotaUsageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_DeleteMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteMachineRequest)
+func _Sox_SetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetQuotaRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).DeleteMachine(ctx, in)
+		return srv.(SoxServer).SetQuota(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/DeleteMachine",
+		FullMethod: "/sox.v1.Sox/SetQuota",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).DeleteMachine(ctx, req.(*DeleteMachineRequest))
+		return srv.(SoxServer).SetQuota(ctx, req.(*SetQuotaRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_TriggerMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TriggerMachineRequest)
+func _Sox_ExportIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportIdentityRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).TriggerMachine(ctx, in)
+		return srv.(SoxServer).ExportIdentity(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/TriggerMachine",
+		FullMethod: "/sox.v1.Sox/ExportIdentity",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).TriggerMachine(ctx, req.(*TriggerMachineRequest))
+		return srv.(SoxServer).ExportIdentity(ctx, req.(*ExportIdentityRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_CreateSSHKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateSSHKeyRequest)
+func _Sox_ImportIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportIdentityRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).CreateSSHKey(ctx, in)
+		return srv.(SoxServer).ImportIdentity(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/CreateSSHKey",
+		FullMethod: "/sox.v1.Sox/ImportIdentity",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).CreateSSHKey(ctx, req.(*CreateSSHKeyRequest))
+		return srv.(SoxServer).ImportIdentity(ctx, req.(*ImportIdentityRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_ListSSHKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListSSHKeysRequest)
+func _Sox_GetHostInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHostInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).ListSSHKeys(ctx, in)
+		return srv.(SoxServer).GetHostInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/ListSSHKeys",
+		FullMethod: "/sox.v1.Sox/GetHostInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).ListSSHKeys(ctx, req.(*ListSSHKeysRequest))
+		return srv.(SoxServer).GetHostInfo(ctx, req.(*GetHostInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_DeleteSSHKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteSSHKeyRequest)
+func _Sox_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SoxServer).WatchEvents(m, &soxWatchEventsServer{stream})
+}
+
+type Sox_WatchEventsServer interface {
+	Send(*WatchEventsResponse) error
+	grpc.ServerStream
+}
+
+type soxWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *soxWatchEventsServer) Send(m *WatchEventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Sox_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).DeleteSSHKey(ctx, in)
+		return srv.(SoxServer).CreateWebhook(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/DeleteSSHKey",
+		FullMethod: "/sox.v1.Sox/CreateWebhook",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).DeleteSSHKey(ctx, req.(*DeleteSSHKeyRequest))
+		return srv.(SoxServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_ListImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListImagesRequest)
+func _Sox_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).ListImages(ctx, in)
+		return srv.(SoxServer).ListWebhooks(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/ListImages",
+		FullMethod: "/sox.v1.Sox/ListWebhooks",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).ListImages(ctx, req.(*ListImagesRequest))
+		return srv.(SoxServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_ListNetworks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListNetworksRequest)
+func _Sox_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).ListNetworks(ctx, in)
+		return srv.(SoxServer).DeleteWebhook(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/ListNetworks",
+		FullMethod: "/sox.v1.Sox/DeleteWebhook",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).ListNetworks(ctx, req.(*ListNetworksRequest))
+		return srv.(SoxServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_CreateNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateNetworkRequest)
+func _Sox_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).CreateNetwork(ctx, in)
+		return srv.(SoxServer).ListWebhookDeliveries(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/CreateNetwork",
+		FullMethod: "/sox.v1.Sox/ListWebhookDeliveries",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).CreateNetwork(ctx, req.(*CreateNetworkRequest))
+		return srv.(SoxServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Sox_ListActivities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListActivitiesRequest)
+func _Sox_GetMachineStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMachineStatsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SoxServer).ListActivities(ctx, in)
+		return srv.(SoxServer).GetMachineStats(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sox.v1.Sox/ListActivities",
+		FullMethod: "/sox.v1.Sox/GetMachineStats",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SoxServer).ListActivities(ctx, req.(*ListActivitiesRequest))
+		return srv.(SoxServer).GetMachineStats(ctx, req.(*GetMachineStatsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -491,7 +2397,239 @@ var Sox_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListActivities",
 			Handler:    _Sox_ListActivities_Handler,
 		},
+		{
+			MethodName: "CreateSecurityGroup",
+			Handler:    _Sox_CreateSecurityGroup_Handler,
+		},
+		{
+			MethodName: "ListSecurityGroups",
+			Handler:    _Sox_ListSecurityGroups_Handler,
+		},
+		{
+			MethodName: "UpdateSecurityGroup",
+			Handler:    _Sox_UpdateSecurityGroup_Handler,
+		},
+		{
+			MethodName: "DeleteSecurityGroup",
+			Handler:    _Sox_DeleteSecurityGroup_Handler,
+		},
+		{
+			MethodName: "AttachSecurityGroup",
+			Handler:    _Sox_AttachSecurityGroup_Handler,
+		},
+		{
+			MethodName: "DetachSecurityGroup",
+			Handler:    _Sox_DetachSecurityGroup_Handler,
+		},
+		{
+			MethodName: "CreatePortForward",
+			Handler:    _Sox_CreatePortForward_Handler,
+		},
+		{
+			MethodName: "ListPortForwards",
+			Handler:    _Sox_ListPortForwards_Handler,
+		},
+		{
+			MethodName: "DeletePortForward",
+			Handler:    _Sox_DeletePortForward_Handler,
+		},
+		{
+			MethodName: "AllocateFloatingIP",
+			Handler:    _Sox_AllocateFloatingIP_Handler,
+		},
+		{
+			MethodName: "ListFloatingIPs",
+			Handler:    _Sox_ListFloatingIPs_Handler,
+		},
+		{
+			MethodName: "AssociateFloatingIP",
+			Handler:    _Sox_AssociateFloatingIP_Handler,
+		},
+		{
+			MethodName: "ReleaseFloatingIP",
+			Handler:    _Sox_ReleaseFloatingIP_Handler,
+		},
+		{
+			MethodName: "CreateVPNPeer",
+			Handler:    _Sox_CreateVPNPeer_Handler,
+		},
+		{
+			MethodName: "ListVPNPeers",
+			Handler:    _Sox_ListVPNPeers_Handler,
+		},
+		{
+			MethodName: "DeleteVPNPeer",
+			Handler:    _Sox_DeleteVPNPeer_Handler,
+		},
+		{
+			MethodName: "AttachNetworkInterface",
+			Handler:    _Sox_AttachNetworkInterface_Handler,
+		},
+		{
+			MethodName: "DetachNetworkInterface",
+			Handler:    _Sox_DetachNetworkInterface_Handler,
+		},
+		{
+			MethodName: "UpdateNetworkInterfaceQoS",
+			Handler:    _Sox_UpdateNetworkInterfaceQoS_Handler,
+		},
+		{
+			MethodName: "RegisterNode",
+			Handler:    _Sox_RegisterNode_Handler,
+		},
+		{
+			MethodName: "ListNodes",
+			Handler:    _Sox_ListNodes_Handler,
+		},
+		{
+			MethodName: "CordonNode",
+			Handler:    _Sox_CordonNode_Handler,
+		},
+		{
+			MethodName: "UncordonNode",
+			Handler:    _Sox_UncordonNode_Handler,
+		},
+		{
+			MethodName: "DrainNode",
+			Handler:    _Sox_DrainNode_Handler,
+		},
+		{
+			MethodName: "Rebalance",
+			Handler:    _Sox_Rebalance_Handler,
+		},
+		{
+			MethodName: "MigrateMachine",
+			Handler:    _Sox_MigrateMachine_Handler,
+		},
+		{
+			MethodName: "ExportMachine",
+			Handler:    _Sox_ExportMachine_Handler,
+		},
+		{
+			MethodName: "ImportMachine",
+			Handler:    _Sox_ImportMachine_Handler,
+		},
+		{
+			MethodName: "SendMachine",
+			Handler:    _Sox_SendMachine_Handler,
+		},
+		{
+			MethodName: "AbortImport",
+			Handler:    _Sox_AbortImport_Handler,
+		},
+		{
+			MethodName: "FlushForwarding",
+			Handler:    _Sox_FlushForwarding_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _Sox_CreateUser_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _Sox_ListUsers_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _Sox_DeleteUser_Handler,
+		},
+		{
+			MethodName: "CreateProject",
+			Handler:    _Sox_CreateProject_Handler,
+		},
+		{
+			MethodName: "ListProjects",
+			Handler:    _Sox_ListProjects_Handler,
+		},
+		{
+			MethodName: "DeleteProject",
+			Handler:    _Sox_DeleteProject_Handler,
+		},
+		{
+			MethodName: "ListMemberships",
+			Handler:    _Sox_ListMemberships_Handler,
+		},
+		{
+			MethodName: "SetMembership",
+			Handler:    _Sox_SetMembership_Handler,
+		},
+		{
+			MethodName: "RemoveMembership",
+			Handler:    _Sox_RemoveMembership_Handler,
+		},
+		{
+			MethodName: "GetCurrentUser",
+			Handler:    _Sox_GetCurrentUser_Handler,
+		},
+		{
+			MethodName: "CreateToken",
+			Handler:    _Sox_CreateToken_Handler,
+		},
+		{
+			MethodName: "ListTokens",
+			Handler:    _Sox_ListTokens_Handler,
+		},
+		{
+			MethodName: "DeleteToken",
+			Handler:    _Sox_DeleteToken_Handler,
+		},
+		{
+			MethodName: "GetQuotaUsage",
+			Handler:    _Sox_GetQuotaUsage_Handler,
+		},
+		{
+			MethodName: "SetQuota",
+			Handler:    _Sox_SetQuota_Handler,
+		},
+		{
+			MethodName: "ExportIdentity",
+			Handler:    _Sox_ExportIdentity_Handler,
+		},
+		{
+			MethodName: "ImportIdentity",
+			Handler:    _Sox_ImportIdentity_Handler,
+		},
+		{
+			MethodName: "GetHostInfo",
+			Handler:    _Sox_GetHostInfo_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _Sox_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _Sox_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _Sox_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _Sox_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "GetMachineStats",
+			Handler:    _Sox_GetMachineStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadMachineDisk",
+			Handler:       _Sox_ReadMachineDisk_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReadImage",
+			Handler:       _Sox_ReadImage_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _Sox_WatchEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.proto",
 }
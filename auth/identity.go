@@ -0,0 +1,109 @@
+// Package auth carries the identity of API callers between clients, aggregators and nodes.
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys naming the user a request is made by and the project it is made in.
+// Authenticated admins may name another user to act on its behalf.
+const (
+	UserMetadataKey    = "sox-user"
+	ProjectMetadataKey = "sox-project"
+	// TokenMetadataKey carries API tokens as bearer tokens.
+	TokenMetadataKey = "authorization"
+)
+
+const bearerPrefix = "Bearer "
+
+// Identity is the user and the selected project of a request.
+type Identity struct {
+	User string
+	// Project is the ID or name of the selected project, empty if none was selected.
+	Project string
+	// Token is the secret of the API token the request is authenticated with.
+	Token string
+}
+
+// FromIncomingContext returns the identity given in the request metadata.
+func FromIncomingContext(ctx context.Context) Identity {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return Identity{}
+	}
+	return Identity{
+		User:    first(md.Get(UserMetadataKey)),
+		Project: first(md.Get(ProjectMetadataKey)),
+		Token:   strings.TrimPrefix(first(md.Get(TokenMetadataKey)), bearerPrefix),
+	}
+}
+
+// NewOutgoingContext attaches the identity to requests made with the returned context.
+func NewOutgoingContext(ctx context.Context, identity Identity) context.Context {
+	var pairs []string
+	if identity.User != "" {
+		pairs = append(pairs, UserMetadataKey, identity.User)
+	}
+	if identity.Project != "" {
+		pairs = append(pairs, ProjectMetadataKey, identity.Project)
+	}
+	if identity.Token != "" {
+		pairs = append(pairs, TokenMetadataKey, bearerPrefix+identity.Token)
+	}
+	if len(pairs) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// Detach returns a context without deadline carrying the identity of the request,
+// for work which continues after the request has been answered.
+func Detach(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	detached := metadata.MD{}
+	for _, key := range []string{UserMetadataKey, ProjectMetadataKey, TokenMetadataKey} {
+		if values := md.Get(key); len(values) > 0 {
+			detached.Set(key, values...)
+		}
+	}
+	return metadata.NewIncomingContext(context.Background(), detached)
+}
+
+// WithUser returns a context whose incoming metadata names the given user.
+func WithUser(ctx context.Context, user string) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Set(UserMetadataKey, user)
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// Forward returns dial options passing the identity of incoming requests on to the requests they cause.
+func Forward() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(forward(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(forward(ctx), desc, cc, method, opts...)
+		}),
+	}
+}
+
+// forward copies the incoming identity unless the outgoing metadata already names a user.
+func forward(ctx context.Context) context.Context {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(UserMetadataKey)) > 0 {
+		return ctx
+	}
+	return NewOutgoingContext(ctx, FromIncomingContext(ctx))
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
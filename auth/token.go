@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// tokenPrefix marks API tokens so they are easy to recognize in configs and logs.
+const tokenPrefix = "sox_"
+
+// GenerateToken returns a new random token secret along with the hash to store.
+func GenerateToken() (secret, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("read random: %w", err)
+	}
+	secret = tokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return secret, HashToken(secret), nil
+}
+
+// HashToken returns the hash tokens are looked up by.
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,21 +1,40 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
 	"github.com/lnsp/sox/meta"
+	"github.com/lnsp/sox/webhooks"
+	"github.com/pelletier/go-toml"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var endpoint string
 
+var node string
+
+var user, project string
+
+var token, caFile, certFile, keyFile string
+
 var insecure bool
 
 var timeout time.Duration
@@ -124,9 +143,9 @@ var machinesCmd = cobra.Command{
 		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
 		defer tw.Flush()
 
-		fmt.Fprintf(tw, "ID\tNAME\tSTATUS\n")
+		fmt.Fprintf(tw, "ID\tNAME\tSTATUS\tNODE\n")
 		for _, machine := range resp.Machines {
-			fmt.Fprintf(tw, "%s\t%s\t%s\n", machine.Id, machine.Name, machine.Status)
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", machine.Id, machine.Name, machine.Status, machine.Node)
 		}
 		return nil
 	},
@@ -166,6 +185,83 @@ var machinesInspectCmd = cobra.Command{
 	},
 }
 
+var machinesTopInterval time.Duration
+var machinesTopOnce bool
+
+var machinesTopCmd = cobra.Command{
+	Use:          "top",
+	Short:        "Display the resource usage of running machines",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// refresh until interrupted
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		ticker := time.NewTicker(machinesTopInterval)
+		defer ticker.Stop()
+		for {
+			if err := printMachinesTop(ctx, client); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			if machinesTopOnce {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// printMachinesTop prints the latest usage samples of all running machines.
+func printMachinesTop(ctx context.Context, client api.SoxClient) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := client.ListMachines(ctx, &api.ListMachinesRequest{})
+	if err != nil {
+		return err
+	}
+	// only the current sample is needed
+	now := timestamppb.Now()
+	tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+	fmt.Fprintf(tw, "NAME\tNODE\tCPU\tMEMORY\tDISK READ\tDISK WRITE\tNET RX\tNET TX\n")
+	for _, machine := range resp.Machines {
+		if machine.Status != api.Machine_RUNNING {
+			continue
+		}
+		stats, err := client.GetMachineStats(ctx, &api.GetMachineStatsRequest{
+			Id:    machine.Id,
+			Since: now,
+		})
+		if err != nil {
+			return err
+		}
+		usage := stats.Current
+		if usage == nil {
+			fmt.Fprintf(tw, "%s\t%s\t-\t-\t-\t-\t-\t-\n", machine.Name, machine.Node)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.1f%%\t%s / %s\t%s/s\t%s/s\t%s/s\t%s/s\n",
+			machine.Name, machine.Node, usage.CpuPercent,
+			humanize.IBytes(uint64(usage.MemoryUsed)), humanize.IBytes(uint64(usage.MemoryTotal)),
+			humanize.Bytes(uint64(usage.DiskReadRate)), humanize.Bytes(uint64(usage.DiskWriteRate)),
+			humanize.Bytes(uint64(usage.NetworkReceiveRate)), humanize.Bytes(uint64(usage.NetworkTransmitRate)))
+	}
+	if !machinesTopOnce {
+		// clear the terminal before printing the table
+		fmt.Print("\033[H\033[2J")
+	}
+	return tw.Flush()
+}
+
 var machinesCreateCpu int64
 var machinesCreateMemory int64
 var machinesCreateDisk int64
@@ -173,6 +269,7 @@ var machinesCreateImage string
 var machinesCreateSSHKeys []string
 var machinesCreateNetworks []string
 var machinesCreateUser string
+var machinesCreateNodeLabels map[string]string
 
 var machinesCreateCmd = cobra.Command{
 	Use:   "create [name]",
@@ -198,6 +295,8 @@ var machinesCreateCmd = cobra.Command{
 			SshKeyIds:  machinesCreateSSHKeys,
 			NetworkIds: machinesCreateNetworks,
 			User:       machinesCreateUser,
+			Node:       node,
+			NodeLabels: machinesCreateNodeLabels,
 		})
 		if err != nil {
 			return err
@@ -304,6 +403,139 @@ var machinesRebootCmd = cobra.Command{
 	},
 }
 
+var machinesExposeHostIP string
+var machinesExposeNetwork string
+var machinesExposeProtocol string
+
+var machinesExposeCmd = cobra.Command{
+	Use:   "expose [id] [host-port]:[machine-port]",
+	Short: "Forward a host port to a machine on a NAT network",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// parse port mapping
+		ports := strings.SplitN(args[1], ":", 2)
+		if len(ports) != 2 {
+			return fmt.Errorf("port mapping must be of form host-port:machine-port")
+		}
+		hostPort, err := strconv.ParseUint(ports[0], 10, 16)
+		if err != nil {
+			return fmt.Errorf("parse host port: %w", err)
+		}
+		machinePort, err := strconv.ParseUint(ports[1], 10, 16)
+		if err != nil {
+			return fmt.Errorf("parse machine port: %w", err)
+		}
+		protocol, ok := api.PortForward_Protocol_value[strings.ToUpper(machinesExposeProtocol)]
+		if !ok {
+			return fmt.Errorf("unknown protocol %s", machinesExposeProtocol)
+		}
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// submit request
+		resp, err := client.CreatePortForward(ctx, &api.CreatePortForwardRequest{
+			MachineId:   args[0],
+			NetworkId:   machinesExposeNetwork,
+			HostIp:      machinesExposeHostIP,
+			HostPort:    uint32(hostPort),
+			MachinePort: uint32(machinePort),
+			Protocol:    api.PortForward_Protocol(protocol),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(resp.Id)
+		return nil
+	},
+}
+
+var qosInboundAverage, qosInboundPeak, qosInboundBurst uint32
+var qosOutboundAverage, qosOutboundPeak, qosOutboundBurst uint32
+
+// addQoSFlags registers the bandwidth limit flags on a command.
+func addQoSFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint32Var(&qosInboundAverage, "inbound-average", 0, "Average inbound rate in KiB/s")
+	cmd.Flags().Uint32Var(&qosInboundPeak, "inbound-peak", 0, "Peak inbound rate in KiB/s")
+	cmd.Flags().Uint32Var(&qosInboundBurst, "inbound-burst", 0, "Inbound burst size in KiB")
+	cmd.Flags().Uint32Var(&qosOutboundAverage, "outbound-average", 0, "Average outbound rate in KiB/s")
+	cmd.Flags().Uint32Var(&qosOutboundPeak, "outbound-peak", 0, "Peak outbound rate in KiB/s")
+	cmd.Flags().Uint32Var(&qosOutboundBurst, "outbound-burst", 0, "Outbound burst size in KiB")
+}
+
+func qosFromFlags() *api.Bandwidth {
+	return &api.Bandwidth{
+		Inbound: &api.Bandwidth_Limit{
+			Average: qosInboundAverage,
+			Peak:    qosInboundPeak,
+			Burst:   qosInboundBurst,
+		},
+		Outbound: &api.Bandwidth_Limit{
+			Average: qosOutboundAverage,
+			Peak:    qosOutboundPeak,
+			Burst:   qosOutboundBurst,
+		},
+	}
+}
+
+var machinesQoSCmd = cobra.Command{
+	Use:   "qos [id] [network-id]",
+	Short: "Set the bandwidth limits of a machine interface",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// submit request
+		_, err = client.UpdateNetworkInterfaceQoS(ctx, &api.UpdateNetworkInterfaceQoSRequest{
+			MachineId: args[0],
+			NetworkId: args[1],
+			Qos:       qosFromFlags(),
+		})
+		return err
+	},
+}
+
+var (
+	machinesMigrateLive        bool
+	machinesMigrateCopyStorage bool
+)
+
+var machinesMigrateCmd = cobra.Command{
+	Use:          "migrate [id] [target-node]",
+	Short:        "Move a machine to another node",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// migrate machine
+		resp, err := client.MigrateMachine(ctx, &api.MigrateMachineRequest{
+			Id:          args[0],
+			TargetNode:  args[1],
+			Live:        machinesMigrateLive,
+			CopyStorage: machinesMigrateCopyStorage,
+		})
+		if err != nil {
+			return err
+		}
+		printMoves([]*api.MachineMove{resp.Move})
+		return nil
+	},
+}
+
 var networksCmd = cobra.Command{
 	Use:          "networks",
 	Short:        "Manage virtual networks",
@@ -331,12 +563,12 @@ var networksCmd = cobra.Command{
 		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
 		defer tw.Flush()
 
-		fmt.Fprintf(tw, "ID\tNAME\tSUBNET\tGATEWAY\n")
+		fmt.Fprintf(tw, "ID\tNAME\tMODE\tSUBNET\tGATEWAY\n")
 		for _, net := range resp.Networks {
 			fmt.Fprintf(
 				tw,
-				"%s\t%s\t%s\t%s\n",
-				net.Id, net.Name, net.IpV4.Subnet, net.IpV4.Gateway,
+				"%s\t%s\t%s\t%s\t%s\n",
+				net.Id, net.Name, net.Mode, net.IpV4.Subnet, net.IpV4.Gateway,
 			)
 		}
 		return nil
@@ -349,6 +581,9 @@ var networksCreateIPV4Subnet string
 var networksCreateIPV4Gateway string
 var networksCreateIPV6Subnet string
 var networksCreateIPV6Gateway string
+var networksCreateMTU uint32
+var networksCreateMode string
+var networksCreatePhysicalDevice string
 
 var networksCreateCmd = cobra.Command{
 	Use:          "create",
@@ -359,11 +594,22 @@ var networksCreateCmd = cobra.Command{
 		if err != nil {
 			return err
 		}
+		mode, ok := api.Network_Mode_value[strings.ToUpper(networksCreateMode)]
+		if networksCreateMode != "" && !ok {
+			return fmt.Errorf("unknown network mode %s", networksCreateMode)
+		}
+		// The default VXLAN ID only applies if no other mode has been chosen
+		bridgeId := networksCreateBridgeId
+		if networksCreateMode != "" && mode != int32(api.Network_VXLAN) && !cmd.Flags().Changed("bridge-id") {
+			bridgeId = 0
+		}
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
 		resp, err := client.CreateNetwork(ctx, &api.CreateNetworkRequest{
-			Name:     networksCreateName,
-			BridgeId: networksCreateBridgeId,
+			Name:           networksCreateName,
+			BridgeId:       bridgeId,
+			Mode:           api.Network_Mode(mode),
+			PhysicalDevice: networksCreatePhysicalDevice,
 			IpV4: &api.IpNetwork{
 				Subnet:  networksCreateIPV4Subnet,
 				Gateway: networksCreateIPV4Gateway,
@@ -372,6 +618,8 @@ var networksCreateCmd = cobra.Command{
 				Subnet:  networksCreateIPV6Subnet,
 				Gateway: networksCreateIPV6Gateway,
 			},
+			DefaultQos: qosFromFlags(),
+			Mtu:        networksCreateMTU,
 		})
 		if err != nil {
 			return err
@@ -381,11 +629,133 @@ var networksCreateCmd = cobra.Command{
 	},
 }
 
+var vpnCmd = cobra.Command{
+	Use:   "vpn",
+	Short: "Manage access to networks via WireGuard",
+}
+
+var vpnPeersCmd = cobra.Command{
+	Use:          "peers",
+	Short:        "List VPN peers",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// list peers
+		resp, err := client.ListVPNPeers(ctx, &api.ListVPNPeersRequest{})
+		if err != nil {
+			return err
+		}
+		if listIdsOnly {
+			for i := range resp.VpnPeers {
+				fmt.Println(resp.VpnPeers[i].Id)
+			}
+			return nil
+		}
+		// print out peers
+		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "ID\tNAME\tADDRESS\tNETWORKS\n")
+		for _, peer := range resp.VpnPeers {
+			fmt.Fprintf(
+				tw,
+				"%s\t%s\t%s\t%s\n",
+				peer.Id, peer.Name, peer.Address, strings.Join(peer.NetworkIds, ","),
+			)
+		}
+		return nil
+	},
+}
+
+var vpnPeersAddNetworks []string
+var vpnPeersAddPublicKey string
+
+var vpnPeersAddCmd = cobra.Command{
+	Use:          "add [name]",
+	Short:        "Add a VPN peer and print its client config",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// submit request
+		resp, err := client.CreateVPNPeer(ctx, &api.CreateVPNPeerRequest{
+			Name:       args[0],
+			NetworkIds: vpnPeersAddNetworks,
+			PublicKey:  vpnPeersAddPublicKey,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Print(resp.ClientConfig)
+		return nil
+	},
+}
+
+var vpnPeersDeleteCmd = cobra.Command{
+	Use:   "delete [id | name]",
+	Short: "Revoke access of a VPN peer",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// submit request
+		if _, err := client.DeleteVPNPeer(ctx, &api.DeleteVPNPeerRequest{
+			Id: args[0],
+		}); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+var (
+	activitySubject     string
+	activityTypes       []string
+	activitySince       time.Duration
+	activityLimit       int32
+	activityPageToken   string
+	activityOldestFirst bool
+	activityDetails     bool
+)
+
 var activityCmd = cobra.Command{
 	Use:          "activity",
-	Short:        "List recent activities",
+	Short:        "List recent activities, newest first",
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		request := &api.ListActivitiesRequest{
+			Subject:     activitySubject,
+			OldestFirst: activityOldestFirst,
+			PageSize:    activityLimit,
+			PageToken:   activityPageToken,
+		}
+		for _, name := range activityTypes {
+			activityType, ok := api.Activity_Type_value[strings.ToUpper(name)]
+			if !ok || activityType == int32(api.Activity_UNKNOWN) {
+				return fmt.Errorf("unknown activity type %s", name)
+			}
+			request.Types = append(request.Types, api.Activity_Type(activityType))
+		}
+		if activitySince > 0 {
+			request.Since = timestamppb.New(time.Now().Add(-activitySince))
+		}
 		client, err := connect()
 		if err != nil {
 			return err
@@ -394,7 +764,7 @@ var activityCmd = cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
 		// list activities
-		resp, err := client.ListActivities(ctx, &api.ListActivitiesRequest{})
+		resp, err := client.ListActivities(ctx, request)
 		if err != nil {
 			return err
 		}
@@ -402,68 +772,1266 @@ var activityCmd = cobra.Command{
 		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
 		defer tw.Flush()
 
-		fmt.Fprintf(tw, "TIME\tACTIVITY\tSUBJECT\n")
+		if activityDetails {
+			fmt.Fprintf(tw, "TIME\tACTIVITY\tKIND\tSUBJECT\tACTOR\tNODE\tRESULT\tSOURCE\tMETHOD\tDETAILS\n")
+		} else {
+			fmt.Fprintf(tw, "TIME\tACTIVITY\tKIND\tSUBJECT\tACTOR\tNODE\tRESULT\n")
+		}
 		for _, act := range resp.Activities {
+			result := "ok"
+			if act.Error != "" {
+				result = act.Error
+			}
 			fmt.Fprintf(
 				tw,
-				"%s\t%s\t%s\n",
+				"%s\t%s\t%s\t%s\t%s\t%s\t%s",
 				humanize.Time(act.Timestamp.AsTime()),
-				act.Type, act.Subject,
+				act.Type, act.Kind, act.Subject, act.Actor, act.Node, result,
 			)
+			if activityDetails {
+				fmt.Fprintf(tw, "\t%s\t%s\t%s", act.SourceAddress, act.Method, act.Details)
+			}
+			fmt.Fprintln(tw)
+		}
+		if resp.NextPageToken != "" {
+			fmt.Fprintf(os.Stderr, "more activities are listed with --page-token %s\n", resp.NextPageToken)
 		}
-
 		return nil
 	},
 }
 
-func init() {
-	rootCmd.PersistentFlags().StringVarP(&endpoint, "endpoint", "p", "localhost:9876", "VirtM endpoint address")
-	rootCmd.PersistentFlags().BoolVar(&insecure, "insecure", true, "Connect to insecure endpoint")
-	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", time.Minute, "Client connection timeout")
-	rootCmd.AddCommand(&imagesCmd)
-	imagesCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
-	rootCmd.AddCommand(&sshKeysCmd)
-	sshKeysCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
-	rootCmd.AddCommand(&machinesCmd)
-	machinesCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
-	rootCmd.AddCommand(&networksCmd)
-	networksCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
-	networksCmd.AddCommand(&networksCreateCmd)
-	networksCreateCmd.Flags().StringVarP(&networksCreateName, "name", "n", "", "Unique name of the network")
-	networksCreateCmd.Flags().Uint32VarP(&networksCreateBridgeId, "bridge-id", "b", 100, "VXLAN ID to attach to")
-	networksCreateCmd.Flags().StringVar(&networksCreateIPV4Subnet, "ipv4-subnet", "10.0.0.0/24", "IPv4 subnet")
-	networksCreateCmd.Flags().StringVar(&networksCreateIPV4Gateway, "ipv4-gateway", "", "IPv4 gateway")
-	networksCreateCmd.Flags().StringVar(&networksCreateIPV6Subnet, "ipv6-subnet", "", "IPv6 subnet")
-	networksCreateCmd.Flags().StringVar(&networksCreateIPV6Gateway, "ipv6-gateway", "", "IPv6 gateway")
-	rootCmd.AddCommand(&activityCmd)
-	machinesCmd.AddCommand(&machinesCreateCmd)
-	machinesCmd.AddCommand(&machinesInspectCmd)
-	machinesCmd.AddCommand(&machinesDeleteCmd)
-	machinesCmd.AddCommand(&machinesStartCmd)
-	machinesCmd.AddCommand(&machinesStopCmd)
-	machinesCmd.AddCommand(&machinesRebootCmd)
-	machinesCreateCmd.Flags().StringVarP(&machinesCreateImage, "image", "i", "", "Operating system image")
-	machinesCreateCmd.Flags().StringArrayVarP(&machinesCreateSSHKeys, "ssh-keys", "k", nil, "SSH keys for login")
-	machinesCreateCmd.Flags().StringArrayVarP(&machinesCreateNetworks, "networks", "n", nil, "Network to connect to")
-	machinesCreateCmd.Flags().Int64Var(&machinesCreateCpu, "cpu", 2, "Number of vCPUs")
-	machinesCreateCmd.Flags().Int64Var(&machinesCreateDisk, "disk", 10000, "Disk size in GB")
-	machinesCreateCmd.Flags().Int64Var(&machinesCreateMemory, "memory", 2000, "Memory size in MB")
-	machinesCreateCmd.Flags().StringVarP(&machinesCreateUser, "user", "u", "ken", "User account to be created")
-	machinesCreateCmd.MarkFlagRequired("image")
-	machinesCreateCmd.MarkFlagRequired("ssh-key")
-	machinesCreateCmd.MarkFlagRequired("networks")
+var (
+	eventsFollow      bool
+	eventsResumeToken string
+	eventsTypes       []string
+)
+
+var eventsCmd = cobra.Command{
+	Use:          "events",
+	Short:        "Watch resource changes and activities as they happen",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		request := &api.WatchEventsRequest{
+			ResumeToken: eventsResumeToken,
+		}
+		for _, name := range eventsTypes {
+			eventType, ok := api.Event_Type_value[strings.ToUpper(name)]
+			if !ok || eventType == int32(api.Event_TYPE_UNSPECIFIED) {
+				return fmt.Errorf("unknown event type %s", name)
+			}
+			request.Types = append(request.Types, api.Event_Type(eventType))
+		}
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// watch until interrupted when following, otherwise until the timeout
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		if !eventsFollow {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		stream, err := client.WatchEvents(ctx, request)
+		if err != nil {
+			return err
+		}
+		var resumeToken string
+		defer func() {
+			if resumeToken != "" {
+				fmt.Fprintf(os.Stderr, "continue watching with --resume-token %s\n", resumeToken)
+			}
+		}()
+		for {
+			resp, err := stream.Recv()
+			if ctx.Err() != nil {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			resumeToken = resp.ResumeToken
+			event := resp.Event
+			var change string
+			switch event.Type {
+			case api.Event_MACHINE_STATE_CHANGED:
+				change = event.MachineStatus.String()
+			case api.Event_ACTIVITY_RECORDED:
+				change = event.Activity.Type.String()
+				if event.Activity.Error != "" {
+					change += ": " + event.Activity.Error
+				}
+			}
+			fmt.Printf(
+				"%s %s %s %s %s %s\n",
+				event.Timestamp.AsTime().Local().Format(time.RFC3339),
+				event.Type, event.Node, event.Kind, event.Subject, change,
+			)
+		}
+	},
 }
 
-func connect() (api.VirtMClient, error) {
-	var grpcOpts []grpc.DialOption
-	if insecure {
-		grpcOpts = append(grpcOpts, grpc.WithInsecure())
-	}
-	grpcClient, err := grpc.Dial(endpoint, grpcOpts...)
-	if err != nil {
-		return nil, fmt.Errorf("dial endpoint: %w", err)
+var nodesCmd = cobra.Command{
+	Use:          "nodes",
+	Short:        "List cluster nodes",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// list nodes
+		resp, err := client.ListNodes(ctx, &api.ListNodesRequest{})
+		if err != nil {
+			return err
+		}
+		if listIdsOnly {
+			for i := range resp.Nodes {
+				fmt.Println(resp.Nodes[i].Name)
+			}
+			return nil
+		}
+		// print out nodes with allocated and total resources
+		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "NAME\tADDRESS\tSTATUS\tCPUS\tMEMORY\tDISK\tLAST SEEN\n")
+		for _, n := range resp.Nodes {
+			allocated, capacity := n.GetAllocated(), n.GetCapacity()
+			nodeStatus := n.Status.String()
+			if n.Cordoned {
+				nodeStatus += ",CORDONED"
+			}
+			fmt.Fprintf(
+				tw,
+				"%s\t%s\t%s\t%d/%d\t%d/%d MiB\t%d/%d GiB\t%s\n",
+				n.Name, n.Address, nodeStatus,
+				allocated.GetCpus(), capacity.GetCpus(),
+				allocated.GetMemory(), capacity.GetMemory(),
+				allocated.GetDisk(), capacity.GetDisk(),
+				humanize.Time(n.LastHeartbeat.AsTime()),
+			)
+		}
+		return nil
+	},
+}
+
+var nodesHostCmd = cobra.Command{
+	Use:          "host [name]",
+	Short:        "Show the host capacity, allocation and overcommit limits of nodes",
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// get host info
+		request := &api.GetHostInfoRequest{}
+		if len(args) > 0 {
+			request.Node = args[0]
+		}
+		resp, err := client.GetHostInfo(ctx, request)
+		if err != nil {
+			return err
+		}
+		// print out resources per node in table format
+		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "NODE\tRESOURCE\tCAPACITY\tFREE\tALLOCATED\tLIMIT\tOVERCOMMIT\n")
+		for _, h := range resp.Hosts {
+			fmt.Fprintf(tw, "%s\tvCPUs\t%d\t-\t%d\t%d\t%.2f\n", h.Node, h.Capacity.GetCpus(), h.Allocated.GetCpus(), h.Limit.GetCpus(), h.Overcommit.GetCpus())
+			fmt.Fprintf(tw, "%s\tMemory (MB)\t%d\t%d\t%d\t%d\t%.2f\n", h.Node, h.Capacity.GetMemory(), h.Free.GetMemory(), h.Allocated.GetMemory(), h.Limit.GetMemory(), h.Overcommit.GetMemory())
+			fmt.Fprintf(tw, "%s\tDisk (GB)\t%d\t%d\t%d\t%d\t%.2f\n", h.Node, h.Capacity.GetDisk(), h.Free.GetDisk(), h.Allocated.GetDisk(), h.Limit.GetDisk(), h.Overcommit.GetDisk())
+		}
+		return nil
+	},
+}
+
+var nodesCordonCmd = cobra.Command{
+	Use:          "cordon [name]",
+	Short:        "Stop placing new machines on a node",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// cordon node
+		if _, err := client.CordonNode(ctx, &api.CordonNodeRequest{
+			Name: args[0],
+		}); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+var nodesUncordonCmd = cobra.Command{
+	Use:          "uncordon [name]",
+	Short:        "Allow placing new machines on a node again",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// uncordon node
+		if _, err := client.UncordonNode(ctx, &api.UncordonNodeRequest{
+			Name: args[0],
+		}); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+var nodesDryRun bool
+
+// printMoves prints a plan of machine moves in table format.
+func printMoves(moves []*api.MachineMove) {
+	tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+	defer tw.Flush()
+
+	fmt.Fprintf(tw, "MACHINE\tNAME\tFROM\tTO\tMETHOD\tBLOCKED\n")
+	for _, move := range moves {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", move.MachineId, move.MachineName, move.SourceNode, move.TargetNode, move.Method, move.BlockedReason)
+	}
+}
+
+var nodesDrainCmd = cobra.Command{
+	Use:          "drain [name]",
+	Short:        "Cordon a node and move its machines to other nodes",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// drain node
+		resp, err := client.DrainNode(ctx, &api.DrainNodeRequest{
+			Name:   args[0],
+			DryRun: nodesDryRun,
+		})
+		if err != nil {
+			return err
+		}
+		printMoves(resp.Moves)
+		return nil
+	},
+}
+
+var nodesRebalanceCmd = cobra.Command{
+	Use:          "rebalance",
+	Short:        "Move machines to even out utilisation across nodes",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// rebalance nodes
+		resp, err := client.Rebalance(ctx, &api.RebalanceRequest{
+			DryRun: nodesDryRun,
+		})
+		if err != nil {
+			return err
+		}
+		printMoves(resp.Moves)
+		return nil
+	},
+}
+
+var usersCmd = cobra.Command{
+	Use:          "users",
+	Short:        "Manage users",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// list users
+		resp, err := client.ListUsers(ctx, &api.ListUsersRequest{})
+		if err != nil {
+			return err
+		}
+		if listIdsOnly {
+			for i := range resp.Users {
+				fmt.Println(resp.Users[i].Id)
+			}
+			return nil
+		}
+		// print out users in table format
+		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "ID\tNAME\tADMIN\n")
+		for _, user := range resp.Users {
+			fmt.Fprintf(tw, "%s\t%s\t%t\n", user.Id, user.Name, user.Admin)
+		}
+		return nil
+	},
+}
+
+var usersCreateAdmin bool
+
+var usersCreateCmd = cobra.Command{
+	Use:          "create [name]",
+	Short:        "Create a user",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// create user
+		resp, err := client.CreateUser(ctx, &api.CreateUserRequest{
+			Name:  args[0],
+			Admin: usersCreateAdmin,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(resp.Id)
+		return nil
+	},
+}
+
+var usersDeleteCmd = cobra.Command{
+	Use:          "delete [id]",
+	Short:        "Delete a user and its memberships",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// delete user
+		_, err = client.DeleteUser(ctx, &api.DeleteUserRequest{
+			Id: args[0],
+		})
+		return err
+	},
+}
+
+var projectsCmd = cobra.Command{
+	Use:          "projects",
+	Short:        "Manage projects and their members",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// list projects
+		resp, err := client.ListProjects(ctx, &api.ListProjectsRequest{})
+		if err != nil {
+			return err
+		}
+		if listIdsOnly {
+			for i := range resp.Projects {
+				fmt.Println(resp.Projects[i].Id)
+			}
+			return nil
+		}
+		// print out projects in table format
+		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "ID\tNAME\n")
+		for _, project := range resp.Projects {
+			fmt.Fprintf(tw, "%s\t%s\n", project.Id, project.Name)
+		}
+		return nil
+	},
+}
+
+var projectsCreateCmd = cobra.Command{
+	Use:          "create [name]",
+	Short:        "Create a project",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// create project
+		resp, err := client.CreateProject(ctx, &api.CreateProjectRequest{
+			Name: args[0],
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(resp.Id)
+		return nil
+	},
+}
+
+var projectsDeleteCmd = cobra.Command{
+	Use:          "delete [id]",
+	Short:        "Delete a project without resources",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// delete project
+		_, err = client.DeleteProject(ctx, &api.DeleteProjectRequest{
+			Id: args[0],
+		})
+		return err
+	},
+}
+
+var projectsMembersCmd = cobra.Command{
+	Use:          "members [project]",
+	Short:        "List the members of a project",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// list memberships
+		resp, err := client.ListMemberships(ctx, &api.ListMembershipsRequest{
+			ProjectId: args[0],
+		})
+		if err != nil {
+			return err
+		}
+		// print out members in table format
+		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "USER\tNAME\tROLE\n")
+		for _, membership := range resp.Memberships {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", membership.UserId, membership.UserName, membership.Role)
+		}
+		return nil
+	},
+}
+
+var projectsAddMemberRole string
+
+var projectsAddMemberCmd = cobra.Command{
+	Use:          "add-member [project] [user]",
+	Short:        "Add a user to a project or change its role",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		role, ok := api.Role_value[strings.ToUpper(projectsAddMemberRole)]
+		if !ok || role == int32(api.Role_ROLE_UNSPECIFIED) {
+			return fmt.Errorf("unknown role %s", projectsAddMemberRole)
+		}
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// set membership
+		_, err = client.SetMembership(ctx, &api.SetMembershipRequest{
+			ProjectId: args[0],
+			UserId:    args[1],
+			Role:      api.Role(role),
+		})
+		return err
+	},
+}
+
+var projectsQuotaCmd = cobra.Command{
+	Use:          "quota [project]",
+	Short:        "Show the quota and resource usage of a project, defaults to the selected project",
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// get quota usage
+		request := &api.GetQuotaUsageRequest{}
+		if len(args) > 0 {
+			request.ProjectId = args[0]
+		}
+		resp, err := client.GetQuotaUsage(ctx, request)
+		if err != nil {
+			return err
+		}
+		// print out usage in table format
+		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		limit := func(value int64) string {
+			if value == 0 {
+				return "unlimited"
+			}
+			return strconv.FormatInt(value, 10)
+		}
+		fmt.Fprintf(tw, "RESOURCE\tUSED\tLIMIT\n")
+		fmt.Fprintf(tw, "vCPUs\t%d\t%s\n", resp.Usage.Cpus, limit(resp.Quota.Cpus))
+		fmt.Fprintf(tw, "Memory (MB)\t%d\t%s\n", resp.Usage.Memory, limit(resp.Quota.Memory))
+		fmt.Fprintf(tw, "Disk (GB)\t%d\t%s\n", resp.Usage.Disk, limit(resp.Quota.Disk))
+		fmt.Fprintf(tw, "Machines\t%d\t%s\n", resp.Usage.Machines, limit(resp.Quota.Machines))
+		fmt.Fprintf(tw, "Floating IPs\t%d\t%s\n", resp.Usage.FloatingIps, limit(resp.Quota.FloatingIps))
+		fmt.Fprintf(tw, "Networks\t%d\t%s\n", resp.Usage.Networks, limit(resp.Quota.Networks))
+		return nil
+	},
+}
+
+var projectsSetQuota api.Quota
+
+var projectsSetQuotaCmd = cobra.Command{
+	Use:          "set-quota [project]",
+	Short:        "Set the quota of a project, limits of zero are not enforced",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// set quota
+		_, err = client.SetQuota(ctx, &api.SetQuotaRequest{
+			ProjectId: args[0],
+			Quota:     &projectsSetQuota,
+		})
+		return err
+	},
+}
+
+var projectsRemoveMemberCmd = cobra.Command{
+	Use:          "remove-member [project] [user]",
+	Short:        "Remove a user from a project",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// remove membership
+		_, err = client.RemoveMembership(ctx, &api.RemoveMembershipRequest{
+			ProjectId: args[0],
+			UserId:    args[1],
+		})
+		return err
+	},
+}
+
+var loginCmd = cobra.Command{
+	Use:          "login",
+	Short:        "Check the token and store it along with the connection flags",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed("token") {
+			fmt.Fprint(os.Stderr, "Token: ")
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil {
+				return fmt.Errorf("read token: %w", err)
+			}
+			token = strings.TrimSpace(line)
+		}
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// check token
+		resp, err := client.GetCurrentUser(ctx, &api.GetCurrentUserRequest{})
+		if err != nil {
+			return err
+		}
+		path, err := saveConfig(cliConfig{
+			Endpoint: endpoint,
+			Token:    token,
+			CA:       caFile,
+			Cert:     certFile,
+			Key:      keyFile,
+			Insecure: insecure,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Logged in as %s, stored token in %s\n", resp.User.Name, path)
+		return nil
+	},
+}
+
+var logoutCmd = cobra.Command{
+	Use:          "logout",
+	Short:        "Remove the stored token",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := loadConfig()
+		cfg.Token = ""
+		_, err := saveConfig(cfg)
+		return err
+	},
+}
+
+var whoamiCmd = cobra.Command{
+	Use:          "whoami",
+	Short:        "Show the user requests are made as",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// get current user
+		resp, err := client.GetCurrentUser(ctx, &api.GetCurrentUserRequest{})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("User:   %s (%s)\n", resp.User.Name, resp.User.Id)
+		fmt.Printf("Admin:  %t\n", resp.User.Admin)
+		if len(resp.Scopes) > 0 {
+			fmt.Printf("Scopes: %s\n", formatScopes(resp.Scopes))
+		}
+		// print out memberships in table format
+		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "\nPROJECT\tROLE\n")
+		for _, membership := range resp.Memberships {
+			fmt.Fprintf(tw, "%s\t%s\n", membership.ProjectId, membership.Role)
+		}
+		return nil
+	},
+}
+
+var tokensUser string
+
+var tokensCmd = cobra.Command{
+	Use:          "tokens",
+	Short:        "Manage API tokens",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// list tokens
+		resp, err := client.ListTokens(ctx, &api.ListTokensRequest{
+			UserId: tokensUser,
+		})
+		if err != nil {
+			return err
+		}
+		if listIdsOnly {
+			for i := range resp.Tokens {
+				fmt.Println(resp.Tokens[i].Id)
+			}
+			return nil
+		}
+		// print out tokens in table format
+		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "ID\tNAME\tSCOPES\tCREATED\tEXPIRES\n")
+		for _, t := range resp.Tokens {
+			scopes, expires := "all", "never"
+			if len(t.Scopes) > 0 {
+				scopes = formatScopes(t.Scopes)
+			}
+			if t.ExpiresAt != nil {
+				expires = humanize.Time(t.ExpiresAt.AsTime())
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.Id, t.Name, scopes, humanize.Time(t.CreatedAt.AsTime()), expires)
+		}
+		return nil
+	},
+}
+
+var (
+	tokensCreateScopes []string
+	tokensCreateTTL    time.Duration
+)
+
+var tokensCreateCmd = cobra.Command{
+	Use:          "create [name]",
+	Short:        "Create an API token, its secret is only shown once",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scopes := make([]api.Token_Scope, len(tokensCreateScopes))
+		for i, name := range tokensCreateScopes {
+			scope, ok := api.Token_Scope_value[strings.ToUpper(name)]
+			if !ok || scope == int32(api.Token_SCOPE_UNSPECIFIED) {
+				return fmt.Errorf("unknown scope %s", name)
+			}
+			scopes[i] = api.Token_Scope(scope)
+		}
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// create token
+		resp, err := client.CreateToken(ctx, &api.CreateTokenRequest{
+			Name:   args[0],
+			Scopes: scopes,
+			Ttl:    int64(tokensCreateTTL.Seconds()),
+			UserId: tokensUser,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(resp.Secret)
+		return nil
+	},
+}
+
+var tokensDeleteCmd = cobra.Command{
+	Use:          "delete [id]",
+	Short:        "Delete an API token",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// delete token
+		_, err = client.DeleteToken(ctx, &api.DeleteTokenRequest{
+			Id: args[0],
+		})
+		return err
+	},
+}
+
+func formatScopes(scopes []api.Token_Scope) string {
+	names := make([]string, len(scopes))
+	for i := range scopes {
+		names[i] = strings.ToLower(scopes[i].String())
+	}
+	return strings.Join(names, ",")
+}
+
+var webhooksCmd = cobra.Command{
+	Use:          "webhooks",
+	Short:        "Manage webhooks receiving events",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// list webhooks
+		resp, err := client.ListWebhooks(ctx, &api.ListWebhooksRequest{})
+		if err != nil {
+			return err
+		}
+		if listIdsOnly {
+			for i := range resp.Webhooks {
+				fmt.Println(resp.Webhooks[i].Id)
+			}
+			return nil
+		}
+		// print out webhooks in table format
+		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "ID\tNAME\tURL\tEVENTS\tCREATED\n")
+		for _, hook := range resp.Webhooks {
+			var filter []string
+			for _, t := range hook.Filter.GetTypes() {
+				filter = append(filter, t.String())
+			}
+			for _, t := range hook.Filter.GetActivityTypes() {
+				filter = append(filter, t.String())
+			}
+			for _, s := range hook.Filter.GetMachineStatuses() {
+				filter = append(filter, s.String())
+			}
+			events := "all"
+			if len(filter) > 0 {
+				events = strings.Join(filter, ",")
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", hook.Id, hook.Name, hook.Url, events, humanize.Time(hook.CreatedAt.AsTime()))
+		}
+		return nil
+	},
+}
+
+var (
+	webhooksCreateTypes      []string
+	webhooksCreateActivities []string
+	webhooksCreateStatuses   []string
+	webhooksCreateSecret     string
+)
+
+var webhooksCreateCmd = cobra.Command{
+	Use:          "create [name] [url]",
+	Short:        "Create a webhook, its secret is only shown once",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := &api.Webhook_Filter{}
+		for _, name := range webhooksCreateTypes {
+			eventType, ok := api.Event_Type_value[strings.ToUpper(name)]
+			if !ok || eventType == int32(api.Event_TYPE_UNSPECIFIED) {
+				return fmt.Errorf("unknown event type %s", name)
+			}
+			filter.Types = append(filter.Types, api.Event_Type(eventType))
+		}
+		for _, name := range webhooksCreateActivities {
+			activityType, ok := api.Activity_Type_value[strings.ToUpper(name)]
+			if !ok || activityType == int32(api.Activity_UNKNOWN) {
+				return fmt.Errorf("unknown activity type %s", name)
+			}
+			filter.ActivityTypes = append(filter.ActivityTypes, api.Activity_Type(activityType))
+		}
+		for _, name := range webhooksCreateStatuses {
+			machineStatus, ok := api.Machine_Status_value[strings.ToUpper(name)]
+			if !ok {
+				return fmt.Errorf("unknown machine status %s", name)
+			}
+			filter.MachineStatuses = append(filter.MachineStatuses, api.Machine_Status(machineStatus))
+		}
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// create webhook
+		resp, err := client.CreateWebhook(ctx, &api.CreateWebhookRequest{
+			Name:   args[0],
+			Url:    args[1],
+			Filter: filter,
+			Secret: webhooksCreateSecret,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(resp.Webhook.Id)
+		if webhooksCreateSecret == "" {
+			fmt.Println(resp.Secret)
+		}
+		return nil
+	},
+}
+
+var webhooksDeleteCmd = cobra.Command{
+	Use:          "delete [id]",
+	Short:        "Delete a webhook along with its deliveries",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// delete webhook
+		_, err = client.DeleteWebhook(ctx, &api.DeleteWebhookRequest{
+			Id: args[0],
+		})
+		return err
+	},
+}
+
+var (
+	webhooksDeliveriesStatus    string
+	webhooksDeliveriesLimit     int32
+	webhooksDeliveriesPageToken string
+)
+
+var webhooksDeliveriesCmd = cobra.Command{
+	Use:          "deliveries [id]",
+	Short:        "List the deliveries of a webhook, newest first",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		request := &api.ListWebhookDeliveriesRequest{
+			WebhookId: args[0],
+			PageSize:  webhooksDeliveriesLimit,
+			PageToken: webhooksDeliveriesPageToken,
+		}
+		if webhooksDeliveriesStatus != "" {
+			deliveryStatus, ok := api.WebhookDelivery_Status_value[strings.ToUpper(webhooksDeliveriesStatus)]
+			if !ok || deliveryStatus == int32(api.WebhookDelivery_STATUS_UNSPECIFIED) {
+				return fmt.Errorf("unknown delivery status %s", webhooksDeliveriesStatus)
+			}
+			request.Status = api.WebhookDelivery_Status(deliveryStatus)
+		}
+		client, err := connect()
+		if err != nil {
+			return err
+		}
+		// create context
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// list deliveries
+		resp, err := client.ListWebhookDeliveries(ctx, request)
+		if err != nil {
+			return err
+		}
+		// print out deliveries in table format
+		tw := tabwriter.NewWriter(os.Stdout, 1, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "ID\tCREATED\tEVENT\tSUBJECT\tSTATUS\tATTEMPTS\tRESPONSE\tNEXT ATTEMPT\tERROR\n")
+		for _, d := range resp.Deliveries {
+			next := "-"
+			if d.NextAttemptAt != nil {
+				next = humanize.Time(d.NextAttemptAt.AsTime())
+			}
+			fmt.Fprintf(
+				tw,
+				"%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+				d.Id, humanize.Time(d.CreatedAt.AsTime()), d.Event.GetType(), d.Event.GetSubject(),
+				d.Status, d.Attempts, d.ResponseCode, next, d.Error,
+			)
+		}
+		if resp.NextPageToken != "" {
+			fmt.Fprintf(os.Stderr, "more deliveries are listed with --page-token %s\n", resp.NextPageToken)
+		}
+		return nil
+	},
+}
+
+var (
+	webhooksReceiveSecret string
+	webhooksReceiveStatus int
+)
+
+var webhooksReceiveCmd = cobra.Command{
+	Use:          "receive [address]",
+	Short:        "Serve a local endpoint printing the deliveries it receives, to try out webhooks",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+			if err != nil {
+				http.Error(w, "could not read body", http.StatusBadRequest)
+				return
+			}
+			delivery, event := r.Header.Get(webhooks.DeliveryHeader), r.Header.Get(webhooks.EventHeader)
+			if webhooksReceiveSecret != "" && !webhooks.Verify(webhooksReceiveSecret, body, r.Header.Get(webhooks.SignatureHeader)) {
+				fmt.Fprintf(os.Stderr, "rejected delivery %s with invalid signature\n", delivery)
+				http.Error(w, "invalid signature", http.StatusUnauthorized)
+				return
+			}
+			fmt.Printf("%s delivery %s of %s\n%s\n", time.Now().Format(time.RFC3339), delivery, event, body)
+			w.WriteHeader(webhooksReceiveStatus)
+		})
+		fmt.Fprintf(os.Stderr, "receiving deliveries at %s\n", args[0])
+		return http.ListenAndServe(args[0], handler)
+	},
+}
+
+// cliConfig is stored by login and provides the defaults of the connection flags.
+type cliConfig struct {
+	Endpoint string `toml:"endpoint"`
+	Token    string `toml:"token"`
+	CA       string `toml:"ca"`
+	Cert     string `toml:"cert"`
+	Key      string `toml:"key"`
+	Insecure bool   `toml:"insecure"`
+}
+
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("find config directory: %w", err)
+	}
+	return filepath.Join(dir, "sox", "cli.toml"), nil
+}
+
+func loadConfig() cliConfig {
+	var cfg cliConfig
+	if path, err := configPath(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			if err := toml.Unmarshal(data, &cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "ignoring invalid config %s: %v\n", path, err)
+				cfg = cliConfig{}
+			}
+		}
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "localhost:9876"
+	}
+	return cfg
+}
+
+func saveConfig(cfg cliConfig) (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	data, err := toml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("create config directory: %w", err)
+	}
+	// the token grants access to the API
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("write config: %w", err)
+	}
+	return path, nil
+}
+
+func init() {
+	cfg := loadConfig()
+	rootCmd.PersistentFlags().StringVarP(&endpoint, "endpoint", "p", cfg.Endpoint, "VirtM endpoint address")
+	rootCmd.PersistentFlags().BoolVar(&insecure, "insecure", cfg.Insecure, "Connect without TLS")
+	rootCmd.PersistentFlags().StringVar(&token, "token", "", "API token to authenticate with, defaults to the token stored by login")
+	token = cfg.Token
+	rootCmd.PersistentFlags().StringVar(&caFile, "ca", cfg.CA, "CA to verify the endpoint with, defaults to the system roots")
+	rootCmd.PersistentFlags().StringVar(&certFile, "cert", cfg.Cert, "Client certificate to authenticate with")
+	rootCmd.PersistentFlags().StringVar(&keyFile, "key", cfg.Key, "Key of the client certificate")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", time.Minute, "Client connection timeout")
+	rootCmd.PersistentFlags().StringVar(&node, "node", "", "Node to send requests to when talking to an aggregator")
+	rootCmd.PersistentFlags().StringVar(&user, "as", "", "User to make requests as, admins may act on behalf of other users")
+	rootCmd.PersistentFlags().StringVar(&project, "project", "", "Project to create and list resources in, defaults to all projects of the user")
+	rootCmd.AddCommand(&loginCmd)
+	rootCmd.AddCommand(&logoutCmd)
+	rootCmd.AddCommand(&whoamiCmd)
+	rootCmd.AddCommand(&tokensCmd)
+	tokensCmd.PersistentFlags().StringVar(&tokensUser, "user", "", "User to manage tokens of, defaults to the current user")
+	tokensCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
+	tokensCmd.AddCommand(&tokensCreateCmd)
+	tokensCreateCmd.Flags().StringSliceVar(&tokensCreateScopes, "scope", nil, "Scopes of the token, any of read, write, members or admin, defaults to the scopes of the caller")
+	tokensCreateCmd.Flags().DurationVar(&tokensCreateTTL, "ttl", 0, "Validity of the token, zero for tokens without expiry")
+	tokensCmd.AddCommand(&tokensDeleteCmd)
+	rootCmd.AddCommand(&usersCmd)
+	usersCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
+	usersCmd.AddCommand(&usersCreateCmd)
+	usersCreateCmd.Flags().BoolVar(&usersCreateAdmin, "admin", false, "Allow the user to act in all projects and manage users and projects")
+	usersCmd.AddCommand(&usersDeleteCmd)
+	rootCmd.AddCommand(&projectsCmd)
+	projectsCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
+	projectsCmd.AddCommand(&projectsCreateCmd)
+	projectsCmd.AddCommand(&projectsDeleteCmd)
+	projectsCmd.AddCommand(&projectsMembersCmd)
+	projectsCmd.AddCommand(&projectsAddMemberCmd)
+	projectsAddMemberCmd.Flags().StringVarP(&projectsAddMemberRole, "role", "r", "viewer", "Role of the user, one of viewer, operator or admin")
+	projectsCmd.AddCommand(&projectsRemoveMemberCmd)
+	projectsCmd.AddCommand(&projectsQuotaCmd)
+	projectsCmd.AddCommand(&projectsSetQuotaCmd)
+	projectsSetQuotaCmd.Flags().Int64Var(&projectsSetQuota.Cpus, "cpus", 0, "Number of vCPUs")
+	projectsSetQuotaCmd.Flags().Int64Var(&projectsSetQuota.Memory, "memory", 0, "Memory in MB")
+	projectsSetQuotaCmd.Flags().Int64Var(&projectsSetQuota.Disk, "disk", 0, "Disk space in GB")
+	projectsSetQuotaCmd.Flags().Int64Var(&projectsSetQuota.Machines, "machines", 0, "Number of machines")
+	projectsSetQuotaCmd.Flags().Int64Var(&projectsSetQuota.FloatingIps, "floating-ips", 0, "Number of floating IPs")
+	projectsSetQuotaCmd.Flags().Int64Var(&projectsSetQuota.Networks, "networks", 0, "Number of networks")
+	rootCmd.AddCommand(&imagesCmd)
+	imagesCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
+	rootCmd.AddCommand(&sshKeysCmd)
+	sshKeysCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
+	rootCmd.AddCommand(&machinesCmd)
+	machinesCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
+	rootCmd.AddCommand(&networksCmd)
+	networksCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
+	networksCmd.AddCommand(&networksCreateCmd)
+	networksCreateCmd.Flags().StringVarP(&networksCreateName, "name", "n", "", "Unique name of the network")
+	networksCreateCmd.Flags().Uint32VarP(&networksCreateBridgeId, "bridge-id", "b", 100, "VXLAN ID to attach to")
+	networksCreateCmd.Flags().StringVar(&networksCreateIPV4Subnet, "ipv4-subnet", "10.0.0.0/24", "IPv4 subnet")
+	networksCreateCmd.Flags().StringVar(&networksCreateIPV4Gateway, "ipv4-gateway", "", "IPv4 gateway")
+	networksCreateCmd.Flags().StringVar(&networksCreateIPV6Subnet, "ipv6-subnet", "", "IPv6 subnet")
+	networksCreateCmd.Flags().StringVar(&networksCreateIPV6Gateway, "ipv6-gateway", "", "IPv6 gateway")
+	networksCreateCmd.Flags().StringVarP(&networksCreateMode, "mode", "m", "", "Network mode, one of nat, routed, isolated, bridged or vxlan")
+	networksCreateCmd.Flags().StringVar(&networksCreatePhysicalDevice, "physical-device", "", "Host NIC to bridge guests to in bridged mode")
+	networksCreateCmd.Flags().Uint32Var(&networksCreateMTU, "mtu", 0, "MTU of the network, defaults to the transport MTU minus VXLAN overhead up to 1500")
+	addQoSFlags(&networksCreateCmd)
+	rootCmd.AddCommand(&activityCmd)
+	activityCmd.Flags().StringVarP(&activitySubject, "subject", "s", "", "Only list activities of the subject")
+	activityCmd.Flags().StringSliceVarP(&activityTypes, "type", "t", nil, "Only list activities of these types")
+	activityCmd.Flags().DurationVar(&activitySince, "since", 0, "Only list activities of this recent period")
+	activityCmd.Flags().Int32VarP(&activityLimit, "limit", "l", 0, "Number of activities per page, defaults to 100")
+	activityCmd.Flags().StringVar(&activityPageToken, "page-token", "", "Token of the page to list")
+	activityCmd.Flags().BoolVar(&activityOldestFirst, "oldest-first", false, "List the oldest activities first")
+	activityCmd.Flags().BoolVar(&activityDetails, "details", false, "Show source address, method and parameters of requests")
+	rootCmd.AddCommand(&webhooksCmd)
+	webhooksCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
+	webhooksCmd.AddCommand(&webhooksCreateCmd)
+	webhooksCreateCmd.Flags().StringSliceVarP(&webhooksCreateTypes, "type", "t", nil, "Only deliver events of these types")
+	webhooksCreateCmd.Flags().StringSliceVar(&webhooksCreateActivities, "activity", nil, "Only deliver activities of these types")
+	webhooksCreateCmd.Flags().StringSliceVar(&webhooksCreateStatuses, "machine-status", nil, "Only deliver machine state changes to these statuses")
+	webhooksCreateCmd.Flags().StringVar(&webhooksCreateSecret, "secret", "", "Secret deliveries are signed with, generated if empty")
+	webhooksCmd.AddCommand(&webhooksDeleteCmd)
+	webhooksCmd.AddCommand(&webhooksDeliveriesCmd)
+	webhooksDeliveriesCmd.Flags().StringVarP(&webhooksDeliveriesStatus, "status", "s", "", "Only list deliveries with this status, either pending, delivered or failed")
+	webhooksDeliveriesCmd.Flags().Int32VarP(&webhooksDeliveriesLimit, "limit", "l", 0, "Number of deliveries per page, defaults to 100")
+	webhooksDeliveriesCmd.Flags().StringVar(&webhooksDeliveriesPageToken, "page-token", "", "Token of the page to list")
+	webhooksCmd.AddCommand(&webhooksReceiveCmd)
+	webhooksReceiveCmd.Flags().StringVar(&webhooksReceiveSecret, "secret", "", "Reject deliveries not signed with this secret")
+	webhooksReceiveCmd.Flags().IntVar(&webhooksReceiveStatus, "status", http.StatusNoContent, "Status code to respond with, to try out retries")
+	rootCmd.AddCommand(&eventsCmd)
+	eventsCmd.Flags().BoolVarP(&eventsFollow, "follow", "f", false, "Keep watching until interrupted instead of until the timeout")
+	eventsCmd.Flags().StringVar(&eventsResumeToken, "resume-token", "", "Continue after the event with this token")
+	eventsCmd.Flags().StringSliceVarP(&eventsTypes, "type", "t", nil, "Only watch events of these types")
+	rootCmd.AddCommand(&nodesCmd)
+	nodesCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
+	nodesCmd.AddCommand(&nodesHostCmd)
+	nodesCmd.AddCommand(&nodesCordonCmd)
+	nodesCmd.AddCommand(&nodesUncordonCmd)
+	nodesCmd.AddCommand(&nodesDrainCmd)
+	nodesDrainCmd.Flags().BoolVar(&nodesDryRun, "dry-run", false, "Only print the planned moves")
+	nodesCmd.AddCommand(&nodesRebalanceCmd)
+	nodesRebalanceCmd.Flags().BoolVar(&nodesDryRun, "dry-run", false, "Only print the planned moves")
+	rootCmd.AddCommand(&vpnCmd)
+	vpnCmd.AddCommand(&vpnPeersCmd)
+	vpnPeersCmd.Flags().BoolVarP(&listIdsOnly, "ids-only", "1", false, "Only display IDs")
+	vpnPeersCmd.AddCommand(&vpnPeersAddCmd)
+	vpnPeersCmd.AddCommand(&vpnPeersDeleteCmd)
+	vpnPeersAddCmd.Flags().StringArrayVarP(&vpnPeersAddNetworks, "networks", "n", nil, "Networks the peer may access")
+	vpnPeersAddCmd.Flags().StringVar(&vpnPeersAddPublicKey, "public-key", "", "Public key of the peer, generates a key pair if empty")
+	machinesCmd.AddCommand(&machinesCreateCmd)
+	machinesCmd.AddCommand(&machinesInspectCmd)
+	machinesCmd.AddCommand(&machinesTopCmd)
+	machinesTopCmd.Flags().DurationVarP(&machinesTopInterval, "interval", "n", 10*time.Second, "Time between refreshes")
+	machinesTopCmd.Flags().BoolVar(&machinesTopOnce, "once", false, "Print the usage once instead of refreshing until interrupted")
+	machinesCmd.AddCommand(&machinesDeleteCmd)
+	machinesCmd.AddCommand(&machinesStartCmd)
+	machinesCmd.AddCommand(&machinesStopCmd)
+	machinesCmd.AddCommand(&machinesRebootCmd)
+	machinesCmd.AddCommand(&machinesExposeCmd)
+	machinesCmd.AddCommand(&machinesQoSCmd)
+	addQoSFlags(&machinesQoSCmd)
+	machinesCmd.AddCommand(&machinesMigrateCmd)
+	machinesMigrateCmd.Flags().BoolVar(&machinesMigrateLive, "live", false, "Migrate the running machine without stopping it")
+	machinesMigrateCmd.Flags().BoolVar(&machinesMigrateCopyStorage, "copy-storage", true, "Copy the disk to the target node")
+	machinesExposeCmd.Flags().StringVar(&machinesExposeHostIP, "host-ip", "", "Host address to listen on, defaults to all local addresses")
+	machinesExposeCmd.Flags().StringVarP(&machinesExposeNetwork, "network", "n", "", "NAT network of the machine to forward to")
+	machinesExposeCmd.Flags().StringVar(&machinesExposeProtocol, "protocol", "tcp", "Protocol to forward, either tcp or udp")
+	machinesCreateCmd.Flags().StringVarP(&machinesCreateImage, "image", "i", "", "Operating system image")
+	machinesCreateCmd.Flags().StringArrayVarP(&machinesCreateSSHKeys, "ssh-keys", "k", nil, "SSH keys for login")
+	machinesCreateCmd.Flags().StringArrayVarP(&machinesCreateNetworks, "networks", "n", nil, "Network to connect to")
+	machinesCreateCmd.Flags().Int64Var(&machinesCreateCpu, "cpu", 2, "Number of vCPUs")
+	machinesCreateCmd.Flags().Int64Var(&machinesCreateDisk, "disk", 10, "Disk size in GB")
+	machinesCreateCmd.Flags().Int64Var(&machinesCreateMemory, "memory", 2000, "Memory size in MB")
+	machinesCreateCmd.Flags().StringVarP(&machinesCreateUser, "user", "u", "ken", "User account to be created")
+	machinesCreateCmd.Flags().StringToStringVar(&machinesCreateNodeLabels, "node-label", nil, "Only place on nodes carrying the label")
+	machinesCreateCmd.MarkFlagRequired("image")
+	machinesCreateCmd.MarkFlagRequired("ssh-key")
+	machinesCreateCmd.MarkFlagRequired("networks")
+}
+
+func connect() (api.SoxClient, error) {
+	var grpcOpts []grpc.DialOption
+	if insecure {
+		grpcOpts = append(grpcOpts, grpc.WithInsecure())
+	} else {
+		tlsConfig, err := auth.ClientConfig(certFile, keyFile, caFile)
+		if err != nil {
+			return nil, err
+		}
+		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+	grpcOpts = append(grpcOpts, grpc.WithUnaryInterceptor(withMetadata), grpc.WithStreamInterceptor(withStreamMetadata))
+	grpcClient, err := grpc.Dial(endpoint, grpcOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("dial endpoint: %w", err)
+	}
+	return api.NewSoxClient(grpcClient), nil
+}
+
+// withMetadata passes the token, user and project of the request and tells an aggregator
+// which node the request is meant for.
+func withMetadata(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+}
+
+// withStreamMetadata passes the same metadata as withMetadata on streaming calls.
+func withStreamMetadata(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+}
+
+func outgoingContext(ctx context.Context) context.Context {
+	ctx = auth.NewOutgoingContext(ctx, auth.Identity{User: user, Project: project, Token: token})
+	if node != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "sox-node", node)
 	}
-	return api.NewVirtMClient(grpcClient), nil
+	return ctx
 }
 
 func main() {
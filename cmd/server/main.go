@@ -1,18 +1,27 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/lnsp/sox/aggregator"
 	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
 	"github.com/lnsp/sox/driver"
 	"github.com/lnsp/sox/meta"
+	"github.com/lnsp/sox/metrics"
 	"github.com/pelletier/go-toml"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 type config struct {
@@ -20,15 +29,84 @@ type config struct {
 		DSN string
 	}
 	Libvirt struct {
-		URI     string
-		Network string
-		Storage string
+		URI          string
+		Network      string
+		Storage      string
+		MigrationURI string `toml:"migration_uri"`
+	}
+	Activities struct {
+		Retention string
+	}
+	Webhooks struct {
+		MaxAttempts   int    `toml:"max_attempts"`
+		RetryDelay    string `toml:"retry_delay"`
+		MaxRetryDelay string `toml:"max_retry_delay"`
+		AllowPrivate  bool   `toml:"allow_private"`
+	}
+	Overcommit struct {
+		CPU    float64
+		Memory float64
+		Disk   float64
 	}
 	Grpc struct {
 		Address string
 	}
+	Metrics struct {
+		Address string
+	}
+	TLS struct {
+		Cert              string
+		Key               string
+		CA                string
+		RequireClientCert bool `toml:"require_client_cert"`
+	}
+	Auth struct {
+		Required bool
+		// Insecure allows starting without TLS or without required authentication.
+		Insecure bool
+	}
+	DNS struct {
+		Address string
+	}
+	Floating struct {
+		Pool   string
+		Device string
+	}
+	VPN struct {
+		Device   string
+		Range    string
+		Endpoint string
+		Port     int
+		Key      string
+	}
+	Cluster struct {
+		Role       string
+		Name       string
+		Advertise  string
+		Aggregator string
+		Heartbeat  string
+		// Expiry removes nodes from the aggregator after missing heartbeats for this long.
+		Expiry    string
+		Placement string
+		Labels    map[string]string
+		// NodeIdentities may register nodes of any name, InsecureJoin accepts nodes without certificate.
+		NodeIdentities []string `toml:"node_identities"`
+		InsecureJoin   bool     `toml:"insecure_join"`
+		// SyncUser is the admin aggregators replicate users, projects and quotas as, their certificate is used if empty.
+		SyncUser string `toml:"sync_user"`
+	}
 }
 
+const (
+	roleNode       = "node"
+	roleAggregator = "aggregator"
+)
+
+const (
+	defaultHeartbeat = 5 * time.Second
+	defaultExpiry    = time.Hour
+)
+
 var rootCmd = cobra.Command{
 	Use:     "virtm [config]",
 	Short:   "Experimental virtual machine manager",
@@ -39,6 +117,40 @@ var rootCmd = cobra.Command{
 	},
 }
 
+var caDir string
+
+var caCmd = cobra.Command{
+	Use:   "ca",
+	Short: "Manage a local certificate authority",
+}
+
+var caInitCmd = cobra.Command{
+	Use:   "init",
+	Short: "Create a new certificate authority",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := auth.InitCA(caDir); err != nil {
+			log.Fatalf("failed to create CA: %v", err)
+		}
+		log.Println("created CA in", caDir)
+	},
+}
+
+var caIssueHosts []string
+
+var caIssueCmd = cobra.Command{
+	Use:   "issue [name]",
+	Short: "Issue a certificate, the name identifies the user of client certificates",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		certFile, keyFile, err := auth.Issue(caDir, args[0], caIssueHosts)
+		if err != nil {
+			log.Fatalf("failed to issue certificate: %v", err)
+		}
+		log.Println("issued certificate", certFile, "with key", keyFile)
+	},
+}
+
 func run(cfgpath string) {
 	// load config
 	cfgdata, err := os.ReadFile(cfgpath)
@@ -50,25 +162,95 @@ func run(cfgpath string) {
 	if err := toml.Unmarshal(cfgdata, &cfg); err != nil {
 		log.Fatalf("failed to decode config: %v", err)
 	}
-	// start vm manager
-	driver, err := driver.New(&driver.Config{
-		DB:                  cfg.Database.DSN,
-		StoragePool:         cfg.Libvirt.Storage,
-		NetworkTransportDev: cfg.Libvirt.Network,
-		LibvirtURI:          cfg.Libvirt.URI,
-	})
-	if err != nil {
-		log.Fatalf("failed to start driver: %v", err)
+	heartbeat := defaultHeartbeat
+	if cfg.Cluster.Heartbeat != "" {
+		if heartbeat, err = time.ParseDuration(cfg.Cluster.Heartbeat); err != nil {
+			log.Fatalf("failed to parse heartbeat interval: %v", err)
+		}
+	}
+	var (
+		server api.SoxServer
+		opts   []grpc.ServerOption
+		// node is nil on aggregators
+		node *driver.Driver
+	)
+	// connections to other nodes and the aggregator use the same certificate
+	dial := grpc.WithInsecure()
+	if cfg.TLS.Cert != "" {
+		serverTLS, err := auth.ServerConfig(cfg.TLS.Cert, cfg.TLS.Key, cfg.TLS.CA, cfg.TLS.RequireClientCert)
+		if err != nil {
+			log.Fatalf("failed to load TLS config: %v", err)
+		}
+		clientTLS, err := auth.ClientConfig(cfg.TLS.Cert, cfg.TLS.Key, cfg.TLS.CA)
+		if err != nil {
+			log.Fatalf("failed to load TLS config: %v", err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(serverTLS)))
+		dial = grpc.WithTransportCredentials(credentials.NewTLS(clientTLS))
+	} else if cfg.Auth.Required {
+		log.Println("authentication is required, but tokens are sent unencrypted without TLS")
+	}
+	// Otherwise anyone reaching the server could act as any user or read its tokens
+	if (cfg.TLS.Cert == "" || !cfg.Auth.Required) && !cfg.Auth.Insecure {
+		log.Fatalf("refusing to start without TLS and required authentication, set insecure in the [auth] config to start anyway")
+	}
+	// requests are counted first, so that rejected ones are included
+	registry := prometheus.NewRegistry()
+	grpcMetrics := metrics.NewGRPC()
+	registry.MustRegister(grpcMetrics)
+	opts = append(opts, grpc.ChainUnaryInterceptor(grpcMetrics.UnaryInterceptor()), grpc.ChainStreamInterceptor(grpcMetrics.StreamInterceptor()))
+	switch cfg.Cluster.Role {
+	case roleAggregator:
+		// nodes are considered unreachable after missing three heartbeats
+		expiry := defaultExpiry
+		if cfg.Cluster.Expiry != "" {
+			if expiry, err = time.ParseDuration(cfg.Cluster.Expiry); err != nil {
+				log.Fatalf("failed to parse node expiry: %v", err)
+			}
+		}
+		nodes := aggregator.NewRegistry(3*heartbeat, expiry, dial)
+		scheduler, err := aggregator.NewScheduler(cfg.Cluster.Placement)
+		if err != nil {
+			log.Fatalf("failed to create scheduler: %v", err)
+		}
+		if cfg.Cluster.InsecureJoin {
+			log.Println("nodes may register without client certificate, anyone reaching the aggregator can add nodes")
+		}
+		server = aggregator.New(nodes, scheduler, aggregator.JoinPolicy{
+			Identities: cfg.Cluster.NodeIdentities,
+			Insecure:   cfg.Cluster.InsecureJoin,
+		}, cfg.Cluster.SyncUser)
+		opts = append(opts, grpc.ChainUnaryInterceptor(aggregator.UnaryInterceptor(cfg.Auth.Required)), grpc.ChainStreamInterceptor(aggregator.StreamInterceptor(cfg.Auth.Required)))
+		log.Println("initialized aggregator")
+	case roleNode, "":
+		// aggregators pass the identity of the caller on, so only nodes check roles
+		node = startNode(&cfg, heartbeat, dial)
+		opts = append(opts, grpc.ChainUnaryInterceptor(node.UnaryInterceptor()), grpc.ChainStreamInterceptor(node.StreamInterceptor()))
+		registry.MustRegister(node.Collectors()...)
+		server = node
+	default:
+		log.Fatalf("unknown cluster role %q", cfg.Cluster.Role)
 	}
-	log.Println("initialized vm driver")
 	// setup listener
 	listener, err := net.Listen("tcp", cfg.Grpc.Address)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 	log.Println("listening on", listener.Addr())
-	// setup options
-	var opts []grpc.ServerOption
+	// serve metrics
+	if cfg.Metrics.Address != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+			ErrorLog:      log.Default(),
+			ErrorHandling: promhttp.ContinueOnError,
+		}))
+		go func() {
+			if err := http.ListenAndServe(cfg.Metrics.Address, mux); err != nil {
+				log.Fatalf("failed to serve metrics: %v", err)
+			}
+		}()
+		log.Println("serving metrics on", cfg.Metrics.Address)
+	}
 	// start server
 	grpcServer := grpc.NewServer(opts...)
 	// handle shutdown signal
@@ -80,11 +262,93 @@ func run(cfgpath string) {
 		grpcServer.GracefulStop()
 	}()
 	// register and start serving
-	api.RegisterVirtMServer(grpcServer, driver)
+	api.RegisterSoxServer(grpcServer, server)
 	if err := grpcServer.Serve(listener); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
 	log.Println("grpc server shutted down")
+	if node != nil {
+		if err := node.Close(); err != nil {
+			log.Println("failed to stop node services:", err)
+		}
+	}
+}
+
+func startNode(cfg *config, heartbeat time.Duration, dial grpc.DialOption) *driver.Driver {
+	advertise := cfg.Cluster.Advertise
+	if advertise == "" {
+		advertise = cfg.Grpc.Address
+	}
+	var retention time.Duration
+	if cfg.Activities.Retention != "" {
+		var err error
+		if retention, err = time.ParseDuration(cfg.Activities.Retention); err != nil {
+			log.Fatalf("failed to parse activity retention: %v", err)
+		}
+	}
+	var retryDelay, maxRetryDelay time.Duration
+	if cfg.Webhooks.RetryDelay != "" {
+		var err error
+		if retryDelay, err = time.ParseDuration(cfg.Webhooks.RetryDelay); err != nil {
+			log.Fatalf("failed to parse webhook retry delay: %v", err)
+		}
+	}
+	if cfg.Webhooks.MaxRetryDelay != "" {
+		var err error
+		if maxRetryDelay, err = time.ParseDuration(cfg.Webhooks.MaxRetryDelay); err != nil {
+			log.Fatalf("failed to parse webhook max retry delay: %v", err)
+		}
+	}
+	// start vm manager
+	driver, err := driver.New(&driver.Config{
+		DB:                  cfg.Database.DSN,
+		StoragePool:         cfg.Libvirt.Storage,
+		NetworkTransportDev: cfg.Libvirt.Network,
+		LibvirtURI:          cfg.Libvirt.URI,
+		DNSAddress:          cfg.DNS.Address,
+		FloatingIPPool:      cfg.Floating.Pool,
+		FloatingIPDevice:    cfg.Floating.Device,
+		VPNDevice:           cfg.VPN.Device,
+		VPNRange:            cfg.VPN.Range,
+		VPNEndpoint:         cfg.VPN.Endpoint,
+		VPNPort:             cfg.VPN.Port,
+		VPNKeyPath:          cfg.VPN.Key,
+		NodeName:            cfg.Cluster.Name,
+		NodeAddress:         advertise,
+		NodeLabels:          cfg.Cluster.Labels,
+		MigrationURI:        cfg.Libvirt.MigrationURI,
+		AuthRequired:        cfg.Auth.Required,
+		DialOptions:         []grpc.DialOption{dial},
+		Overcommit: driver.Overcommit{
+			CPUs:   cfg.Overcommit.CPU,
+			Memory: cfg.Overcommit.Memory,
+			Disk:   cfg.Overcommit.Disk,
+		},
+		ActivityRetention: retention,
+		WebhookRetries: driver.WebhookRetries{
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			Delay:       retryDelay,
+			MaxDelay:    maxRetryDelay,
+		},
+		WebhookAllowPrivate: cfg.Webhooks.AllowPrivate,
+	})
+	if err != nil {
+		log.Fatalf("failed to start driver: %v", err)
+	}
+	log.Println("initialized vm driver")
+	// join aggregator
+	if cfg.Cluster.Aggregator != "" {
+		go aggregator.Join(context.Background(), cfg.Cluster.Aggregator, heartbeat, driver.NodeInfo, dial)
+		log.Println("joining aggregator at", cfg.Cluster.Aggregator)
+	}
+	return driver
+}
+
+func init() {
+	caCmd.PersistentFlags().StringVar(&caDir, "dir", "ca", "Directory holding the CA and issued certificates")
+	caIssueCmd.Flags().StringSliceVar(&caIssueHosts, "hosts", nil, "DNS names and IP addresses of server certificates")
+	caCmd.AddCommand(&caInitCmd, &caIssueCmd)
+	rootCmd.AddCommand(&caCmd)
 }
 
 func main() {
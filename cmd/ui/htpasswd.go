@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"fmt"
+	"hash"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// htpasswd holds user names and SHA-crypt password hashes as created by `openssl passwd -5` or `-6`.
+type htpasswd map[string]string
+
+func loadHtpasswd(path string) (htpasswd, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open htpasswd: %w", err)
+	}
+	defer file.Close()
+	users := htpasswd{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		fields := strings.SplitN(entry, ":", 2)
+		if len(fields) != 2 || !strings.HasPrefix(fields[1], "$5$") && !strings.HasPrefix(fields[1], "$6$") {
+			return nil, fmt.Errorf("line %d: expected user:hash with a SHA-crypt hash", line)
+		}
+		users[fields[0]] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read htpasswd: %w", err)
+	}
+	return users, nil
+}
+
+// verify checks the password of the user.
+func (users htpasswd) verify(user, password string) bool {
+	expected, ok := users[user]
+	if !ok {
+		return false
+	}
+	actual, err := shaCrypt(password, expected)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) == 1
+}
+
+const (
+	cryptAlphabet       = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	cryptDefaultRounds  = 5000
+	cryptMinRounds      = 1000
+	cryptMaxRounds      = 999999999
+	cryptMaxSaltLength  = 16
+	cryptRoundsPrefix   = "rounds="
+	cryptSHA256Prefix   = "$5$"
+	cryptSHA512Prefix   = "$6$"
+	cryptSHA256Length   = 32
+	cryptSaltRepetition = 16
+)
+
+// Order in which the digest bytes are encoded, three at a time.
+var (
+	cryptSHA256Order = []int{0, 10, 20, 21, 1, 11, 12, 22, 2, 3, 13, 23, 24, 4, 14, 15, 25, 5, 6, 16, 26, 27, 7, 17, 18, 28, 8, 9, 19, 29}
+	cryptSHA512Order = []int{0, 21, 42, 22, 43, 1, 44, 2, 23, 3, 24, 45, 25, 46, 4, 47, 5, 26, 6, 27, 48, 28, 49, 7, 50, 8, 29, 9, 30, 51, 31, 52, 10, 53, 11, 32, 12, 33, 54, 34, 55, 13, 56, 14, 35, 15, 36, 57, 37, 58, 16, 59, 17, 38, 18, 39, 60, 40, 61, 19, 62, 20, 41}
+)
+
+// shaCrypt hashes the password using the algorithm, rounds and salt of the setting,
+// following the SHA-crypt specification used by glibc.
+func shaCrypt(password, setting string) (string, error) {
+	var (
+		newHash func() hash.Hash
+		prefix  string
+	)
+	switch {
+	case strings.HasPrefix(setting, cryptSHA256Prefix):
+		newHash, prefix = sha256.New, cryptSHA256Prefix
+	case strings.HasPrefix(setting, cryptSHA512Prefix):
+		newHash, prefix = sha512.New, cryptSHA512Prefix
+	default:
+		return "", fmt.Errorf("unsupported hash")
+	}
+	rest := strings.TrimPrefix(setting, prefix)
+	rounds, explicitRounds := cryptDefaultRounds, false
+	if strings.HasPrefix(rest, cryptRoundsPrefix) {
+		end := strings.IndexByte(rest, '$')
+		if end < 0 {
+			return "", fmt.Errorf("missing salt")
+		}
+		n, err := strconv.Atoi(rest[len(cryptRoundsPrefix):end])
+		if err != nil {
+			return "", fmt.Errorf("parse rounds: %w", err)
+		}
+		rounds, explicitRounds, rest = n, true, rest[end+1:]
+		if rounds < cryptMinRounds {
+			rounds = cryptMinRounds
+		} else if rounds > cryptMaxRounds {
+			rounds = cryptMaxRounds
+		}
+	}
+	salt := rest
+	if end := strings.IndexByte(salt, '$'); end >= 0 {
+		salt = salt[:end]
+	}
+	if len(salt) > cryptMaxSaltLength {
+		salt = salt[:cryptMaxSaltLength]
+	}
+	p, s := []byte(password), []byte(salt)
+
+	// Digest B
+	h := newHash()
+	h.Write(p)
+	h.Write(s)
+	h.Write(p)
+	b := h.Sum(nil)
+
+	// Digest A
+	h = newHash()
+	h.Write(p)
+	h.Write(s)
+	h.Write(repeat(b, len(p)))
+	for n := len(p); n > 0; n >>= 1 {
+		if n&1 == 1 {
+			h.Write(b)
+		} else {
+			h.Write(p)
+		}
+	}
+	a := h.Sum(nil)
+
+	// Sequences P and S
+	h = newHash()
+	for i := 0; i < len(p); i++ {
+		h.Write(p)
+	}
+	pSeq := repeat(h.Sum(nil), len(p))
+	h = newHash()
+	for i := 0; i < cryptSaltRepetition+int(a[0]); i++ {
+		h.Write(s)
+	}
+	sSeq := repeat(h.Sum(nil), len(s))
+
+	// Rounds
+	c := a
+	for i := 0; i < rounds; i++ {
+		h = newHash()
+		if i%2 == 1 {
+			h.Write(pSeq)
+		} else {
+			h.Write(c)
+		}
+		if i%3 != 0 {
+			h.Write(sSeq)
+		}
+		if i%7 != 0 {
+			h.Write(pSeq)
+		}
+		if i%2 == 1 {
+			h.Write(c)
+		} else {
+			h.Write(pSeq)
+		}
+		c = h.Sum(nil)
+	}
+
+	var out strings.Builder
+	out.WriteString(prefix)
+	if explicitRounds {
+		fmt.Fprintf(&out, "%s%d$", cryptRoundsPrefix, rounds)
+	}
+	out.WriteString(salt)
+	out.WriteByte('$')
+	order := cryptSHA512Order
+	if len(c) == cryptSHA256Length {
+		order = cryptSHA256Order
+	}
+	for i := 0; i+2 < len(order); i += 3 {
+		encode24(&out, c[order[i]], c[order[i+1]], c[order[i+2]], 4)
+	}
+	if len(c) == cryptSHA256Length {
+		encode24(&out, 0, c[31], c[30], 3)
+	} else {
+		encode24(&out, 0, 0, c[63], 2)
+	}
+	return out.String(), nil
+}
+
+// repeat returns the digest repeated up to the given length.
+func repeat(digest []byte, length int) []byte {
+	seq := make([]byte, 0, length)
+	for len(seq) < length {
+		n := length - len(seq)
+		if n > len(digest) {
+			n = len(digest)
+		}
+		seq = append(seq, digest[:n]...)
+	}
+	return seq
+}
+
+func encode24(out *strings.Builder, b2, b1, b0 byte, n int) {
+	w := uint(b2)<<16 | uint(b1)<<8 | uint(b0)
+	for i := 0; i < n; i++ {
+		out.WriteByte(cryptAlphabet[w&0x3f])
+		w >>= 6
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShaCrypt(t *testing.T) {
+	tests := []struct {
+		password string
+		setting  string
+		want     string
+	}{
+		// openssl passwd -5 -salt saltstring 'Hello world!'
+		{"Hello world!", "$5$saltstring", "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5"},
+		// openssl passwd -6 -salt saltstring 'Hello world!'
+		{"Hello world!", "$6$saltstring", "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+		// Passwords longer than the digest
+		{"correct horse battery staple, a long password", "$5$Zx9fQb3P", "$5$Zx9fQb3P$6KeByFoEJQgjcqVxP2is2mOUGY8smyrR4yGprFXyK69"},
+		{"correct horse battery staple, a long password", "$6$Zx9fQb3P", "$6$Zx9fQb3P$umbAgW4z1Jb6LvYg0KOp.cDnvJrBVuJRySH7meDWQnpHUGxt5LWqB.bnQRWYU.WBp5h3swukDKd1WBt20VSom."},
+		// glibc test vectors with explicit rounds, the salt is cut to 16 characters
+		{"Hello world!", "$5$rounds=10000$saltstringsaltstring", "$5$rounds=10000$saltstringsaltst$3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA"},
+		{"Hello world!", "$6$rounds=10000$saltstringsaltstring", "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v."},
+	}
+	for _, test := range tests {
+		got, err := shaCrypt(test.password, test.setting)
+		if err != nil {
+			t.Errorf("shaCrypt(%q, %q) = %v", test.password, test.setting, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("shaCrypt(%q, %q) = %s, want %s", test.password, test.setting, got, test.want)
+		}
+		// Hashes are their own setting
+		if again, _ := shaCrypt(test.password, test.want); again != test.want {
+			t.Errorf("shaCrypt(%q, %q) = %s, want the same hash", test.password, test.want, again)
+		}
+	}
+}
+
+func TestShaCryptInvalidSetting(t *testing.T) {
+	for _, setting := range []string{"", "$1$salt$hash", "$2y$10$abcdefghijklmnopqrstuv", "$5$rounds=10000", "$6$rounds=many$salt$hash"} {
+		if got, err := shaCrypt("password", setting); err == nil {
+			t.Errorf("shaCrypt(%q) = %s, want error", setting, got)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	users := htpasswd{
+		"alice": "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5",
+		"bob":   "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1",
+		"carol": "$6$rounds=many$salt$hash",
+	}
+	tests := []struct {
+		user     string
+		password string
+		valid    bool
+	}{
+		{"alice", "Hello world!", true},
+		{"bob", "Hello world!", true},
+		{"alice", "hello world!", false},
+		{"alice", "Hello world", false},
+		{"alice", "", false},
+		{"dave", "Hello world!", false},
+		{"carol", "Hello world!", false},
+	}
+	for _, test := range tests {
+		if valid := users.verify(test.user, test.password); valid != test.valid {
+			t.Errorf("verify(%q, %q) = %v, want %v", test.user, test.password, valid, test.valid)
+		}
+	}
+}
+
+func TestLoadHtpasswd(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		users   []string
+		error   string
+	}{
+		{
+			name:    "valid",
+			content: "# sox users\n\nalice:$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5\n  bob:$6$saltstring$hash  \n",
+			users:   []string{"alice", "bob"},
+		},
+		{name: "missing hash", content: "alice\n", error: "line 1"},
+		{name: "md5 hash", content: "alice:$5$salt$hash\nbob:$apr1$salt$hash\n", error: "line 2"},
+		{name: "bcrypt hash", content: "# comment\nalice:$2y$10$abcdefghijklmnopqrstuv\n", error: "line 2"},
+		{name: "plain password", content: "alice:secret\n", error: "line 1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "htpasswd")
+			if err := os.WriteFile(path, []byte(test.content), 0600); err != nil {
+				t.Fatalf("write htpasswd: %v", err)
+			}
+			users, err := loadHtpasswd(path)
+			if test.error != "" {
+				if err == nil || !strings.Contains(err.Error(), test.error) {
+					t.Fatalf("loadHtpasswd() = %v, want error in %s", err, test.error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadHtpasswd() = %v", err)
+			}
+			if len(users) != len(test.users) {
+				t.Errorf("loadHtpasswd() = %d users, want %d", len(users), len(test.users))
+			}
+			for _, user := range test.users {
+				if _, ok := users[user]; !ok {
+					t.Errorf("user %s missing", user)
+				}
+			}
+		})
+	}
+	if _, err := loadHtpasswd(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("loadHtpasswd() of missing file succeeded")
+	}
+}
@@ -1,61 +1,104 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
 	"github.com/lnsp/sox/meta"
 	"github.com/lnsp/sox/ui"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func main() {
 	serveAddress := flag.String("serve", "localhost:9877", "Listen on address")
 	virtmEndpoint := flag.String("virtm-endpoint", "localhost:9876", "VirtM server endpoint")
-	virtmInsecure := flag.Bool("virtm-insecure", true, "Use insecure gRPC client")
-	dev := flag.Bool("dev", false, "Disables static file hosting and relaxes CORS filtering")
+	virtmInsecure := flag.Bool("virtm-insecure", false, "Connect to VirtM without TLS")
+	virtmCA := flag.String("virtm-ca", "", "CA to verify VirtM with, defaults to the system roots")
+	virtmCert := flag.String("virtm-cert", "", "Client certificate to authenticate with")
+	virtmKey := flag.String("virtm-key", "", "Key of the client certificate")
+	virtmToken := flag.String("virtm-token", os.Getenv("VIRTM_TOKEN"), "API token to authenticate with, defaults to $VIRTM_TOKEN")
+	virtmUser := flag.String("virtm-user", "", "User to make requests as, admins may act on behalf of other users")
+	virtmProject := flag.String("virtm-project", "", "Project to create and list resources in")
+	htpasswdPath := flag.String("htpasswd", "", "Log in users of an htpasswd file with SHA-crypt hashes instead of VirtM users, requires admin credentials")
+	sessionTTL := flag.Duration("session-ttl", 12*time.Hour, "Lifetime of login sessions")
+	insecureCookies := flag.Bool("insecure-cookies", false, "Send session cookies over plain HTTP")
+	dev := flag.Bool("dev", false, "Disables static file hosting and allows requests from the dev origin")
+	devOrigin := flag.String("dev-origin", "http://localhost:3000", "Origin of the UI dev server")
 	flag.Parse()
 
-	// Connect to virtm
-	virtm, err := connect(*virtmEndpoint, *virtmInsecure)
+	// Connect to virtm, servers which do not require authentication trust the named user
+	identity := auth.Identity{User: *virtmUser, Project: *virtmProject, Token: *virtmToken}
+	var tlsConfig *tls.Config
+	if !*virtmInsecure {
+		var err error
+		if tlsConfig, err = auth.ClientConfig(*virtmCert, *virtmKey, *virtmCA); err != nil {
+			log.Fatalln("load TLS config:", err)
+		}
+	}
+	virtm, err := connect(*virtmEndpoint, tlsConfig, identity)
 	if err != nil {
 		log.Fatalln("connect to virtm:", err)
 	}
 
+	// Users log in with VirtM API tokens unless an htpasswd file is given
+	login, passwordHint := soxLogin(virtm, *virtmProject), "API token"
+	if *htpasswdPath != "" {
+		users, err := loadHtpasswd(*htpasswdPath)
+		if err != nil {
+			log.Fatalln("load htpasswd:", err)
+		}
+		login, passwordHint = htpasswdLogin(users, identity), "Password"
+	}
+	landing := "/"
+	if *dev {
+		landing = *devOrigin + "/"
+	}
+	sessions := NewSessions(login, passwordHint, *sessionTTL, !*insecureCookies, landing)
+
 	router := mux.NewRouter()
+	apiRouter := router.PathPrefix("/api/").Subrouter()
+	apiRouter.Use(sessions.Require)
+	sessions.Init(router, apiRouter)
 	// Setup API endpoints
 	apiHandler := &APIHandler{
 		Client: virtm,
 	}
-	if err := apiHandler.Init(router.PathPrefix("/api/").Subrouter()); err != nil {
+	if err := apiHandler.Init(apiRouter); err != nil {
 		log.Fatalln("init api:", err)
 	}
 	// Setup static endpoints
 	if !*dev {
 		fs := http.FileServer(http.FS(ui.FS))
-		router.PathPrefix("/").Handler(fs)
+		router.PathPrefix("/").Handler(sessions.Require(fs))
 	}
-	// Setup server and listen
+	// Setup server and listen, without write timeout so event streams stay open
 	server := &http.Server{
-		ReadTimeout:  time.Minute,
-		WriteTimeout: time.Minute,
-		Handler:      router,
-		Addr:         *serveAddress,
+		ReadTimeout: time.Minute,
+		Handler:     limitRequests(router, requestTimeout, "/api/events"),
+		Addr:        *serveAddress,
 	}
-	// Setup CORS
+	// Setup CORS, the dev server sends the session cookie along
 	if *dev {
 		methods := handlers.AllowedMethods([]string{"DELETE", "GET", "HEAD", "POST"})
-		headers := handlers.AllowedHeaders([]string{"Content-Type"})
-		origins := handlers.AllowedOrigins([]string{"*"})
-		server.Handler = handlers.CORS(methods, headers, origins)(router)
+		headers := handlers.AllowedHeaders([]string{"Content-Type", "Last-Event-ID", csrfHeader})
+		origins := handlers.AllowedOrigins([]string{*devOrigin})
+		server.Handler = handlers.CORS(methods, headers, origins, handlers.AllowCredentials())(server.Handler)
 	}
 	// Listen and serve
 	if err := server.ListenAndServe(); err != nil {
@@ -63,8 +106,25 @@ func main() {
 	}
 }
 
+// requestTimeout bounds the handling of all requests but streams.
+const requestTimeout = time.Minute
+
+// limitRequests cancels requests not done within the timeout, except for those of the streaming paths.
+func limitRequests(next http.Handler, timeout time.Duration, streams ...string) http.Handler {
+	limited := http.TimeoutHandler(next, timeout, "request timed out")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, path := range streams {
+			if r.URL.Path == path {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		limited.ServeHTTP(w, r)
+	})
+}
+
 type APIHandler struct {
-	Client api.VirtMClient
+	Client api.SoxClient
 }
 
 func (handler *APIHandler) Init(mux *mux.Router) error {
@@ -74,11 +134,20 @@ func (handler *APIHandler) Init(mux *mux.Router) error {
 	mux.Handle("/machines", handler.createMachine()).Methods(http.MethodPost)
 	mux.Handle("/machines/{id}", handler.showMachineDetails()).Methods(http.MethodGet)
 	mux.Handle("/machines/{id}", handler.deleteMachine()).Methods(http.MethodDelete)
+	mux.Handle("/machines/{id}/stats", handler.showMachineStats()).Methods(http.MethodGet)
 	mux.Handle("/machines/{id}/trigger", handler.triggerMachine()).Methods(http.MethodPost).Queries("event", "{event}")
+	mux.Handle("/machines/{id}/interfaces", handler.attachNetworkInterface()).Methods(http.MethodPost)
+	mux.Handle("/machines/{id}/interfaces/{networkId}", handler.detachNetworkInterface()).Methods(http.MethodDelete)
+	mux.Handle("/machines/{id}/port-forwards", handler.listPortForwards()).Methods(http.MethodGet)
+	mux.Handle("/machines/{id}/port-forwards", handler.createPortForward()).Methods(http.MethodPost)
+	mux.Handle("/port-forwards/{id}", handler.deletePortForward()).Methods(http.MethodDelete)
 	mux.Handle("/ssh-keys", handler.listSSHKeys()).Methods(http.MethodGet)
 	mux.Handle("/images", handler.listImages()).Methods(http.MethodGet)
 	mux.Handle("/networks", handler.listNetworks()).Methods(http.MethodGet)
 	mux.Handle("/activities", handler.listActivities()).Methods(http.MethodGet)
+	mux.Handle("/quota", handler.showQuotaUsage()).Methods(http.MethodGet)
+	mux.Handle("/hosts", handler.listHosts()).Methods(http.MethodGet)
+	mux.Handle("/events", handler.streamEvents()).Methods(http.MethodGet)
 	return nil
 }
 
@@ -123,6 +192,8 @@ func (handler *APIHandler) showMachineDetails() http.Handler {
 			IPv4      string `json:"ipv4"`
 			IPv6      string `json:"ipv6"`
 			NetworkID string `json:"networkId"`
+			// PendingReboot is set until the guest configured the interface
+			PendingReboot bool `json:"pendingReboot"`
 		}
 		body := struct {
 			ID     string `json:"id"`
@@ -142,15 +213,68 @@ func (handler *APIHandler) showMachineDetails() http.Handler {
 		}
 		for i := range resp.Machine.Networks {
 			body.NetworkInterfaces[i] = jsonNetworkInterface{
-				IPv4:      resp.Machine.Networks[i].IpV4,
-				IPv6:      resp.Machine.Networks[i].IpV6,
-				NetworkID: resp.Machine.Networks[i].NetworkId,
+				IPv4:          resp.Machine.Networks[i].IpV4,
+				IPv6:          resp.Machine.Networks[i].IpV6,
+				NetworkID:     resp.Machine.Networks[i].NetworkId,
+				PendingReboot: resp.Machine.Networks[i].PendingReboot,
 			}
 		}
 		json.NewEncoder(w).Encode(&body)
 	})
 }
 
+func (handler *APIHandler) showMachineStats() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		resp, err := handler.Client.GetMachineStats(r.Context(), &api.GetMachineStatsRequest{
+			Id: vars["id"],
+		})
+		if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			log.Println("machine stats:", err)
+			return
+		}
+		type jsonUsage struct {
+			Timestamp           time.Time `json:"timestamp"`
+			CPUPercent          float64   `json:"cpuPercent"`
+			MemoryUsed          int64     `json:"memoryUsed"`
+			MemoryTotal         int64     `json:"memoryTotal"`
+			DiskReadRate        float64   `json:"diskReadRate"`
+			DiskWriteRate       float64   `json:"diskWriteRate"`
+			NetworkReceiveRate  float64   `json:"networkReceiveRate"`
+			NetworkTransmitRate float64   `json:"networkTransmitRate"`
+		}
+		toJSON := func(usage *api.MachineUsage) *jsonUsage {
+			if usage == nil {
+				return nil
+			}
+			return &jsonUsage{
+				Timestamp:           usage.Time.AsTime(),
+				CPUPercent:          usage.CpuPercent,
+				MemoryUsed:          usage.MemoryUsed,
+				MemoryTotal:         usage.MemoryTotal,
+				DiskReadRate:        usage.DiskReadRate,
+				DiskWriteRate:       usage.DiskWriteRate,
+				NetworkReceiveRate:  usage.NetworkReceiveRate,
+				NetworkTransmitRate: usage.NetworkTransmitRate,
+			}
+		}
+		body := struct {
+			Current  *jsonUsage   `json:"current"`
+			History  []*jsonUsage `json:"history"`
+			Interval int64        `json:"interval"`
+		}{
+			Current:  toJSON(resp.Current),
+			History:  make([]*jsonUsage, len(resp.History)),
+			Interval: resp.Interval,
+		}
+		for i := range resp.History {
+			body.History[i] = toJSON(resp.History[i])
+		}
+		json.NewEncoder(w).Encode(&body)
+	})
+}
+
 func (handler *APIHandler) listMachines() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp, err := handler.Client.ListMachines(r.Context(), &api.ListMachinesRequest{})
@@ -319,26 +443,179 @@ func (handler *APIHandler) triggerMachine() http.Handler {
 	})
 }
 
-func (handler *APIHandler) listActivities() http.Handler {
+func (handler *APIHandler) attachNetworkInterface() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp, err := handler.Client.ListActivities(r.Context(), &api.ListActivitiesRequest{})
+		vars := mux.Vars(r)
+		var body struct {
+			NetworkID        string   `json:"networkId"`
+			SecurityGroupIDs []string `json:"securityGroupIds"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "could not decode body", http.StatusBadRequest)
+			log.Println("decode request body:", err)
+			return
+		}
+		resp, err := handler.Client.AttachNetworkInterface(r.Context(), &api.AttachNetworkInterfaceRequest{
+			MachineId:        vars["id"],
+			NetworkId:        body.NetworkID,
+			SecurityGroupIds: body.SecurityGroupIDs,
+		})
+		if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			log.Println("attach network interface:", err)
+			return
+		}
+		json.NewEncoder(w).Encode(struct {
+			IPv4          string `json:"ipv4"`
+			IPv6          string `json:"ipv6"`
+			NetworkID     string `json:"networkId"`
+			PendingReboot bool   `json:"pendingReboot"`
+		}{
+			IPv4:          resp.NetworkInterface.IpV4,
+			IPv6:          resp.NetworkInterface.IpV6,
+			NetworkID:     resp.NetworkInterface.NetworkId,
+			PendingReboot: resp.NetworkInterface.PendingReboot,
+		})
+	})
+}
+
+func (handler *APIHandler) detachNetworkInterface() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		_, err := handler.Client.DetachNetworkInterface(r.Context(), &api.DetachNetworkInterfaceRequest{
+			MachineId: vars["id"],
+			NetworkId: vars["networkId"],
+		})
+		if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			log.Println("detach network interface:", err)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+func (handler *APIHandler) listPortForwards() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		resp, err := handler.Client.ListPortForwards(r.Context(), &api.ListPortForwardsRequest{
+			MachineId: vars["id"],
+		})
 		if err != nil {
 			http.Error(w, "internal server error", http.StatusInternalServerError)
-			log.Println("list activities:", err)
+			log.Println("list port forwards:", err)
+			return
+		}
+		type jsonPortForward struct {
+			ID          string `json:"id"`
+			NetworkID   string `json:"networkId"`
+			HostIP      string `json:"hostIp"`
+			HostPort    uint32 `json:"hostPort"`
+			MachinePort uint32 `json:"machinePort"`
+			Protocol    string `json:"protocol"`
+		}
+		forwards := make([]jsonPortForward, len(resp.PortForwards))
+		for i := range resp.PortForwards {
+			forwards[i] = jsonPortForward{
+				ID:          resp.PortForwards[i].Id,
+				NetworkID:   resp.PortForwards[i].NetworkId,
+				HostIP:      resp.PortForwards[i].HostIp,
+				HostPort:    resp.PortForwards[i].HostPort,
+				MachinePort: resp.PortForwards[i].MachinePort,
+				Protocol:    resp.PortForwards[i].Protocol.String(),
+			}
+		}
+		json.NewEncoder(w).Encode(forwards)
+	})
+}
+
+func (handler *APIHandler) createPortForward() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		var body struct {
+			NetworkID   string `json:"networkId"`
+			HostIP      string `json:"hostIp"`
+			HostPort    uint32 `json:"hostPort"`
+			MachinePort uint32 `json:"machinePort"`
+			Protocol    string `json:"protocol"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "could not decode body", http.StatusBadRequest)
+			log.Println("decode request body:", err)
 			return
 		}
+		resp, err := handler.Client.CreatePortForward(r.Context(), &api.CreatePortForwardRequest{
+			MachineId:   vars["id"],
+			NetworkId:   body.NetworkID,
+			HostIp:      body.HostIP,
+			HostPort:    body.HostPort,
+			MachinePort: body.MachinePort,
+			Protocol:    api.PortForward_Protocol(api.PortForward_Protocol_value[body.Protocol]),
+		})
+		if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			log.Println("create port forward:", err)
+			return
+		}
+		json.NewEncoder(w).Encode(struct {
+			Id string `json:"id"`
+		}{
+			resp.Id,
+		})
+	})
+}
+
+func (handler *APIHandler) deletePortForward() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		_, err := handler.Client.DeletePortForward(r.Context(), &api.DeletePortForwardRequest{
+			Id: vars["id"],
+		})
+		if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			log.Println("delete port forward:", err)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+func (handler *APIHandler) listActivities() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		type jsonActivity struct {
 			Timestamp time.Time `json:"timestamp"`
 			Subject   string    `json:"subject"`
 			Type      string    `json:"type"`
+			Actor     string    `json:"actor"`
+			Error     string    `json:"error,omitempty"`
 		}
-		activities := make([]jsonActivity, len(resp.Activities))
-		for i := range resp.Activities {
-			activities[i] = jsonActivity{
-				Timestamp: resp.Activities[i].Timestamp.AsTime(),
-				Type:      resp.Activities[i].Type.String(),
-				Subject:   resp.Activities[i].Subject,
+		// The dashboard summarizes the activities of the last year
+		activities := []jsonActivity{}
+		request := &api.ListActivitiesRequest{
+			Since:       timestamppb.New(time.Now().AddDate(-1, 0, 0)),
+			OldestFirst: true,
+			PageSize:    1000,
+		}
+		for {
+			resp, err := handler.Client.ListActivities(r.Context(), request)
+			if err != nil {
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+				log.Println("list activities:", err)
+				return
+			}
+			for _, activity := range resp.Activities {
+				activities = append(activities, jsonActivity{
+					Timestamp: activity.Timestamp.AsTime(),
+					Type:      activity.Type.String(),
+					Subject:   activity.Subject,
+					Actor:     activity.Actor,
+					Error:     activity.Error,
+				})
+			}
+			if resp.NextPageToken == "" {
+				break
 			}
+			request.PageToken = resp.NextPageToken
 		}
 		json.NewEncoder(w).Encode(struct {
 			Activities []jsonActivity `json:"activities"`
@@ -348,14 +625,199 @@ func (handler *APIHandler) listActivities() http.Handler {
 	})
 }
 
-func connect(endpoint string, insecure bool) (api.VirtMClient, error) {
+func (handler *APIHandler) showQuotaUsage() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := handler.Client.GetQuotaUsage(r.Context(), &api.GetQuotaUsageRequest{})
+		if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			log.Println("quota usage:", err)
+			return
+		}
+		type jsonQuota struct {
+			CPUs        int64 `json:"cpus"`
+			Memory      int64 `json:"memory"`
+			Disk        int64 `json:"disk"`
+			Machines    int64 `json:"machines"`
+			FloatingIPs int64 `json:"floatingIps"`
+			Networks    int64 `json:"networks"`
+		}
+		toJSON := func(quota *api.Quota) jsonQuota {
+			return jsonQuota{
+				CPUs:        quota.Cpus,
+				Memory:      quota.Memory,
+				Disk:        quota.Disk,
+				Machines:    quota.Machines,
+				FloatingIPs: quota.FloatingIps,
+				Networks:    quota.Networks,
+			}
+		}
+		json.NewEncoder(w).Encode(struct {
+			ProjectID string    `json:"projectId"`
+			Quota     jsonQuota `json:"quota"`
+			Usage     jsonQuota `json:"usage"`
+		}{
+			ProjectID: resp.ProjectId,
+			Quota:     toJSON(resp.Quota),
+			Usage:     toJSON(resp.Usage),
+		})
+	})
+}
+
+func (handler *APIHandler) listHosts() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := handler.Client.GetHostInfo(r.Context(), &api.GetHostInfoRequest{})
+		if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			log.Println("host info:", err)
+			return
+		}
+		type jsonResources struct {
+			CPUs   int64 `json:"cpus"`
+			Memory int64 `json:"memory"`
+			Disk   int64 `json:"disk"`
+		}
+		type jsonHost struct {
+			Node      string        `json:"node"`
+			CPUModel  string        `json:"cpuModel"`
+			Capacity  jsonResources `json:"capacity"`
+			Free      jsonResources `json:"free"`
+			Allocated jsonResources `json:"allocated"`
+			Limit     jsonResources `json:"limit"`
+		}
+		toJSON := func(resources *api.Node_Resources) jsonResources {
+			return jsonResources{
+				CPUs:   resources.GetCpus(),
+				Memory: resources.GetMemory(),
+				Disk:   resources.GetDisk(),
+			}
+		}
+		hosts := make([]jsonHost, len(resp.Hosts))
+		for i, host := range resp.Hosts {
+			hosts[i] = jsonHost{
+				Node:      host.Node,
+				CPUModel:  host.CpuModel,
+				Capacity:  toJSON(host.Capacity),
+				Free:      toJSON(host.Free),
+				Allocated: toJSON(host.Allocated),
+				Limit:     toJSON(host.Limit),
+			}
+		}
+		json.NewEncoder(w).Encode(hosts)
+	})
+}
+
+// eventStreamDuration is the time an event stream is kept open before the browser reconnects
+// with the ID of the last event, which checks its session again.
+const eventStreamDuration = 10 * time.Minute
+
+// streamEvents forwards the VirtM events as server-sent events. Streams resumed after expired
+// events receive an expired event, which tells the browser to reload its state.
+func (handler *APIHandler) streamEvents() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), eventStreamDuration)
+		defer cancel()
+		stream, err := handler.Client.WatchEvents(ctx, &api.WatchEventsRequest{
+			ResumeToken: r.Header.Get("Last-Event-ID"),
+		})
+		if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			log.Println("watch events:", err)
+			return
+		}
+		type jsonActivity struct {
+			Timestamp time.Time `json:"timestamp"`
+			Subject   string    `json:"subject"`
+			Type      string    `json:"type"`
+			Actor     string    `json:"actor"`
+			Error     string    `json:"error,omitempty"`
+		}
+		type jsonEvent struct {
+			Type      string        `json:"type"`
+			Timestamp time.Time     `json:"timestamp"`
+			Node      string        `json:"node"`
+			Kind      string        `json:"kind"`
+			Subject   string        `json:"subject"`
+			Status    string        `json:"status,omitempty"`
+			Activity  *jsonActivity `json:"activity,omitempty"`
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		fmt.Fprint(w, "retry: 1000\n\n")
+		flusher.Flush()
+		for {
+			resp, err := stream.Recv()
+			if ctx.Err() != nil {
+				return
+			} else if status.Code(err) == codes.OutOfRange {
+				// An empty ID resets the ID the browser resumes with
+				fmt.Fprint(w, "event: expired\nid\ndata: {}\n\n")
+				flusher.Flush()
+				return
+			} else if err != nil {
+				log.Println("watch events:", err)
+				return
+			}
+			event := jsonEvent{
+				Type:      resp.Event.Type.String(),
+				Timestamp: resp.Event.Timestamp.AsTime(),
+				Node:      resp.Event.Node,
+				Kind:      resp.Event.Kind,
+				Subject:   resp.Event.Subject,
+			}
+			if resp.Event.Type == api.Event_MACHINE_STATE_CHANGED {
+				event.Status = resp.Event.MachineStatus.String()
+			}
+			if activity := resp.Event.Activity; activity != nil {
+				event.Activity = &jsonActivity{
+					Timestamp: activity.Timestamp.AsTime(),
+					Type:      activity.Type.String(),
+					Subject:   activity.Subject,
+					Actor:     activity.Actor,
+					Error:     activity.Error,
+				}
+			}
+			data, err := json.Marshal(&event)
+			if err != nil {
+				log.Println("encode event:", err)
+				return
+			}
+			fmt.Fprintf(w, "id: %s\ndata: %s\n\n", resp.ResumeToken, data)
+			flusher.Flush()
+		}
+	})
+}
+
+// connect dials VirtM using TLS unless tlsConfig is nil. Requests act as the identity
+// attached to their context, or as the given identity if there is none.
+func connect(endpoint string, tlsConfig *tls.Config, identity auth.Identity) (api.SoxClient, error) {
 	var grpcOpts []grpc.DialOption
-	if insecure {
+	if tlsConfig == nil {
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
+	} else {
+		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	}
+	grpcOpts = append(grpcOpts, grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		caller, ok := ctx.Value(identityKey{}).(auth.Identity)
+		if !ok {
+			caller = identity
+		}
+		return invoker(auth.NewOutgoingContext(ctx, caller), method, req, reply, cc, opts...)
+	}))
+	grpcOpts = append(grpcOpts, grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		caller, ok := ctx.Value(identityKey{}).(auth.Identity)
+		if !ok {
+			caller = identity
+		}
+		return streamer(auth.NewOutgoingContext(ctx, caller), desc, cc, method, opts...)
+	}))
 	grpcClient, err := grpc.Dial(endpoint, grpcOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("dial endpoint: %w", err)
 	}
-	return api.NewVirtMClient(grpcClient), nil
+	return api.NewSoxClient(grpcClient), nil
 }
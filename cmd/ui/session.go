@@ -0,0 +1,403 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
+)
+
+const (
+	sessionCookie = "sox_session"
+	csrfHeader    = "X-CSRF-Token"
+)
+
+const (
+	// Logins of a user or from an address are delayed after loginFreeAttempts failures,
+	// starting at loginBackoff and doubling with each further failure up to loginMaxBackoff.
+	loginFreeAttempts = 3
+	loginBackoff      = time.Second
+	loginMaxBackoff   = 5 * time.Minute
+	// loginFailureWindow is how long failures are remembered after the last one.
+	loginFailureWindow = 15 * time.Minute
+)
+
+// session is a logged in user of the UI.
+type session struct {
+	user string
+	// identity is passed to VirtM with the requests of the user.
+	identity auth.Identity
+	csrf     string
+	expires  time.Time
+}
+
+// loginFailures counts the failed logins of a user or client address.
+type loginFailures struct {
+	count        int
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// loginFunc checks the credentials entered on the login page and returns the identity to act with.
+type loginFunc func(ctx context.Context, user, password string) (auth.Identity, error)
+
+// soxLogin logs in users of VirtM using one of their API tokens as password.
+func soxLogin(client api.SoxClient, project string) loginFunc {
+	return func(ctx context.Context, user, password string) (auth.Identity, error) {
+		identity := auth.Identity{Project: project, Token: password}
+		resp, err := client.GetCurrentUser(withIdentity(ctx, identity), &api.GetCurrentUserRequest{})
+		if err != nil {
+			return identity, fmt.Errorf("get current user: %w", err)
+		}
+		if resp.User.Name != user && resp.User.Id != user {
+			return identity, fmt.Errorf("token belongs to user %s", resp.User.Name)
+		}
+		return identity, nil
+	}
+}
+
+// htpasswdLogin logs in users of an htpasswd file. Requests are made on their behalf
+// using the credentials of the UI, which therefore need to belong to an admin.
+func htpasswdLogin(users htpasswd, identity auth.Identity) loginFunc {
+	return func(ctx context.Context, user, password string) (auth.Identity, error) {
+		if !users.verify(user, password) {
+			return identity, fmt.Errorf("invalid password")
+		}
+		identity.User = user
+		return identity, nil
+	}
+}
+
+// Sessions keeps the logged in users and guards the API and the static UI.
+type Sessions struct {
+	login loginFunc
+	// passwordHint tells users what to enter as password.
+	passwordHint string
+	ttl          time.Duration
+	// secure restricts cookies to HTTPS, browsers accept them on localhost as well.
+	secure bool
+	// landing is where users are sent after logging in.
+	landing string
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	// failures are kept by user and client address, see loginKeys.
+	failures map[string]*loginFailures
+}
+
+func NewSessions(login loginFunc, passwordHint string, ttl time.Duration, secure bool, landing string) *Sessions {
+	return &Sessions{
+		login:        login,
+		passwordHint: passwordHint,
+		ttl:          ttl,
+		secure:       secure,
+		landing:      landing,
+		sessions:     make(map[string]*session),
+		failures:     make(map[string]*loginFailures),
+	}
+}
+
+// Init registers the login page on the router and the session endpoints on the API router.
+func (sessions *Sessions) Init(router, apiRouter *mux.Router) {
+	router.Handle("/login", sessions.loginPage()).Methods(http.MethodGet)
+	router.Handle("/login", sessions.handleLogin()).Methods(http.MethodPost)
+	apiRouter.Handle("/session", sessions.showSession()).Methods(http.MethodGet)
+	apiRouter.Handle("/logout", sessions.handleLogout()).Methods(http.MethodPost)
+}
+
+// Require rejects requests without session and changes without CSRF token. Requests outside
+// of the API are redirected to the login page. The identity of the user is attached to the request.
+func (sessions *Sessions) Require(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess := sessions.lookup(r)
+		if sess == nil {
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+			} else {
+				http.Redirect(w, r, "/login", http.StatusSeeOther)
+			}
+			return
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get(csrfHeader)), []byte(sess.csrf)) != 1 {
+				http.Error(w, "invalid csrf token", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(withIdentity(r.Context(), sess.identity)))
+	})
+}
+
+func (sessions *Sessions) lookup(r *http.Request) *session {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return nil
+	}
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	sess, ok := sessions.sessions[cookie.Value]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(sess.expires) {
+		delete(sessions.sessions, cookie.Value)
+		return nil
+	}
+	return sess
+}
+
+// create stores a new session and returns its ID.
+func (sessions *Sessions) create(user string, identity auth.Identity) (string, error) {
+	id, err := randomString()
+	if err != nil {
+		return "", err
+	}
+	csrf, err := randomString()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	// Drop expired sessions of users who never logged out
+	for id, sess := range sessions.sessions {
+		if now.After(sess.expires) {
+			delete(sessions.sessions, id)
+		}
+	}
+	sessions.sessions[id] = &session{
+		user:     user,
+		identity: identity,
+		csrf:     csrf,
+		expires:  now.Add(sessions.ttl),
+	}
+	return id, nil
+}
+
+func (sessions *Sessions) setCookie(w http.ResponseWriter, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   sessions.secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+var loginTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Login - VirtM UI</title>
+<style>
+body { background: #112825; color: #f3f4f6; font-family: Inter, sans-serif; display: flex; align-items: center; justify-content: center; height: 100vh; margin: 0; }
+form { display: flex; flex-direction: column; gap: 0.75rem; width: 20rem; }
+input { padding: 0.5rem; border-radius: 0.25rem; border: 1px solid #4b5563; background: #1f2937; color: inherit; }
+button { padding: 0.5rem; border-radius: 0.25rem; border: 0; background: #2dd4bf; color: #112825; font-weight: 600; cursor: pointer; }
+.error { color: #f87171; }
+</style>
+</head>
+<body>
+<form method="post" action="/login">
+<h1>sox</h1>
+{{if .Error}}<div class="error">{{.Error}}</div>{{end}}
+<input name="user" placeholder="User" autocomplete="username" value="{{.User}}" required autofocus>
+<input name="password" type="password" placeholder="{{.PasswordHint}}" autocomplete="current-password" required>
+<button type="submit">Log in</button>
+</form>
+</body>
+</html>
+`))
+
+type loginPageData struct {
+	User         string
+	Error        string
+	PasswordHint string
+}
+
+func (sessions *Sessions) renderLogin(w http.ResponseWriter, status int, data loginPageData) {
+	data.PasswordHint = sessions.passwordHint
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// The login form must not be embedded by other sites
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.WriteHeader(status)
+	loginTemplate.Execute(w, data)
+}
+
+func (sessions *Sessions) loginPage() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessions.renderLogin(w, http.StatusOK, loginPageData{})
+	})
+}
+
+func (sessions *Sessions) handleLogin() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Logins from other sites could make the browser act as another user
+		if !sameOrigin(r) {
+			http.Error(w, "cross-origin login rejected", http.StatusForbidden)
+			return
+		}
+		user, password := r.PostFormValue("user"), r.PostFormValue("password")
+		userKey, addrKey := loginKeys(r, user)
+		if wait := sessions.throttled(time.Now(), userKey, addrKey); wait > 0 {
+			seconds := int((wait + time.Second - 1) / time.Second)
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			sessions.renderLogin(w, http.StatusTooManyRequests, loginPageData{User: user, Error: fmt.Sprintf("Too many failed logins, try again in %d seconds", seconds)})
+			return
+		}
+		identity, err := sessions.login(r.Context(), user, password)
+		if err != nil {
+			log.Println("login of", user, "failed:", err)
+			sessions.recordFailure(time.Now(), userKey, addrKey)
+			sessions.renderLogin(w, http.StatusUnauthorized, loginPageData{User: user, Error: "Invalid user or password"})
+			return
+		}
+		id, err := sessions.create(user, identity)
+		if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			log.Println("create session:", err)
+			return
+		}
+		// The address keeps its failures, so that logging in to one account does not
+		// lift the backoff for guessing the passwords of others
+		sessions.forgetFailures(userKey)
+		log.Println("user", user, "logged in")
+		sessions.setCookie(w, id, int(sessions.ttl.Seconds()))
+		http.Redirect(w, r, sessions.landing, http.StatusSeeOther)
+	})
+}
+
+// loginKeys returns the keys failed logins of the user and the client address are counted by.
+func loginKeys(r *http.Request, user string) (userKey, addrKey string) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "user:" + user, "addr:" + host
+}
+
+// throttled returns how long logins with any of the keys are still blocked.
+func (sessions *Sessions) throttled(now time.Time, keys ...string) time.Duration {
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	var wait time.Duration
+	for _, key := range keys {
+		if f, ok := sessions.failures[key]; ok && f.blockedUntil.Sub(now) > wait {
+			wait = f.blockedUntil.Sub(now)
+		}
+	}
+	return wait
+}
+
+// recordFailure counts a failed login for the keys and blocks them once the free attempts are used up.
+func (sessions *Sessions) recordFailure(now time.Time, keys ...string) {
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	for key, f := range sessions.failures {
+		if now.Sub(f.last) > loginFailureWindow {
+			delete(sessions.failures, key)
+		}
+	}
+	for _, key := range keys {
+		f, ok := sessions.failures[key]
+		if !ok {
+			f = &loginFailures{}
+			sessions.failures[key] = f
+		}
+		f.count++
+		f.last = now
+		if f.count < loginFreeAttempts {
+			continue
+		}
+		backoff := loginMaxBackoff
+		if shift := f.count - loginFreeAttempts; shift < 16 && loginBackoff<<shift < loginMaxBackoff {
+			backoff = loginBackoff << shift
+		}
+		f.blockedUntil = now.Add(backoff)
+	}
+}
+
+// forgetFailures resets the failed logins of the keys.
+func (sessions *Sessions) forgetFailures(keys ...string) {
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	for _, key := range keys {
+		delete(sessions.failures, key)
+	}
+}
+
+func (sessions *Sessions) showSession() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess := sessions.lookup(r)
+		json.NewEncoder(w).Encode(struct {
+			User      string    `json:"user"`
+			CSRFToken string    `json:"csrfToken"`
+			Expires   time.Time `json:"expires"`
+		}{
+			User:      sess.user,
+			CSRFToken: sess.csrf,
+			Expires:   sess.expires,
+		})
+	})
+}
+
+func (sessions *Sessions) handleLogout() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(sessionCookie); err == nil {
+			sessions.mu.Lock()
+			delete(sessions.sessions, cookie.Value)
+			sessions.mu.Unlock()
+		}
+		sessions.setCookie(w, "", -1)
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+// sameOrigin reports whether a browser request was sent by a page of this server.
+// Requests without origin information do not come from browsers and pass.
+func sameOrigin(r *http.Request) bool {
+	source := r.Header.Get("Origin")
+	if source == "" {
+		source = r.Header.Get("Referer")
+	}
+	if source == "" {
+		return true
+	}
+	u, err := url.Parse(source)
+	return err == nil && u.Host == r.Host
+}
+
+func randomString() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("read random: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+type identityKey struct{}
+
+// withIdentity makes requests to VirtM using the context act as the given identity.
+func withIdentity(ctx context.Context, identity auth.Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lnsp/sox/auth"
+)
+
+func postLogin(handler http.Handler, addr, user, password string) *httptest.ResponseRecorder {
+	form := url.Values{"user": {user}, "password": {password}}
+	r := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.RemoteAddr = addr
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestLoginBackoff(t *testing.T) {
+	sessions := NewSessions(func(ctx context.Context, user, password string) (auth.Identity, error) {
+		if password != "secret" {
+			return auth.Identity{}, errors.New("invalid password")
+		}
+		return auth.Identity{User: user}, nil
+	}, "Password", time.Hour, false, "/")
+	handler := sessions.handleLogin()
+
+	for i := 0; i < loginFreeAttempts; i++ {
+		if w := postLogin(handler, "192.0.2.1:1234", "alice", "wrong"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("failed login %d = %d, want 401", i+1, w.Code)
+		}
+	}
+	// Both the user and the address are blocked, even with the right password
+	w := postLogin(handler, "192.0.2.1:1234", "alice", "secret")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+		t.Fatalf("login after failures = %d, retry after %q, want 429 after 1s", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := postLogin(handler, "192.0.2.2:1234", "alice", "secret"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("login of blocked user from other address = %d, want 429", w.Code)
+	}
+	if w := postLogin(handler, "192.0.2.1:4321", "bob", "secret"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("login of other user from blocked address = %d, want 429", w.Code)
+	}
+	if w := postLogin(handler, "192.0.2.2:1234", "bob", "secret"); w.Code != http.StatusSeeOther {
+		t.Errorf("login of other user from other address = %d, want 303", w.Code)
+	}
+}
+
+func TestLoginBackoffDoubles(t *testing.T) {
+	sessions := NewSessions(nil, "Password", time.Hour, false, "/")
+	now := time.Now()
+	for _, want := range []time.Duration{0, 0, time.Second, 2 * time.Second, 4 * time.Second} {
+		sessions.recordFailure(now, "user:alice")
+		if wait := sessions.throttled(now, "user:alice"); wait != want {
+			t.Errorf("backoff after %d failures = %v, want %v", sessions.failures["user:alice"].count, wait, want)
+		}
+	}
+	for i := 0; i < 40; i++ {
+		sessions.recordFailure(now, "user:alice")
+	}
+	if wait := sessions.throttled(now, "user:alice"); wait != loginMaxBackoff {
+		t.Errorf("backoff after many failures = %v, want %v", wait, loginMaxBackoff)
+	}
+	sessions.forgetFailures("user:alice")
+	if wait := sessions.throttled(now, "user:alice"); wait != 0 {
+		t.Errorf("backoff after reset = %v, want none", wait)
+	}
+	// Failures are forgotten once the window has passed
+	sessions.recordFailure(now, "user:bob")
+	sessions.recordFailure(now.Add(loginFailureWindow+time.Second), "user:carol")
+	if _, ok := sessions.failures["user:bob"]; ok {
+		t.Error("failures of bob outlived the window")
+	}
+}
@@ -0,0 +1,524 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// caller is the user a request is made by along with its project roles.
+type caller struct {
+	user models.User
+	// roles maps project IDs to the role of the user within them.
+	roles map[string]api.Role
+	// selected is the ID of the project given in the request metadata.
+	selected string
+	// scopes limits the requests of callers using a token, nil if not limited.
+	scopes []api.Token_Scope
+}
+
+type callerKey struct{}
+
+// callerFrom returns the caller of the request, nil for requests made by the driver itself.
+func callerFrom(ctx context.Context) *caller {
+	c, _ := ctx.Value(callerKey{}).(*caller)
+	return c
+}
+
+// can reports whether the caller has at least the given role in the project.
+func (c *caller) can(project string, role api.Role) bool {
+	return c.user.Admin || c.roles[project] >= role
+}
+
+// allows reports whether the scopes of the caller include the given scope.
+func (c *caller) allows(scope api.Token_Scope) bool {
+	if c.scopes == nil || scope == api.Token_SCOPE_UNSPECIFIED {
+		return true
+	}
+	for _, s := range c.scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// project returns the project new resources are created in. Without a selection
+// it is the only project the caller is a member of.
+func (c *caller) project() string {
+	if c.selected != "" {
+		return c.selected
+	}
+	if len(c.roles) == 1 {
+		for id := range c.roles {
+			return id
+		}
+	}
+	return ""
+}
+
+// visibleProjects returns the projects whose resources the caller of a list request sees.
+// Admins see all projects unless they selected one.
+func visibleProjects(ctx context.Context) (projects []string, all bool) {
+	c := callerFrom(ctx)
+	switch {
+	case c == nil:
+		return nil, true
+	case c.selected != "":
+		return []string{c.selected}, false
+	case c.user.Admin:
+		return nil, true
+	}
+	projects = make([]string, 0, len(c.roles))
+	for id := range c.roles {
+		projects = append(projects, id)
+	}
+	return projects, false
+}
+
+// scoped limits a query to the resources visible to the caller of the request.
+func scoped(ctx context.Context, tx *gorm.DB) *gorm.DB {
+	projects, all := visibleProjects(ctx)
+	if all {
+		return tx
+	}
+	return tx.Where("project_id IN ?", projects)
+}
+
+// projectFrom returns the project resources created by the request belong to.
+func projectFrom(ctx context.Context) string {
+	if c := callerFrom(ctx); c != nil {
+		return c.project()
+	}
+	return models.DefaultProjectID
+}
+
+// resource references a model by its ID or another unique column.
+type resource struct {
+	kind  string
+	id    string
+	model interface{}
+	// where matches the reference, every placeholder is filled with the ID.
+	where string
+	// column holds the ID of the owning project.
+	column string
+	// public is set for models which can be shared with all projects.
+	public bool
+}
+
+func machineRef(id string) resource {
+	return resource{kind: "machine", id: id, model: &models.Machine{}, where: "id = ? OR name = ?", column: "project_id"}
+}
+
+func networkRef(id string) resource {
+	return resource{kind: "network", id: id, model: &models.Network{}, where: "id = ? OR name = ?", column: "project_id"}
+}
+
+func sshKeyRef(id string) resource {
+	return resource{kind: "ssh key", id: id, model: &models.SSHKey{}, where: "id = ? OR name = ?", column: "project_id"}
+}
+
+func imageRef(id string) resource {
+	return resource{kind: "image", id: id, model: &models.Image{}, where: "id = ? OR name = ?", column: "project_id", public: true}
+}
+
+func securityGroupRef(id string) resource {
+	return resource{kind: "security group", id: id, model: &models.SecurityGroup{}, where: "id = ? OR name = ?", column: "project_id"}
+}
+
+func portForwardRef(id string) resource {
+	return resource{kind: "port forward", id: id, model: &models.PortForward{}, where: "id = ?", column: "project_id"}
+}
+
+func floatingIPRef(id string) resource {
+	return resource{kind: "floating ip", id: id, model: &models.FloatingIP{}, where: "id = ? OR address = ?", column: "project_id"}
+}
+
+func projectRef(id string) resource {
+	return resource{kind: "project", id: id, model: &models.Project{}, where: "id = ? OR name = ?", column: "id"}
+}
+
+// accessRule describes the role a request requires. Requests without a rule are reserved to admins.
+type accessRule struct {
+	role api.Role
+	// list requests only require a membership, their results are limited to the visible projects.
+	list bool
+	// target returns the resource the request acts on, its project is checked. Requests
+	// without a target, or with an empty one, act in the project selected by the caller.
+	target func(req interface{}) resource
+	// uses returns further resources the request refers to, they have to belong to the same project.
+	uses func(req interface{}) []resource
+	// self requests are open to all users, they only act on the caller itself.
+	self bool
+}
+
+// scope returns the token scope the request requires.
+func (rule accessRule) scope() api.Token_Scope {
+	switch {
+	case rule.self:
+		return api.Token_SCOPE_UNSPECIFIED
+	case rule.list || rule.role == api.Role_VIEWER:
+		return api.Token_READ
+	case rule.role == api.Role_OPERATOR:
+		return api.Token_WRITE
+	default:
+		return api.Token_MEMBERS
+	}
+}
+
+var accessRules = map[string]accessRule{
+	"CreateMachine": {role: api.Role_OPERATOR, uses: func(req interface{}) []resource {
+		r := req.(*api.CreateMachineRequest)
+		uses := []resource{imageRef(r.ImageId)}
+		for _, id := range r.SshKeyIds {
+			uses = append(uses, sshKeyRef(id))
+		}
+		for _, id := range r.NetworkIds {
+			uses = append(uses, networkRef(id))
+		}
+		for _, id := range r.SecurityGroupIds {
+			uses = append(uses, securityGroupRef(id))
+		}
+		return uses
+	}},
+	"ListMachines": {list: true},
+	"GetMachineDetails": {role: api.Role_VIEWER, target: func(req interface{}) resource {
+		return machineRef(req.(*api.GetMachineDetailsRequest).Id)
+	}},
+	"GetMachineStats": {role: api.Role_VIEWER, target: func(req interface{}) resource {
+		return machineRef(req.(*api.GetMachineStatsRequest).Id)
+	}},
+	"DeleteMachine": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return machineRef(req.(*api.DeleteMachineRequest).Id)
+	}},
+	"TriggerMachine": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return machineRef(req.(*api.TriggerMachineRequest).Id)
+	}},
+	"CreateSSHKey": {role: api.Role_OPERATOR},
+	"ListSSHKeys":  {list: true},
+	"DeleteSSHKey": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return sshKeyRef(req.(*api.DeleteSSHKeyRequest).Id)
+	}},
+	"ListImages":     {list: true},
+	"ListNetworks":   {list: true},
+	"CreateNetwork":  {role: api.Role_OPERATOR},
+	"ListActivities": {list: true},
+	"CreateSecurityGroup": {role: api.Role_OPERATOR, uses: func(req interface{}) []resource {
+		return remoteGroups(req.(*api.CreateSecurityGroupRequest).Rules)
+	}},
+	"ListSecurityGroups": {list: true},
+	"UpdateSecurityGroup": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return securityGroupRef(req.(*api.UpdateSecurityGroupRequest).Id)
+	}, uses: func(req interface{}) []resource {
+		return remoteGroups(req.(*api.UpdateSecurityGroupRequest).Rules)
+	}},
+	"DeleteSecurityGroup": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return securityGroupRef(req.(*api.DeleteSecurityGroupRequest).Id)
+	}},
+	"AttachSecurityGroup": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return machineRef(req.(*api.AttachSecurityGroupRequest).MachineId)
+	}, uses: func(req interface{}) []resource {
+		r := req.(*api.AttachSecurityGroupRequest)
+		return []resource{securityGroupRef(r.SecurityGroupId), networkRef(r.NetworkId)}
+	}},
+	"DetachSecurityGroup": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return machineRef(req.(*api.DetachSecurityGroupRequest).MachineId)
+	}},
+	"CreatePortForward": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return machineRef(req.(*api.CreatePortForwardRequest).MachineId)
+	}, uses: func(req interface{}) []resource {
+		return []resource{networkRef(req.(*api.CreatePortForwardRequest).NetworkId)}
+	}},
+	"ListPortForwards": {list: true},
+	"DeletePortForward": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return portForwardRef(req.(*api.DeletePortForwardRequest).Id)
+	}},
+	"AllocateFloatingIP": {role: api.Role_OPERATOR},
+	"ListFloatingIPs":    {list: true},
+	"AssociateFloatingIP": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return floatingIPRef(req.(*api.AssociateFloatingIPRequest).Id)
+	}, uses: func(req interface{}) []resource {
+		r := req.(*api.AssociateFloatingIPRequest)
+		return []resource{machineRef(r.MachineId), networkRef(r.NetworkId)}
+	}},
+	"ReleaseFloatingIP": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return floatingIPRef(req.(*api.ReleaseFloatingIPRequest).Id)
+	}},
+	"AttachNetworkInterface": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return machineRef(req.(*api.AttachNetworkInterfaceRequest).MachineId)
+	}, uses: func(req interface{}) []resource {
+		r := req.(*api.AttachNetworkInterfaceRequest)
+		uses := []resource{networkRef(r.NetworkId)}
+		for _, id := range r.SecurityGroupIds {
+			uses = append(uses, securityGroupRef(id))
+		}
+		return uses
+	}},
+	"DetachNetworkInterface": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return machineRef(req.(*api.DetachNetworkInterfaceRequest).MachineId)
+	}},
+	"UpdateNetworkInterfaceQoS": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return machineRef(req.(*api.UpdateNetworkInterfaceQoSRequest).MachineId)
+	}},
+	"ListNodes":     {list: true},
+	"GetHostInfo":   {list: true},
+	"WatchEvents":   {list: true},
+	"CreateWebhook": {role: api.Role_OPERATOR},
+	"ListWebhooks":  {list: true},
+	"DeleteWebhook": {role: api.Role_OPERATOR, target: func(req interface{}) resource {
+		return webhookRef(req.(*api.DeleteWebhookRequest).Id)
+	}},
+	"ListWebhookDeliveries": {role: api.Role_VIEWER, target: func(req interface{}) resource {
+		return webhookRef(req.(*api.ListWebhookDeliveriesRequest).WebhookId)
+	}},
+	"ListProjects": {list: true},
+	"ListMemberships": {role: api.Role_VIEWER, target: func(req interface{}) resource {
+		return projectRef(req.(*api.ListMembershipsRequest).ProjectId)
+	}},
+	"SetMembership": {role: api.Role_ADMIN, target: func(req interface{}) resource {
+		return projectRef(req.(*api.SetMembershipRequest).ProjectId)
+	}},
+	"RemoveMembership": {role: api.Role_ADMIN, target: func(req interface{}) resource {
+		return projectRef(req.(*api.RemoveMembershipRequest).ProjectId)
+	}},
+	"GetQuotaUsage": {role: api.Role_VIEWER, target: func(req interface{}) resource {
+		return projectRef(req.(*api.GetQuotaUsageRequest).ProjectId)
+	}},
+	"GetCurrentUser": {self: true},
+	"CreateToken":    {self: true},
+	"ListTokens":     {self: true},
+	"DeleteToken":    {self: true},
+}
+
+func remoteGroups(rules []*api.SecurityGroup_Rule) []resource {
+	var uses []resource
+	for _, rule := range rules {
+		uses = append(uses, securityGroupRef(rule.RemoteGroupId))
+	}
+	return uses
+}
+
+// UnaryInterceptor checks the role of the caller before handing the request to the driver.
+// Denied and failed requests are recorded as activities.
+func (driver *Driver) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = driver.withRequest(ctx, info.FullMethod, req)
+		authorized, err := driver.authorize(ctx, info.FullMethod, req)
+		if err != nil {
+			driver.recordFailure(ctx, info.FullMethod, req, err)
+			return nil, err
+		}
+		resp, err := handler(authorized, req)
+		if err != nil {
+			driver.recordFailure(authorized, info.FullMethod, req, err)
+		} else if identityMethods[strings.TrimPrefix(info.FullMethod, "/"+api.Sox_ServiceDesc.ServiceName+"/")] {
+			driver.bumpIdentityRevision()
+		}
+		return resp, err
+	}
+}
+
+// StreamInterceptor checks the role of the caller of streaming requests. Their request messages
+// are not known yet, so the rules of streaming methods must not have a target.
+func (driver *Driver) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := driver.authorize(stream.Context(), info.FullMethod, nil)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authorizedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authorizedStream) Context() context.Context {
+	return stream.ctx
+}
+
+// authorize identifies the caller and checks its role against the access rule of the method.
+func (driver *Driver) authorize(ctx context.Context, method string, req interface{}) (context.Context, error) {
+	c, err := driver.identify(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, callerKey{}, c)
+	name := strings.TrimPrefix(method, "/"+api.Sox_ServiceDesc.ServiceName+"/")
+	rule, ok := accessRules[name]
+	scope := api.Token_ADMIN
+	if ok {
+		scope = rule.scope()
+	}
+	if !c.allows(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s requires a token with the %s scope", name, scope)
+	}
+	switch {
+	case c.user.Admin:
+		return ctx, nil
+	case !ok:
+		return nil, status.Errorf(codes.PermissionDenied, "%s is reserved to admins", name)
+	case rule.self:
+		return ctx, nil
+	case rule.list:
+		if len(c.roles) == 0 {
+			return nil, status.Errorf(codes.PermissionDenied, "user %s is not a member of any project", c.user.Name)
+		}
+		return ctx, nil
+	}
+	var (
+		project string
+		target  resource
+	)
+	if rule.target != nil {
+		target = rule.target(req)
+	}
+	if target.id != "" {
+		if project, _, err = driver.projectOf(target); err != nil {
+			return nil, err
+		}
+	} else if project = c.project(); project == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "select a project using the %s metadata", auth.ProjectMetadataKey)
+	}
+	if !c.can(project, rule.role) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s requires the %s role in project %s", name, rule.role, project)
+	}
+	if rule.uses == nil {
+		return ctx, nil
+	}
+	for _, r := range rule.uses(req) {
+		if r.id == "" {
+			continue
+		}
+		owner, public, err := driver.projectOf(r)
+		if err != nil {
+			return nil, err
+		}
+		if owner != project && !public {
+			return nil, status.Errorf(codes.PermissionDenied, "%s %s belongs to another project", r.kind, r.id)
+		}
+	}
+	return ctx, nil
+}
+
+// identify authenticates the caller using its token or client certificate and looks up
+// the selected project. Authenticated admins may act on behalf of the user named in the
+// request metadata. Unless authentication is required, the named user is trusted as is.
+func (driver *Driver) identify(ctx context.Context) (*caller, error) {
+	identity := auth.FromIncomingContext(ctx)
+	var (
+		user   models.User
+		scopes []api.Token_Scope
+		err    error
+	)
+	switch peer := auth.UserFromPeer(ctx); {
+	case identity.Token != "":
+		if user, scopes, err = driver.verifyToken(identity.Token); err != nil {
+			return nil, err
+		}
+	case peer != "":
+		if user, err = driver.findUser(peer); err != nil {
+			return nil, err
+		}
+	case driver.authRequired:
+		return nil, status.Errorf(codes.Unauthenticated, "missing token or client certificate")
+	case identity.User == "":
+		return nil, status.Errorf(codes.Unauthenticated, "missing %s metadata", auth.UserMetadataKey)
+	}
+	if identity.User != "" && identity.User != user.ID && identity.User != user.Name {
+		if user.ID != "" && !user.Admin {
+			return nil, status.Errorf(codes.PermissionDenied, "only admins may act on behalf of other users")
+		}
+		if user, err = driver.findUser(identity.User); err != nil {
+			return nil, err
+		}
+	}
+	var memberships []models.Membership
+	if err := driver.db.Where("user_id = ?", user.ID).Find(&memberships).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve memberships: %v", err)
+	}
+	c := &caller{
+		user:   user,
+		roles:  make(map[string]api.Role, len(memberships)),
+		scopes: scopes,
+	}
+	for _, membership := range memberships {
+		c.roles[membership.ProjectID] = api.Role(api.Role_value[membership.Role])
+	}
+	if identity.Project != "" {
+		project, _, err := driver.projectOf(projectRef(identity.Project))
+		if err != nil {
+			return nil, err
+		}
+		if !c.can(project, api.Role_VIEWER) {
+			return nil, status.Errorf(codes.PermissionDenied, "user %s is not a member of project %s", user.Name, identity.Project)
+		}
+		c.selected = project
+	}
+	return c, nil
+}
+
+// findUser looks up a user by ID or name.
+func (driver *Driver) findUser(id string) (models.User, error) {
+	var user models.User
+	if err := driver.db.Where("id = ? OR name = ?", id, id).Limit(1).Find(&user).Error; err != nil {
+		return user, status.Errorf(codes.Internal, "retrieve user: %v", err)
+	}
+	if user.ID == "" {
+		return user, status.Errorf(codes.Unauthenticated, "unknown user %s", id)
+	}
+	return user, nil
+}
+
+// verifyToken returns the user and scopes of a token secret.
+func (driver *Driver) verifyToken(secret string) (models.User, []api.Token_Scope, error) {
+	var token models.Token
+	if err := driver.db.Where("hash = ?", auth.HashToken(secret)).Limit(1).Find(&token).Error; err != nil {
+		return models.User{}, nil, status.Errorf(codes.Internal, "retrieve token: %v", err)
+	}
+	if token.ID == "" {
+		return models.User{}, nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	if token.ExpiresAt != nil && token.ExpiresAt.Before(time.Now()) {
+		return models.User{}, nil, status.Errorf(codes.Unauthenticated, "token %s expired", token.Name)
+	}
+	user, err := driver.findUser(token.UserID)
+	if err != nil {
+		return user, nil, err
+	}
+	return user, parseScopes(token.Scopes), nil
+}
+
+// projectOf returns the project owning the resource and whether it is shared with all projects.
+func (driver *Driver) projectOf(r resource) (string, bool, error) {
+	var owner struct {
+		ProjectID string
+		Public    bool
+	}
+	columns := r.column + " AS project_id"
+	if r.public {
+		columns += ", public"
+	}
+	args := make([]interface{}, strings.Count(r.where, "?"))
+	for i := range args {
+		args[i] = r.id
+	}
+	result := driver.db.Model(r.model).Select(columns).Where(r.where, args...).Limit(1).Scan(&owner)
+	if result.Error != nil {
+		return "", false, status.Errorf(codes.Internal, "retrieve %s: %v", r.kind, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return "", false, status.Errorf(codes.NotFound, "%s %s not found", r.kind, r.id)
+	}
+	return owner.ProjectID, owner.Public, nil
+}
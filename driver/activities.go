@@ -0,0 +1,340 @@
+package driver
+
+import (
+	"context"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultActivityPageSize = 100
+	maxActivityPageSize     = 1000
+	// activityPruneInterval is the time between deletions of expired activities.
+	activityPruneInterval = time.Hour
+)
+
+// activityKinds maps the prefixes of activity types to the kind of their subject.
+var activityKinds = []struct{ prefix, kind string }{
+	{"MACHINE_", "machine"},
+	{"NETWORK_INTERFACE_", "machine"},
+	{"IMAGE_", "image"},
+	{"SSHKEY_", "ssh key"},
+	{"SECURITY_GROUP_", "security group"},
+	{"PORT_FORWARD_", "port forward"},
+	{"FLOATING_IP_", "floating ip"},
+	{"VPN_PEER_", "vpn peer"},
+	{"NODE_", "node"},
+	{"USER_", "user"},
+	{"PROJECT_", "project"},
+	{"MEMBERSHIP_", "project"},
+	{"TOKEN_", "token"},
+	{"QUOTA_", "project"},
+	{"WEBHOOK_", "webhook"},
+}
+
+func activityKind(activityType api.Activity_Type) string {
+	for _, k := range activityKinds {
+		if strings.HasPrefix(activityType.String(), k.prefix) {
+			return k.kind
+		}
+	}
+	return ""
+}
+
+// sensitiveFields are request fields whose values are redacted from activity details.
+var sensitiveFields = map[protoreflect.Name]bool{
+	"secret":        true,
+	"password":      true,
+	"token":         true,
+	"private_key":   true,
+	"preshared_key": true,
+	// Token hashes of identity snapshots
+	"hash": true,
+}
+
+type requestKey struct{}
+
+// requestInfo is the request activities are recorded for.
+type requestInfo struct {
+	method  string
+	message interface{}
+	// project owns the target of the request before it was changed.
+	project string
+}
+
+// withRequest attaches the request to ctx. The project of the target of changes is looked up
+// beforehand, so activities of deleted resources can still be attributed to it.
+func (driver *Driver) withRequest(ctx context.Context, method string, message interface{}) context.Context {
+	info := &requestInfo{method: method, message: message}
+	name := strings.TrimPrefix(method, "/"+api.Sox_ServiceDesc.ServiceName+"/")
+	if rule, ok := accessRules[name]; ok && rule.target != nil && !readOnly(name) {
+		if target := rule.target(message); target.id != "" {
+			info.project, _, _ = driver.projectOf(target)
+		}
+	}
+	return context.WithValue(ctx, requestKey{}, info)
+}
+
+// resourceRefs references the resources owned by projects by the kind of activity subjects.
+var resourceRefs = map[string]func(id string) resource{
+	"machine":        machineRef,
+	"network":        networkRef,
+	"ssh key":        sshKeyRef,
+	"image":          imageRef,
+	"security group": securityGroupRef,
+	"port forward":   portForwardRef,
+	"floating ip":    floatingIPRef,
+	"project":        projectRef,
+	"webhook":        webhookRef,
+}
+
+// subjectProject returns the project owning the subject of an activity, empty if it is not owned by a project.
+func (driver *Driver) subjectProject(ctx context.Context, kind, subject string) string {
+	ref, ok := resourceRefs[kind]
+	if !ok || subject == "" {
+		return ""
+	}
+	if project, _, err := driver.projectOf(ref(subject)); err == nil {
+		return project
+	}
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		return info.project
+	}
+	return ""
+}
+
+// readOnly reports if the method does not change any resources. Failures of these are not recorded.
+func readOnly(name string) bool {
+	for _, prefix := range []string{"List", "Get", "Read", "Export", "Watch"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// newActivity describes the request in ctx, its caller and the client it came from.
+func newActivity(ctx context.Context, activityType api.Activity_Type, subject string) models.Activity {
+	activity := models.Activity{
+		Type:    activityType.String(),
+		Subject: subject,
+	}
+	if c := callerFrom(ctx); c != nil {
+		activity.Actor = c.user.Name
+	} else {
+		activity.Actor = auth.FromIncomingContext(ctx).User
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		activity.SourceAddress = p.Addr.String()
+		if host, _, err := net.SplitHostPort(activity.SourceAddress); err == nil {
+			activity.SourceAddress = host
+		}
+	}
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		activity.Method = info.method
+		activity.Details = redactedJSON(info.message)
+	}
+	return activity
+}
+
+// redactedJSON encodes the request message without binary data and the values of sensitive fields.
+func redactedJSON(message interface{}) string {
+	m, ok := message.(proto.Message)
+	if !ok {
+		return ""
+	}
+	m = proto.Clone(m)
+	redact(m.ProtoReflect())
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func redact(m protoreflect.Message) {
+	var fields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	for _, fd := range fields {
+		switch {
+		case sensitiveFields[fd.Name()] && fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated:
+			m.Set(fd, protoreflect.ValueOfString("[redacted]"))
+		case sensitiveFields[fd.Name()] || fd.Kind() == protoreflect.BytesKind:
+			m.Clear(fd)
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				m.Mutable(fd).Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+					redact(v.Message())
+					return true
+				})
+			}
+		case fd.Kind() != protoreflect.MessageKind:
+		case fd.IsList():
+			list := m.Mutable(fd).List()
+			for i := 0; i < list.Len(); i++ {
+				redact(list.Get(i).Message())
+			}
+		default:
+			redact(m.Mutable(fd).Message())
+		}
+	}
+}
+
+// recordActivity records the activity together with the caller and parameters of the request in ctx
+// and publishes it to watchers.
+func (driver *Driver) recordActivity(ctx context.Context, activityType api.Activity_Type, subject string) {
+	activity := newActivity(ctx, activityType, subject)
+	activity.Kind = activityKind(activityType)
+	activity.ProjectID = driver.subjectProject(ctx, activity.Kind, subject)
+	if err := driver.db.Create(&activity).Error; err != nil {
+		log.Println("record activity", activityType, "of", subject, "failed:", err)
+		return
+	}
+	driver.publishActivity(&activity)
+}
+
+// recordFailure records requests which failed or were denied, unless they only read resources.
+func (driver *Driver) recordFailure(ctx context.Context, method string, req interface{}, err error) {
+	name := strings.TrimPrefix(method, "/"+api.Sox_ServiceDesc.ServiceName+"/")
+	if readOnly(name) {
+		return
+	}
+	activity := newActivity(ctx, api.Activity_REQUEST_FAILED, "")
+	if rule, ok := accessRules[name]; ok && rule.target != nil && req != nil {
+		target := rule.target(req)
+		activity.Subject, activity.Kind = target.id, target.kind
+	}
+	activity.ProjectID = driver.subjectProject(ctx, activity.Kind, activity.Subject)
+	activity.Error = status.Convert(err).Message()
+	if err := driver.db.Create(&activity).Error; err != nil {
+		log.Println("record failure of", name, "failed:", err)
+		return
+	}
+	driver.publishActivity(&activity)
+}
+
+// pruneActivities deletes activities and finished webhook deliveries older than the retention period
+// every activityPruneInterval.
+func (driver *Driver) pruneActivities(retention time.Duration) {
+	ticker := time.NewTicker(activityPruneInterval)
+	defer ticker.Stop()
+	for {
+		result := driver.db.Unscoped().Where("created_at < ?", time.Now().Add(-retention)).Delete(&models.Activity{})
+		if result.Error != nil {
+			log.Println("prune activities failed:", result.Error)
+		} else if result.RowsAffected > 0 {
+			log.Println("pruned", result.RowsAffected, "activities older than", retention)
+		}
+		result = driver.db.Where("created_at < ? AND status <> ?", time.Now().Add(-retention), api.WebhookDelivery_PENDING.String()).Delete(&models.WebhookDelivery{})
+		if result.Error != nil {
+			log.Println("prune webhook deliveries failed:", result.Error)
+		}
+		<-ticker.C
+	}
+}
+
+func (driver *Driver) activityToApi(activity *models.Activity) *api.Activity {
+	return &api.Activity{
+		Id:            strconv.FormatUint(uint64(activity.ID), 10),
+		Type:          api.Activity_Type(api.Activity_Type_value[activity.Type]),
+		Timestamp:     timestamppb.New(activity.CreatedAt),
+		Subject:       activity.Subject,
+		Node:          driver.nodeName,
+		Kind:          activity.Kind,
+		Actor:         activity.Actor,
+		SourceAddress: activity.SourceAddress,
+		Method:        activity.Method,
+		Details:       activity.Details,
+		Error:         activity.Error,
+	}
+}
+
+func activityPageSize(requested int32) int {
+	switch {
+	case requested <= 0:
+		return defaultActivityPageSize
+	case requested > maxActivityPageSize:
+		return maxActivityPageSize
+	}
+	return int(requested)
+}
+
+func (driver *Driver) ListActivities(ctx context.Context, request *api.ListActivitiesRequest) (*api.ListActivitiesResponse, error) {
+	// Activities are listed if their subject belongs to a visible project or the caller caused them
+	query := driver.db
+	if projects, all := visibleProjects(ctx); !all {
+		subjects := []string{"project_id IN ?", "subject IN ?"}
+		args := []interface{}{projects, projects}
+		for _, model := range projectModels {
+			subjects = append(subjects, "subject IN (?)")
+			args = append(args, driver.db.Model(model).Select("id").Where("project_id IN ?", projects))
+		}
+		if c := callerFrom(ctx); c != nil {
+			subjects = append(subjects, "actor = ?")
+			args = append(args, c.user.Name)
+		}
+		query = query.Where(strings.Join(subjects, " OR "), args...)
+	}
+	if request.Subject != "" {
+		query = query.Where("subject = ?", request.Subject)
+	}
+	if len(request.Types) > 0 {
+		types := make([]string, len(request.Types))
+		for i := range request.Types {
+			types[i] = request.Types[i].String()
+		}
+		query = query.Where("type IN ?", types)
+	}
+	if request.Since != nil {
+		query = query.Where("created_at >= ?", request.Since.AsTime())
+	}
+	if request.Until != nil {
+		query = query.Where("created_at < ?", request.Until.AsTime())
+	}
+	order, after := "id DESC", "id < ?"
+	if request.OldestFirst {
+		order, after = "id", "id > ?"
+	}
+	if request.PageToken != "" {
+		last, err := strconv.ParseUint(request.PageToken, 10, 64)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token")
+		}
+		query = query.Where(after, last)
+	}
+	size := activityPageSize(request.PageSize)
+	activities := []models.Activity{}
+	if result := query.Order(order).Limit(size + 1).Find(&activities); result.Error != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve activities: %v", result.Error)
+	}
+	var nextPageToken string
+	if len(activities) > size {
+		activities = activities[:size]
+		nextPageToken = strconv.FormatUint(uint64(activities[size-1].ID), 10)
+	}
+	apiActivities := make([]*api.Activity, len(activities))
+	for i := range activities {
+		apiActivities[i] = driver.activityToApi(&activities[i])
+	}
+	return &api.ListActivitiesResponse{
+		Activities:    apiActivities,
+		NextPageToken: nextPageToken,
+	}, nil
+}
@@ -0,0 +1,111 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// Overcommit holds the ratios of resources allocatable to machines to the capacity of the host.
+type Overcommit struct {
+	CPUs   float64
+	Memory float64
+	Disk   float64
+}
+
+// DefaultOvercommit shares each host CPU among four vCPUs and does not overcommit memory and disk.
+var DefaultOvercommit = Overcommit{CPUs: 4, Memory: 1, Disk: 1}
+
+// limit scales the capacity of the host by the overcommit ratios.
+func (overcommit Overcommit) limit(capacity models.Specs) models.Specs {
+	return models.Specs{
+		CPUs:   int64(float64(capacity.CPUs) * overcommit.CPUs),
+		Memory: int64(float64(capacity.Memory) * overcommit.Memory),
+		Disk:   int64(float64(capacity.Disk) * overcommit.Disk),
+	}
+}
+
+// allocatedSpecs sums up the specs of all machines on the node.
+func allocatedSpecs(tx *gorm.DB) (models.Specs, error) {
+	var allocated models.Specs
+	if err := tx.Model(&models.Machine{}).
+		// Machines created with negative specs before they were rejected must not free up capacity
+		Select("COALESCE(SUM(MAX(cpus, 0)), 0) AS cpus, COALESCE(SUM(MAX(memory, 0)), 0) AS memory, COALESCE(SUM(MAX(disk, 0)), 0) AS disk").
+		Scan(&allocated).Error; err != nil {
+		return allocated, fmt.Errorf("sum machine specs: %w", err)
+	}
+	return allocated, nil
+}
+
+// checkCapacity rejects machines which do not fit on the host next to the machines already placed on it.
+// It has to be called with quotaMu held, within the transaction creating the machine.
+func (driver *Driver) checkCapacity(tx *gorm.DB, specs models.Specs) error {
+	// Non-positive specs would make room for other machines
+	if specs.CPUs <= 0 || specs.Memory <= 0 || specs.Disk <= 0 {
+		return status.Errorf(codes.InvalidArgument, "specs must be positive, got %s", specs)
+	}
+	host, err := driver.lv.GetHostInfo()
+	if err != nil {
+		return status.Errorf(codes.Internal, "get host info: %v", err)
+	}
+	allocated, err := allocatedSpecs(tx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	limit := driver.overcommit.limit(host.Capacity)
+	for _, resource := range []struct {
+		name                        string
+		limit, allocated, requested int64
+	}{
+		{"vCPUs", limit.CPUs, allocated.CPUs, specs.CPUs},
+		{"MB memory", limit.Memory, allocated.Memory, specs.Memory},
+		{"GB disk", limit.Disk, allocated.Disk, specs.Disk},
+	} {
+		if resource.allocated+resource.requested > resource.limit {
+			return status.Errorf(codes.ResourceExhausted, "capacity of node %s exceeded: %d of %d %s allocated, %d requested", driver.nodeName, resource.allocated, resource.limit, resource.name, resource.requested)
+		}
+	}
+	return nil
+}
+
+func specsToApi(specs models.Specs) *api.Node_Resources {
+	return &api.Node_Resources{
+		Cpus:   specs.CPUs,
+		Memory: specs.Memory,
+		Disk:   specs.Disk,
+	}
+}
+
+func (driver *Driver) GetHostInfo(ctx context.Context, request *api.GetHostInfoRequest) (*api.GetHostInfoResponse, error) {
+	if request.Node != "" && request.Node != driver.nodeName {
+		return nil, status.Errorf(codes.NotFound, "node %s not found", request.Node)
+	}
+	host, err := driver.lv.GetHostInfo()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get host info: %v", err)
+	}
+	allocated, err := allocatedSpecs(driver.db)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return &api.GetHostInfoResponse{
+		Hosts: []*api.HostInfo{{
+			Node:      driver.nodeName,
+			CpuModel:  host.CPUModel,
+			Capacity:  specsToApi(host.Capacity),
+			Free:      specsToApi(host.Free),
+			Allocated: specsToApi(allocated),
+			Limit:     specsToApi(driver.overcommit.limit(host.Capacity)),
+			Overcommit: &api.HostInfo_Overcommit{
+				Cpus:   driver.overcommit.CPUs,
+				Memory: driver.overcommit.Memory,
+				Disk:   driver.overcommit.Disk,
+			},
+		}},
+	}, nil
+}
@@ -44,6 +44,17 @@ type CloudConfig struct {
 
 	// WriteFilesModule
 	WriteFiles []WriteFile `yaml:"write_files"`
+
+	// Event and update handling
+	Updates *Updates `yaml:"updates,omitempty"`
+}
+
+type UpdatesNetwork struct {
+	When []string `yaml:"when"`
+}
+
+type Updates struct {
+	Network UpdatesNetwork `yaml:"network"`
 }
 
 type WriteFile struct {
@@ -55,11 +66,19 @@ type NetworkNameservers struct {
 	SearchDomains []string `yaml:"search"`
 }
 
+type NetworkMatch struct {
+	MACAddress string `yaml:"macaddress,omitempty"`
+}
+
 type NetworkEthernet struct {
+	Match       NetworkMatch       `yaml:"match,omitempty"`
 	DHCPv4      bool               `yaml:"dhcp4"`
-	Addresses   []string           `yaml:"addresses"`
-	GatewayIPv4 string             `yaml:"gateway4"`
-	Nameservers NetworkNameservers `yaml:"nameservers"`
+	DHCPv6      bool               `yaml:"dhcp6"`
+	Addresses   []string           `yaml:"addresses,omitempty"`
+	GatewayIPv4 string             `yaml:"gateway4,omitempty"`
+	GatewayIPv6 string             `yaml:"gateway6,omitempty"`
+	Nameservers NetworkNameservers `yaml:"nameservers,omitempty"`
+	MTU         uint32             `yaml:"mtu,omitempty"`
 }
 
 type NetworkConfig struct {
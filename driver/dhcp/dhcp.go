@@ -0,0 +1,235 @@
+package dhcp
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv4/server4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/dhcpv6/server6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/insomniacslk/dhcp/rfc1035label"
+	"github.com/lnsp/sox/driver/models"
+)
+
+// leaseTime is the lifetime of handed out leases. Addresses are static, so
+// clients only have to come back once in a while.
+const leaseTime = 24 * time.Hour
+
+// LeaseFunc looks up the network interface registered for a hardware address.
+type LeaseFunc func(hwAddr string) (*models.NetworkInterface, error)
+
+// Server answers DHCPv4 and DHCPv6 requests on a single bridge.
+type Server struct {
+	network  *models.Network
+	lookup   LeaseFunc
+	serverIP net.IP
+	// serverIPv6 is the host address guests resolve through over IPv6, nil if the host has none.
+	serverIPv6 net.IP
+	duid       dhcpv6.Duid
+
+	v4 *server4.Server
+	v6 *server6.Server
+}
+
+// New starts a DHCP responder on the bridge of the given network.
+func New(network *models.Network, lookup LeaseFunc) (*Server, error) {
+	ifname := network.NetlinkBridge()
+	link, err := net.InterfaceByName(ifname)
+	if err != nil {
+		return nil, fmt.Errorf("find bridge: %w", err)
+	}
+	serverIP := network.HostIPv4()
+	if serverIP == nil {
+		return nil, fmt.Errorf("network %s has no host address", network.Name)
+	}
+	server := &Server{
+		network:    network,
+		lookup:     lookup,
+		serverIP:   serverIP,
+		serverIPv6: network.HostIPv6(),
+		duid: dhcpv6.Duid{
+			Type:          dhcpv6.DUID_LL,
+			HwType:        iana.HWTypeEthernet,
+			LinkLayerAddr: link.HardwareAddr,
+		},
+	}
+	server.v4, err = server4.NewServer(ifname, nil, server.handle4)
+	if err != nil {
+		return nil, fmt.Errorf("listen dhcpv4: %w", err)
+	}
+	go func() {
+		if err := server.v4.Serve(); err != nil {
+			log.Println("dhcpv4 server on", ifname, "stopped:", err)
+		}
+	}()
+	if network.IPv6.Subnet != "" {
+		server.v6, err = server6.NewServer(ifname, nil, server.handle6)
+		if err != nil {
+			server.v4.Close()
+			return nil, fmt.Errorf("listen dhcpv6: %w", err)
+		}
+		go func() {
+			if err := server.v6.Serve(); err != nil {
+				log.Println("dhcpv6 server on", ifname, "stopped:", err)
+			}
+		}()
+	}
+	log.Println("started dhcp server on", ifname)
+	return server, nil
+}
+
+// Close stops all responders of the server.
+func (server *Server) Close() error {
+	if server.v6 != nil {
+		if err := server.v6.Close(); err != nil {
+			return fmt.Errorf("close dhcpv6: %w", err)
+		}
+	}
+	if err := server.v4.Close(); err != nil {
+		return fmt.Errorf("close dhcpv4: %w", err)
+	}
+	return nil
+}
+
+func (server *Server) handle4(conn net.PacketConn, peer net.Addr, request *dhcpv4.DHCPv4) {
+	if request.OpCode != dhcpv4.OpcodeBootRequest {
+		return
+	}
+	var replyType dhcpv4.MessageType
+	switch request.MessageType() {
+	case dhcpv4.MessageTypeDiscover:
+		replyType = dhcpv4.MessageTypeOffer
+	case dhcpv4.MessageTypeRequest:
+		replyType = dhcpv4.MessageTypeAck
+	default:
+		return
+	}
+	// Interfaces owned by other hosts on the same VXLAN are not ours to answer
+	iface, err := server.lookup(request.ClientHWAddr.String())
+	if err != nil {
+		log.Println("dhcpv4: no lease for", request.ClientHWAddr, err)
+		return
+	}
+	ip, ipnet, err := net.ParseCIDR(iface.IPv4)
+	if err != nil {
+		log.Println("dhcpv4: parse lease address:", err)
+		return
+	}
+	modifiers := []dhcpv4.Modifier{
+		dhcpv4.WithMessageType(replyType),
+		dhcpv4.WithYourIP(ip),
+		dhcpv4.WithServerIP(server.serverIP),
+		dhcpv4.WithNetmask(ipnet.Mask),
+		dhcpv4.WithLeaseTime(uint32(leaseTime.Seconds())),
+		dhcpv4.WithOption(dhcpv4.OptServerIdentifier(server.serverIP)),
+	}
+	if gateway := net.ParseIP(server.network.IPv4.Gateway); gateway != nil {
+		modifiers = append(modifiers, dhcpv4.WithRouter(gateway))
+	}
+	// Guests resolve through the sox resolver, which forwards to the nameservers
+	modifiers = append(modifiers, dhcpv4.WithDNS(server.serverIP))
+	if domains := strings.Fields(server.network.SearchDomains); len(domains) > 0 {
+		modifiers = append(modifiers, dhcpv4.WithOption(dhcpv4.OptDomainSearch(&rfc1035label.Labels{
+			Labels: domains,
+		})))
+	}
+	reply, err := dhcpv4.NewReplyFromRequest(request, modifiers...)
+	if err != nil {
+		log.Println("dhcpv4: build reply:", err)
+		return
+	}
+	if _, err := conn.WriteTo(reply.ToBytes(), peer); err != nil {
+		log.Println("dhcpv4: send reply:", err)
+		return
+	}
+	log.Println("dhcpv4: sent", replyType, "with", ip, "to", request.ClientHWAddr)
+}
+
+// clientHwAddr extracts the hardware address of a DHCPv6 client, either from
+// its link-layer DUID or from the EUI-64 of its link-local source address.
+func clientHwAddr(msg *dhcpv6.Message, peer net.Addr) net.HardwareAddr {
+	if duid := msg.Options.ClientID(); duid != nil && (duid.Type == dhcpv6.DUID_LL || duid.Type == dhcpv6.DUID_LLT) {
+		return duid.LinkLayerAddr
+	}
+	udpAddr, ok := peer.(*net.UDPAddr)
+	if !ok || !udpAddr.IP.IsLinkLocalUnicast() {
+		return nil
+	}
+	ip := udpAddr.IP.To16()
+	if ip[11] != 0xff || ip[12] != 0xfe {
+		return nil
+	}
+	return net.HardwareAddr{ip[8] ^ 0x02, ip[9], ip[10], ip[13], ip[14], ip[15]}
+}
+
+func (server *Server) handle6(conn net.PacketConn, peer net.Addr, request dhcpv6.DHCPv6) {
+	msg, err := request.GetInnerMessage()
+	if err != nil {
+		log.Println("dhcpv6: get inner message:", err)
+		return
+	}
+	hwAddr := clientHwAddr(msg, peer)
+	if hwAddr == nil {
+		log.Println("dhcpv6: unable to identify client", peer)
+		return
+	}
+	iface, err := server.lookup(hwAddr.String())
+	if err != nil {
+		log.Println("dhcpv6: no lease for", hwAddr, err)
+		return
+	}
+	modifiers := []dhcpv6.Modifier{
+		dhcpv6.WithServerID(server.duid),
+	}
+	// Like over IPv4, guests resolve through the sox resolver
+	if server.serverIPv6 != nil {
+		modifiers = append(modifiers, dhcpv6.WithDNS(server.serverIPv6))
+	}
+	if domains := strings.Fields(server.network.SearchDomains); len(domains) > 0 {
+		modifiers = append(modifiers, dhcpv6.WithDomainSearchList(domains...))
+	}
+	// Hand out the address only if the client asks for a non-temporary one
+	if iana := msg.Options.OneIANA(); iana != nil && iface.IPv6 != "" {
+		ip, _, err := net.ParseCIDR(iface.IPv6)
+		if err != nil {
+			log.Println("dhcpv6: parse lease address:", err)
+			return
+		}
+		modifiers = append(modifiers,
+			dhcpv6.WithIAID(iana.IaId),
+			dhcpv6.WithIANA(dhcpv6.OptIAAddress{
+				IPv6Addr:          ip,
+				PreferredLifetime: leaseTime,
+				ValidLifetime:     leaseTime,
+			}),
+		)
+	}
+	var reply *dhcpv6.Message
+	switch msg.Type() {
+	case dhcpv6.MessageTypeSolicit:
+		if msg.GetOneOption(dhcpv6.OptionRapidCommit) != nil {
+			reply, err = dhcpv6.NewReplyFromMessage(msg, modifiers...)
+		} else {
+			reply, err = dhcpv6.NewAdvertiseFromSolicit(msg, modifiers...)
+		}
+	case dhcpv6.MessageTypeRequest, dhcpv6.MessageTypeConfirm, dhcpv6.MessageTypeRenew,
+		dhcpv6.MessageTypeRebind, dhcpv6.MessageTypeInformationRequest:
+		reply, err = dhcpv6.NewReplyFromMessage(msg, modifiers...)
+	default:
+		return
+	}
+	if err != nil {
+		log.Println("dhcpv6: build reply:", err)
+		return
+	}
+	if _, err := conn.WriteTo(reply.ToBytes(), peer); err != nil {
+		log.Println("dhcpv6: send reply:", err)
+		return
+	}
+	log.Println("dhcpv6: sent", reply.Type(), "to", hwAddr)
+}
@@ -6,65 +6,209 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/dhcp"
 	"github.com/lnsp/sox/driver/libvirt"
 	"github.com/lnsp/sox/driver/models"
+	"github.com/lnsp/sox/driver/nat"
+	"github.com/lnsp/sox/driver/resolver"
+	"github.com/lnsp/sox/driver/vpn"
+	"github.com/lnsp/sox/events"
+	"github.com/lnsp/sox/webhooks"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+const (
+	// minIPv4MTU is the smallest datagram every IPv4 host must accept.
+	minIPv4MTU = 576
+	// minIPv6MTU is the minimum link MTU required by IPv6.
+	minIPv6MTU = 1280
+)
+
 type Driver struct {
 	api.UnimplementedSoxServer
 
-	db *gorm.DB
-	lv *libvirt.Libvirt
+	db  *gorm.DB
+	lv  *libvirt.Libvirt
+	nat *nat.Table
+	// vpn is nil unless a VPN range is configured.
+	vpn *vpn.Gateway
+
+	floatingIPPool string
+
+	// nodeName and nodeAddress identify the node in aggregation mode.
+	nodeName    string
+	nodeAddress string
+	nodeLabels  map[string]string
+	// migrationURI is the libvirt URI other nodes live migrate machines to.
+	migrationURI string
+
+	// authRequired rejects requests without token or client certificate.
+	authRequired bool
+	// dialOptions are used to connect to other nodes.
+	dialOptions []grpc.DialOption
+
+	// events are published to the watchers of the node.
+	events *events.Bus
+
+	// overcommit scales the host capacity available to machines.
+	overcommit Overcommit
+
+	// webhookQueued wakes up the delivery of webhooks once deliveries are queued.
+	webhookQueued  chan struct{}
+	webhookRetries WebhookRetries
+	webhookClient  *http.Client
+	// webhookAllowPrivate permits webhooks posting to loopback, link-local and private addresses.
+	webhookAllowPrivate bool
+
+	// dbErrors counts the failed database statements by operation.
+	dbErrors *prometheus.CounterVec
+
+	// usage holds the usage history of the machines by ID.
+	usageMu sync.Mutex
+	usage   map[string]*usageHistory
+
+	// quotaMu serializes quota and capacity checks with the creation of the resources they count.
+	quotaMu sync.Mutex
+	// natMu serializes changes of port forwards and floating IPs with the checks they depend on.
+	natMu sync.Mutex
+
+	servicesMu  sync.Mutex
+	dhcpServers map[string]*dhcp.Server
+	dnsServers  map[string][]*resolver.Server
+	zone        *resolver.Zone
+	// hostResolver serves the zone to the host, nil unless a DNS address is configured.
+	hostResolver *resolver.Server
 }
 
-func (driver *Driver) recordActivity(activityType api.Activity_Type, subject string) error {
-	if result := driver.db.Create(&models.Activity{
-		Type:    activityType.String(),
-		Subject: subject,
-	}); result.Error != nil {
-		return fmt.Errorf("record activity: %w", result.Error)
+// startDHCP launches the DHCP responder of a network if it is not running yet. On libvirt networks
+// it serves guests that do not apply the static network config. Networks without a host address are skipped.
+func (driver *Driver) startDHCP(network *models.Network) error {
+	if !network.HasHostAddress() {
+		return nil
+	}
+	driver.servicesMu.Lock()
+	defer driver.servicesMu.Unlock()
+	if _, ok := driver.dhcpServers[network.ID]; ok {
+		return nil
+	}
+	server, err := dhcp.New(network, func(hwAddr string) (*models.NetworkInterface, error) {
+		var iface models.NetworkInterface
+		if err := driver.db.Where("network_id = ? AND hw_addr = ?", network.ID, hwAddr).First(&iface).Error; err != nil {
+			return nil, fmt.Errorf("retrieve interface: %w", err)
+		}
+		return &iface, nil
+	})
+	if err != nil {
+		return err
+	}
+	driver.dhcpServers[network.ID] = server
+	return nil
+}
+
+// startResolver launches the DNS servers on the host addresses of the network if they are not running yet.
+// Networks without a host address are skipped.
+func (driver *Driver) startResolver(network *models.Network) error {
+	if !network.HasHostAddress() {
+		return nil
+	}
+	driver.servicesMu.Lock()
+	defer driver.servicesMu.Unlock()
+	if _, ok := driver.dnsServers[network.ID]; ok {
+		return nil
 	}
+	hostIP := network.HostIPv4()
+	if hostIP == nil {
+		return fmt.Errorf("network %s has no host address", network.Name)
+	}
+	hostIPs := []net.IP{hostIP}
+	if hostIPv6 := network.HostIPv6(); hostIPv6 != nil {
+		hostIPs = append(hostIPs, hostIPv6)
+	}
+	servers := make([]*resolver.Server, 0, len(hostIPs))
+	for _, ip := range hostIPs {
+		server, err := resolver.Listen(net.JoinHostPort(ip.String(), "53"), driver.zone, strings.Fields(network.Nameservers))
+		if err != nil {
+			for _, server := range servers {
+				server.Close()
+			}
+			return err
+		}
+		servers = append(servers, server)
+	}
+	driver.dnsServers[network.ID] = servers
 	return nil
 }
 
-func (driver *Driver) ListActivities(ctx context.Context, request *api.ListActivitiesRequest) (*api.ListActivitiesResponse, error) {
-	activities := []models.Activity{}
-	if result := driver.db.Find(&activities); result.Error != nil {
-		return nil, status.Errorf(codes.Internal, "retrieve activities: %v", result.Error)
+// closeServices stops the DHCP responder and resolvers of the network. The services lock must be held.
+func (driver *Driver) closeServices(id string) error {
+	var closeErr error
+	if server, ok := driver.dhcpServers[id]; ok {
+		if err := server.Close(); err != nil {
+			closeErr = fmt.Errorf("close dhcp server of network %s: %w", id, err)
+		}
+		delete(driver.dhcpServers, id)
 	}
-	apiActivities := make([]*api.Activity, len(activities))
-	for i := range activities {
-		apiActivities[i] = &api.Activity{
-			Type:      api.Activity_Type(api.Activity_Type_value[activities[i].Type]),
-			Timestamp: timestamppb.New(activities[i].CreatedAt),
-			Subject:   activities[i].Subject,
+	for _, server := range driver.dnsServers[id] {
+		if err := server.Close(); err != nil && closeErr == nil {
+			closeErr = fmt.Errorf("close resolver of network %s: %w", id, err)
 		}
 	}
-	return &api.ListActivitiesResponse{
-		Activities: apiActivities,
-	}, nil
+	delete(driver.dnsServers, id)
+	return closeErr
+}
+
+// Close stops the DHCP responders and resolvers of the node. Machines and networks keep running.
+func (driver *Driver) Close() error {
+	driver.servicesMu.Lock()
+	defer driver.servicesMu.Unlock()
+	var closeErr error
+	ids := make(map[string]bool)
+	for id := range driver.dhcpServers {
+		ids[id] = true
+	}
+	for id := range driver.dnsServers {
+		ids[id] = true
+	}
+	for id := range ids {
+		if err := driver.closeServices(id); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+	if driver.hostResolver != nil {
+		if err := driver.hostResolver.Close(); err != nil && closeErr == nil {
+			closeErr = fmt.Errorf("close host resolver: %w", err)
+		}
+		driver.hostResolver = nil
+	}
+	return closeErr
 }
 
 func (driver *Driver) ListSSHKeys(ctx context.Context, request *api.ListSSHKeysRequest) (*api.ListSSHKeysResponse, error) {
 	keys := []models.SSHKey{}
-	if result := driver.db.Find(&keys); result.Error != nil {
+	if result := scoped(ctx, driver.db).Find(&keys); result.Error != nil {
 		return nil, status.Errorf(codes.Internal, "retrieve ssh keys: %v", result.Error)
 	}
 	apiKeys := make([]*api.SSHKey, len(keys))
 	for i := range keys {
 		apiKeys[i] = &api.SSHKey{
-			Id:     keys[i].ID,
-			Name:   keys[i].Name,
-			Pubkey: keys[i].Pubkey,
+			Id:        keys[i].ID,
+			Name:      keys[i].Name,
+			Pubkey:    keys[i].Pubkey,
+			ProjectId: keys[i].ProjectID,
 		}
 	}
 	return &api.ListSSHKeysResponse{
@@ -73,16 +217,23 @@ func (driver *Driver) ListSSHKeys(ctx context.Context, request *api.ListSSHKeysR
 }
 
 func (driver *Driver) ListImages(ctx context.Context, request *api.ListImagesRequest) (*api.ListImagesResponse, error) {
+	// Public images are listed for all projects
+	query := driver.db
+	if projects, all := visibleProjects(ctx); !all {
+		query = query.Where("project_id IN ? OR public = ?", projects, true)
+	}
 	images := []models.Image{}
-	if result := driver.db.Find(&images); result.Error != nil {
+	if result := query.Find(&images); result.Error != nil {
 		return nil, status.Errorf(codes.Internal, "retrieve images: %v", result.Error)
 	}
 	apiImages := make([]*api.Image, len(images))
 	for i := range images {
 		apiImages[i] = &api.Image{
-			Id:     images[i].ID,
-			Name:   images[i].Name,
-			System: api.Image_OS(api.Image_OS_value[images[i].OS]),
+			Id:        images[i].ID,
+			Name:      images[i].Name,
+			System:    api.Image_OS(api.Image_OS_value[images[i].OS]),
+			ProjectId: images[i].ProjectID,
+			Public:    images[i].Public,
 		}
 	}
 	return &api.ListImagesResponse{
@@ -90,7 +241,9 @@ func (driver *Driver) ListImages(ctx context.Context, request *api.ListImagesReq
 	}, nil
 }
 
-func (driver *Driver) ConfigureNetworkInterface(ctx context.Context, network models.Network) (models.NetworkInterface, error) {
+// findFreeAddress returns the first address in subnet that is neither the base address,
+// the gateway nor contained in the list of used addresses.
+func findFreeAddress(subnet, gateway string, used []string) (string, error) {
 	inc := func(ipp net.IP) net.IP {
 		ip := make(net.IP, len(ipp))
 		copy(ip, ipp)
@@ -102,34 +255,25 @@ func (driver *Driver) ConfigureNetworkInterface(ctx context.Context, network mod
 		}
 		return ip
 	}
-	// Generate hw addr
-	random := make([]byte, 3)
-	if _, err := rand.Read(random); err != nil {
-		return models.NetworkInterface{}, fmt.Errorf("generate hwaddr: %v", err)
-	}
-	hwAddr := net.HardwareAddr(append([]byte{0x52, 0x54, 0x00}, random...)).String()
-	log.Println("generated hwaddr", hwAddr)
-	// Generate ip addr
-	var existingIfaces []models.NetworkInterface
-	if err := driver.db.Where("network_id = ?", network.ID).Find(&existingIfaces).Error; err != nil {
-		return models.NetworkInterface{}, fmt.Errorf("retrieve interfaces: %v", err)
-	}
 	// Find min mask value on ip addr
-	ip, ipnet, err := net.ParseCIDR(network.IPv4.Subnet)
+	ip, ipnet, err := net.ParseCIDR(subnet)
 	if err != nil {
-		return models.NetworkInterface{}, fmt.Errorf("parse subnet: %v", err)
+		return "", fmt.Errorf("parse subnet: %v", err)
 	}
 	// TODO(lnsp): Refine this algorithm
 	blocked := make(map[string]struct{})
-	// Block base ip and gateway from pool
+	// Block base ip, broadcast address and gateway from pool
 	blocked[ip.String()] = struct{}{}
-	blocked[network.IPv4.Gateway] = struct{}{}
+	if broadcast := broadcastAddress(ipnet); broadcast != nil {
+		blocked[broadcast.String()] = struct{}{}
+	}
+	blocked[gateway] = struct{}{}
 	// Block every iface from pool
-	for _, iface := range existingIfaces {
-		ip, _, _ := net.ParseCIDR(iface.IPv4)
+	for _, addr := range used {
+		ip, _, _ := net.ParseCIDR(addr)
 		blocked[ip.String()] = struct{}{}
 	}
-	log.Println("searching for ip in subnet", network.IPv4.Subnet, "and blocked IPs", blocked)
+	log.Println("searching for ip in subnet", subnet, "and blocked IPs", blocked)
 	// Find first IP that is contained in our ipnet and not blocked
 	for ipnet.Contains(ip) {
 		if _, ok := blocked[ip.String()]; !ok {
@@ -138,19 +282,82 @@ func (driver *Driver) ConfigureNetworkInterface(ctx context.Context, network mod
 		ip = inc(ip)
 	}
 	if !ipnet.Contains(ip) {
-		return models.NetworkInterface{}, fmt.Errorf("no capacity available: subnet %s is full", ipnet.String())
+		return "", fmt.Errorf("no capacity available: subnet %s is full", ipnet.String())
 	}
 	maskSize, _ := ipnet.Mask.Size()
-	ipv4 := fmt.Sprintf("%s/%d", ip, maskSize)
+	return fmt.Sprintf("%s/%d", ip, maskSize), nil
+}
+
+// broadcastAddress returns the broadcast address of an IPv4 subnet, nil for IPv6 subnets
+// and point-to-point subnets without broadcast.
+func broadcastAddress(ipnet *net.IPNet) net.IP {
+	ip := ipnet.IP.To4()
+	if ip == nil {
+		return nil
+	}
+	if ones, bits := ipnet.Mask.Size(); bits-ones < 2 {
+		return nil
+	}
+	mask := net.IP(ipnet.Mask).To4()
+	broadcast := make(net.IP, net.IPv4len)
+	for i := range ip {
+		broadcast[i] = ip[i] | ^mask[i]
+	}
+	return broadcast
+}
+
+func (driver *Driver) ConfigureNetworkInterface(ctx context.Context, network models.Network) (models.NetworkInterface, error) {
+	// Generate hw addr
+	random := make([]byte, 3)
+	if _, err := rand.Read(random); err != nil {
+		return models.NetworkInterface{}, fmt.Errorf("generate hwaddr: %v", err)
+	}
+	hwAddr := net.HardwareAddr(append([]byte{0x52, 0x54, 0x00}, random...)).String()
+	log.Println("generated hwaddr", hwAddr)
+	// Generate ip addr
+	var existingIfaces []models.NetworkInterface
+	if err := driver.db.Where("network_id = ?", network.ID).Find(&existingIfaces).Error; err != nil {
+		return models.NetworkInterface{}, fmt.Errorf("retrieve interfaces: %v", err)
+	}
+	usedV4 := make([]string, 0, len(existingIfaces))
+	usedV6 := make([]string, 0, len(existingIfaces))
+	for _, iface := range existingIfaces {
+		usedV4 = append(usedV4, iface.IPv4)
+		if iface.IPv6 != "" {
+			usedV6 = append(usedV6, iface.IPv6)
+		}
+	}
+	ipv4, err := findFreeAddress(network.IPv4.Subnet, network.IPv4.Gateway, usedV4)
+	if err != nil {
+		return models.NetworkInterface{}, err
+	}
 	log.Println("found available IPv4 address", ipv4)
+	// Only allocate IPv6 addresses on dual-stack networks
+	var ipv6 string
+	if network.IPv6.Subnet != "" {
+		ipv6, err = findFreeAddress(network.IPv6.Subnet, network.IPv6.Gateway, usedV6)
+		if err != nil {
+			return models.NetworkInterface{}, err
+		}
+		log.Println("found available IPv6 address", ipv6)
+	}
 	return models.NetworkInterface{
 		Network: network,
 		HwAddr:  hwAddr,
 		IPv4:    ipv4,
+		IPv6:    ipv6,
 	}, nil
 }
 
 func (driver *Driver) CreateMachine(ctx context.Context, request *api.CreateMachineRequest) (*api.CreateMachineResponse, error) {
+	if err := validateSpecs(request.Specs); err != nil {
+		return nil, err
+	}
+	if cordoned, err := driver.cordoned(); err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	} else if cordoned {
+		return nil, status.Errorf(codes.FailedPrecondition, "node %s is cordoned", driver.nodeName)
+	}
 	// Retrieve SSH keys
 	sshKeys := make([]models.SSHKey, len(request.SshKeyIds))
 	if len(request.SshKeyIds) < 1 {
@@ -182,6 +389,16 @@ func (driver *Driver) CreateMachine(ctx context.Context, request *api.CreateMach
 		}
 		ifaces[i] = iface
 	}
+	// Retrieve security groups, they apply to all interfaces
+	securityGroups := make([]models.SecurityGroup, len(request.SecurityGroupIds))
+	for i := range request.SecurityGroupIds {
+		if err := driver.db.Where("id = ?", request.SecurityGroupIds[i]).First(&securityGroups[i]).Error; err != nil {
+			return nil, status.Errorf(codes.NotFound, "retrieve security group: %v", err)
+		}
+	}
+	for i := range ifaces {
+		ifaces[i].SecurityGroups = securityGroups
+	}
 	// Create entry for machine in DB
 	specs := models.Specs{
 		CPUs:   request.Specs.Cpus,
@@ -191,29 +408,61 @@ func (driver *Driver) CreateMachine(ctx context.Context, request *api.CreateMach
 	machine := models.Machine{
 		ID:                uuid.New().String(),
 		Name:              request.Name,
+		ProjectID:         projectFrom(ctx),
 		User:              request.User,
 		Image:             image,
 		SSHKeys:           sshKeys,
 		Specs:             specs,
 		NetworkInterfaces: ifaces,
 	}
-	// Generate network interface
-	if err := driver.db.Create(&machine).Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "create machine record: %v", err)
+	requested := models.Quota{
+		CPUs:     specs.CPUs,
+		Memory:   specs.Memory,
+		Disk:     specs.Disk,
+		Machines: 1,
+	}
+	if err := driver.reserve(machine.ProjectID, requested, func(tx *gorm.DB) error {
+		if err := tx.Create(&machine).Error; err != nil {
+			return status.Errorf(codes.Internal, "create machine record: %v", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 	log.Println("created machine record", machine.ID)
-	if err := driver.lv.CreateMachine(&machine); err != nil {
-		return nil, status.Errorf(codes.Internal, "create machine instance: %v", err)
+	if err := driver.createInstance(&machine, len(securityGroups) > 0); err != nil {
+		// Drop the record, so its resources no longer count against quota and capacity
+		if err := driver.lv.DeleteMachine(&machine); err != nil {
+			log.Println("attempted to delete machine instance:", err)
+		}
+		if err := driver.removeMachineRecord(&machine); err != nil {
+			log.Println("attempted to remove machine record:", err)
+		}
+		return nil, status.Errorf(codes.Internal, "%v", err)
 	}
 	log.Println("created machine instance", machine.ID)
+	driver.zone.Add(&machine)
 	// Record activity
-	go driver.recordActivity(api.Activity_MACHINE_CREATED, machine.ID)
+	driver.recordActivity(ctx, api.Activity_MACHINE_CREATED, machine.ID)
 	// And return
 	return &api.CreateMachineResponse{
 		Id: machine.ID,
 	}, nil
 }
 
+// createInstance applies the security groups of the new machine and starts its instance.
+func (driver *Driver) createInstance(machine *models.Machine, securityGroups bool) error {
+	if securityGroups {
+		if err := driver.applySecurityGroups(); err != nil {
+			return fmt.Errorf("apply security groups: %w", err)
+		}
+	}
+	if err := driver.lv.CreateMachine(machine); err != nil {
+		return fmt.Errorf("create machine instance: %w", err)
+	}
+	return nil
+}
+
 func (driver *Driver) TriggerMachine(ctx context.Context, request *api.TriggerMachineRequest) (*api.TriggerMachineResponse, error) {
 	// Find target machine
 	var machine models.Machine
@@ -251,7 +500,7 @@ func (driver *Driver) TriggerMachine(ctx context.Context, request *api.TriggerMa
 		return nil, status.Errorf(codes.Internal, "get machine state after trigger: %v", err)
 	}
 	// Record activity
-	go driver.recordActivity(activityType, machine.ID)
+	driver.recordActivity(ctx, activityType, machine.ID)
 	// And return
 	return &api.TriggerMachineResponse{
 		Status: machineStateToApiStatus(state),
@@ -261,16 +510,22 @@ func (driver *Driver) TriggerMachine(ctx context.Context, request *api.TriggerMa
 func (driver *Driver) GetMachineDetails(ctx context.Context, request *api.GetMachineDetailsRequest) (*api.GetMachineDetailsResponse, error) {
 	// Use ID or name to find machine
 	var machine models.Machine
-	if err := driver.db.Preload("SSHKeys").Preload("NetworkInterfaces").Where("id = ? OR name = ?", request.Id, request.Id).First(&machine).Error; err != nil {
+	if err := driver.db.Preload("SSHKeys").Preload("NetworkInterfaces.SecurityGroups").Where("id = ? OR name = ?", request.Id, request.Id).First(&machine).Error; err != nil {
 		return nil, status.Errorf(codes.Internal, "retrieve machine: %v", err)
 	}
 	// TODO(lnsp): Retrieve status data from libvirt, maybe cache them
 	apiNetworkInterfaces := make([]*api.NetworkInterface, len(machine.NetworkInterfaces))
 	for i := range machine.NetworkInterfaces {
 		apiNetworkInterfaces[i] = &api.NetworkInterface{
-			NetworkId: machine.NetworkInterfaces[i].Network.ID,
-			IpV4:      machine.NetworkInterfaces[i].IPv4,
-			IpV6:      machine.NetworkInterfaces[i].IPv6,
+			NetworkId:     machine.NetworkInterfaces[i].NetworkID,
+			IpV4:          machine.NetworkInterfaces[i].IPv4,
+			IpV6:          machine.NetworkInterfaces[i].IPv6,
+			Qos:           bandwidthToApi(machine.NetworkInterfaces[i].QoS),
+			MacAddress:    machine.NetworkInterfaces[i].HwAddr,
+			PendingReboot: machine.NetworkInterfaces[i].PendingReboot,
+		}
+		for _, group := range machine.NetworkInterfaces[i].SecurityGroups {
+			apiNetworkInterfaces[i].SecurityGroupIds = append(apiNetworkInterfaces[i].SecurityGroupIds, group.ID)
 		}
 	}
 	// Generate list of key ids
@@ -294,9 +549,12 @@ func (driver *Driver) GetMachineDetails(ctx context.Context, request *api.GetMac
 				Memory: machine.Specs.Memory,
 				Disk:   machine.Specs.Disk,
 			},
-			ImageId:   machine.Image.ID,
+			ImageId:   machine.ImageID,
+			User:      machine.User,
+			Node:      driver.nodeName,
 			SshKeyIds: sshKeyIds,
 			Networks:  apiNetworkInterfaces,
+			ProjectId: machine.ProjectID,
 		},
 	}, nil
 }
@@ -318,7 +576,7 @@ func machineStateToApiStatus(state models.MachineState) api.Machine_Status {
 
 func (driver *Driver) ListMachines(ctx context.Context, request *api.ListMachinesRequest) (*api.ListMachinesResponse, error) {
 	machines := []models.Machine{}
-	if err := driver.db.Find(&machines).Error; err != nil {
+	if err := scoped(ctx, driver.db).Find(&machines).Error; err != nil {
 		return nil, status.Errorf(codes.Internal, "retrieve machines: %v", err)
 	}
 	apiMachines := make([]*api.Machine, len(machines))
@@ -338,7 +596,9 @@ func (driver *Driver) ListMachines(ctx context.Context, request *api.ListMachine
 				Memory: machines[i].Specs.Memory,
 				Disk:   machines[i].Specs.Disk,
 			},
-			ImageId: machines[i].Image.ID,
+			ImageId:   machines[i].ImageID,
+			Node:      driver.nodeName,
+			ProjectId: machines[i].ProjectID,
 		}
 	}
 	return &api.ListMachinesResponse{
@@ -349,25 +609,49 @@ func (driver *Driver) ListMachines(ctx context.Context, request *api.ListMachine
 func (driver *Driver) DeleteMachine(ctx context.Context, request *api.DeleteMachineRequest) (*api.DeleteMachineResponse, error) {
 	// Destroy machine instance
 	var machine models.Machine
-	if err := driver.db.Where("id = ?", request.Id, request.Id).First(&machine).Error; err != nil {
+	if err := driver.db.Preload("NetworkInterfaces").Where("id = ?", request.Id, request.Id).First(&machine).Error; err != nil {
 		return nil, status.Errorf(codes.NotFound, "retrieve machine: %v", err)
 	}
+	if err := driver.deletePortForwards(machine.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "delete port forwards: %v", err)
+	}
+	if err := driver.disassociateFloatingIPs(machine.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "disassociate floating ips: %v", err)
+	}
 	if err := driver.lv.DeleteMachine(&machine); err != nil {
 		return nil, status.Errorf(codes.Internal, "delete machine: %v", err)
 	}
-	// Delete machine record
-	if err := driver.db.Select("NetworkInterfaces").Delete(&machine).Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "delete machine record: %v", err)
+	if err := driver.removeMachineRecord(&machine); err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
 	}
 	// Record activity
-	go driver.recordActivity(api.Activity_MACHINE_DELETED, machine.ID)
+	driver.recordActivity(ctx, api.Activity_MACHINE_DELETED, machine.ID)
 	// And return
 	return &api.DeleteMachineResponse{}, nil
 }
 
+// removeMachineRecord deletes the machine and its interfaces once the instance is gone.
+func (driver *Driver) removeMachineRecord(machine *models.Machine) error {
+	// Drop security group memberships
+	for i := range machine.NetworkInterfaces {
+		if err := driver.db.Model(&machine.NetworkInterfaces[i]).Association("SecurityGroups").Clear(); err != nil {
+			return fmt.Errorf("detach security groups: %w", err)
+		}
+	}
+	// Delete machine record
+	if err := driver.db.Select("NetworkInterfaces").Delete(machine).Error; err != nil {
+		return fmt.Errorf("delete machine record: %w", err)
+	}
+	driver.zone.Remove(machine.ID)
+	if err := driver.applySecurityGroups(); err != nil {
+		log.Println("apply security groups:", err)
+	}
+	return nil
+}
+
 func (driver *Driver) ListNetworks(ctx context.Context, request *api.ListNetworksRequest) (*api.ListNetworksResponse, error) {
 	networks := []models.Network{}
-	if err := driver.db.Find(&networks).Error; err != nil {
+	if err := scoped(ctx, driver.db).Find(&networks).Error; err != nil {
 		return nil, status.Errorf(codes.Internal, "retrieve networks: %v", err)
 	}
 	apiNetworks := make([]*api.Network, len(networks))
@@ -383,6 +667,11 @@ func (driver *Driver) ListNetworks(ctx context.Context, request *api.ListNetwork
 				Subnet:  networks[i].IPv6.Subnet,
 				Gateway: networks[i].IPv6.Gateway,
 			},
+			DefaultQos:     bandwidthToApi(networks[i].DefaultQoS),
+			Mtu:            networks[i].MTU,
+			Mode:           api.Network_Mode(api.Network_Mode_value[networks[i].Mode]),
+			PhysicalDevice: networks[i].PhysicalDevice,
+			ProjectId:      networks[i].ProjectID,
 		}
 	}
 	return &api.ListNetworksResponse{
@@ -391,10 +680,43 @@ func (driver *Driver) ListNetworks(ctx context.Context, request *api.ListNetwork
 }
 
 func (driver *Driver) CreateNetwork(ctx context.Context, request *api.CreateNetworkRequest) (*api.CreateNetworkResponse, error) {
+	defaultQoS, err := bandwidthFromApi(request.DefaultQos)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid default qos: %v", err)
+	}
+	mode := request.Mode
+	if mode == api.Network_MODE_UNSPECIFIED {
+		mode = api.Network_NAT
+		if request.BridgeId != 0 {
+			mode = api.Network_VXLAN
+		}
+	}
+	switch {
+	case mode == api.Network_VXLAN && request.BridgeId == 0:
+		return nil, status.Errorf(codes.InvalidArgument, "vxlan networks require a bridge id")
+	case (mode == api.Network_NAT || mode == api.Network_ROUTED || mode == api.Network_BRIDGED) && request.BridgeId != 0:
+		return nil, status.Errorf(codes.InvalidArgument, "%s networks do not support a bridge id", mode)
+	case mode == api.Network_BRIDGED && request.PhysicalDevice == "":
+		return nil, status.Errorf(codes.InvalidArgument, "bridged networks require a physical device")
+	case mode != api.Network_BRIDGED && request.PhysicalDevice != "":
+		return nil, status.Errorf(codes.InvalidArgument, "only bridged networks support a physical device")
+	}
+	if mode == api.Network_BRIDGED {
+		// Bridged networks hand out access to the physical network of the host
+		if c := callerFrom(ctx); c != nil && !c.user.Admin {
+			return nil, status.Errorf(codes.PermissionDenied, "only admins can create bridged networks")
+		}
+		if err := driver.lv.CheckPhysicalDevice(request.PhysicalDevice); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid physical device: %v", err)
+		}
+	}
 	network := models.Network{
-		ID:       uuid.New().String(),
-		Name:     request.Name,
-		BridgeID: request.BridgeId,
+		ID:             uuid.New().String(),
+		Name:           request.Name,
+		ProjectID:      projectFrom(ctx),
+		BridgeID:       request.BridgeId,
+		Mode:           mode.String(),
+		PhysicalDevice: request.PhysicalDevice,
 		IPv4: models.NetworkSpec{
 			Subnet:  request.IpV4.Subnet,
 			Gateway: request.IpV4.Gateway,
@@ -403,19 +725,82 @@ func (driver *Driver) CreateNetwork(ctx context.Context, request *api.CreateNetw
 			Subnet:  request.IpV6.Subnet,
 			Gateway: request.IpV6.Gateway,
 		},
+		DefaultQoS: defaultQoS,
+		MTU:        request.Mtu,
 	}
-	if err := driver.db.Create(&network).Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "add network entry: %v", err)
+	maxMTU, err := driver.lv.MaxMTU(&network)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "determine max mtu: %v", err)
+	}
+	minMTU := uint32(minIPv4MTU)
+	if network.IPv6.Subnet != "" {
+		minMTU = minIPv6MTU
+	}
+	if network.MTU == 0 && network.IsBridge() {
+		if network.MTU, err = driver.lv.DefaultMTU(&network); err != nil {
+			return nil, status.Errorf(codes.Internal, "determine default mtu: %v", err)
+		}
+	} else if network.MTU != 0 && (network.MTU < minMTU || network.MTU > maxMTU) {
+		return nil, status.Errorf(codes.InvalidArgument, "mtu must be between %d and %d", minMTU, maxMTU)
+	}
+	if err := driver.reserve(network.ProjectID, models.Quota{Networks: 1}, func(tx *gorm.DB) error {
+		if err := tx.Create(&network).Error; err != nil {
+			return status.Errorf(codes.Internal, "add network entry: %v", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 	// Create network in libvirt
 	if err := driver.lv.CreateNetwork(&network); err != nil {
+		driver.abortNetwork(&network)
 		return nil, status.Errorf(codes.Internal, "create network: %v", err)
 	}
+	if err := driver.startDHCP(&network); err != nil {
+		driver.abortNetwork(&network)
+		return nil, status.Errorf(codes.Internal, "start dhcp: %v", err)
+	}
+	if err := driver.startResolver(&network); err != nil {
+		driver.abortNetwork(&network)
+		return nil, status.Errorf(codes.Internal, "start resolver: %v", err)
+	}
 	return &api.CreateNetworkResponse{
 		Id: network.ID,
 	}, nil
 }
 
+// abortNetwork stops the services and removes the instance and record of a network whose creation failed.
+// Bridges shared with other networks are kept.
+func (driver *Driver) abortNetwork(network *models.Network) {
+	driver.servicesMu.Lock()
+	if err := driver.closeServices(network.ID); err != nil {
+		log.Println("attempted to stop network services:", err)
+	}
+	driver.servicesMu.Unlock()
+	var shared int64
+	if network.IsBridge() {
+		query := driver.db.Model(&models.Network{}).Where("id <> ?", network.ID)
+		if network.Mode == models.NetworkModeBridged {
+			query = query.Where("mode = ? AND physical_device = ?", network.Mode, network.PhysicalDevice)
+		} else {
+			query = query.Where("bridge_id = ?", network.BridgeID)
+		}
+		if err := query.Count(&shared).Error; err != nil {
+			log.Println("attempted to check shared bridge:", err)
+			shared = 1
+		}
+	}
+	if shared == 0 {
+		if err := driver.lv.DeleteNetwork(network); err != nil {
+			log.Println("attempted to delete network instance:", err)
+		}
+	}
+	// Drop the record, so it no longer counts against the quota
+	if err := driver.db.Delete(network).Error; err != nil {
+		log.Println("attempted to remove network record:", err)
+	}
+}
+
 func (driver *Driver) Recover() error {
 	// Go through networks and re-create them
 	var networks []models.Network
@@ -426,39 +811,109 @@ func (driver *Driver) Recover() error {
 		if err := driver.lv.CreateNetwork(&networks[i]); err != nil {
 			return fmt.Errorf("create network: %w", err)
 		}
+		if err := driver.startDHCP(&networks[i]); err != nil {
+			return fmt.Errorf("start dhcp: %w", err)
+		}
+		// Other resolvers may still occupy the address, which should not block startup
+		if err := driver.startResolver(&networks[i]); err != nil {
+			log.Println("start resolver:", err)
+		}
+	}
+	// Register machine names with the resolver
+	var machines []models.Machine
+	if err := driver.db.Preload("NetworkInterfaces.Network").Find(&machines).Error; err != nil {
+		return fmt.Errorf("find machines: %w", err)
+	}
+	for i := range machines {
+		driver.zone.Add(&machines[i])
+	}
+	// Re-apply port forwards and floating IPs
+	if err := driver.nat.Flush(); err != nil {
+		return fmt.Errorf("flush nat table: %w", err)
+	}
+	if err := driver.restorePortForwards(); err != nil {
+		return fmt.Errorf("restore port forwards: %w", err)
+	}
+	if err := driver.restoreFloatingIPs(); err != nil {
+		return fmt.Errorf("restore floating ips: %w", err)
+	}
+	if err := driver.restoreVPNPeers(); err != nil {
+		return fmt.Errorf("restore vpn peers: %w", err)
+	}
+	// Bring security group filters up to date
+	if err := driver.applySecurityGroups(); err != nil {
+		return fmt.Errorf("apply security groups: %w", err)
 	}
 	// TODO(lnsp): Restore state of virtual machines
 	return nil
 }
 
 func initModels(db *gorm.DB) error {
-	if err := db.AutoMigrate(&models.NetworkInterface{}, &models.Machine{}, &models.Image{}, &models.SSHKey{}, &models.Network{}, &models.Activity{}); err != nil {
+	if err := db.AutoMigrate(&models.NetworkInterface{}, &models.Machine{}, &models.Image{}, &models.SSHKey{}, &models.Network{}, &models.Activity{}, &models.SecurityGroup{}, &models.SecurityGroupRule{}, &models.PortForward{}, &models.FloatingIP{}, &models.VPNPeer{}, &models.NodeState{}, &models.IdentityRevision{}, &models.User{}, &models.Project{}, &models.Membership{}, &models.Token{}, &models.Quota{}, &models.Webhook{}, &models.WebhookDelivery{}); err != nil {
 		return err
 	}
 
+	// make sure default project and its admin exist
+	db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Project{
+		ID:   models.DefaultProjectID,
+		Name: "default",
+	})
+	db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.User{
+		ID:    models.DefaultUserID,
+		Name:  "admin",
+		Admin: true,
+	})
+	db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Membership{
+		UserID:    models.DefaultUserID,
+		ProjectID: models.DefaultProjectID,
+		Role:      api.Role_ADMIN.String(),
+	})
+	db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.IdentityRevision{ID: 1})
+	// images created before projects existed were usable by everyone
+	if err := db.Model(&models.Image{}).Where("project_id = '' OR project_id IS NULL").Update("public", true).Error; err != nil {
+		return err
+	}
+	for _, model := range projectModels {
+		if err := db.Model(model).Where("project_id = '' OR project_id IS NULL").Update("project_id", models.DefaultProjectID).Error; err != nil {
+			return err
+		}
+	}
+
 	// make sure debian image exists
 	db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Image{
-		ID:   "6274bb3f-56c4-4a94-895b-8e0675f12368",
-		Name: "Debian 11",
-		OS:   api.Image_DEBIAN_BULLSEYE.String(),
-		Path: "/var/lib/libvirt/images/debian-11-generic-amd64.qcow2",
+		ID:        "6274bb3f-56c4-4a94-895b-8e0675f12368",
+		Name:      "Debian 11",
+		ProjectID: models.DefaultProjectID,
+		Public:    true,
+		OS:        api.Image_DEBIAN_BULLSEYE.String(),
+		Path:      "/var/lib/libvirt/images/debian-11-generic-amd64.qcow2",
 	})
 	// make sure ssh key exists
 	db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.SSHKey{
-		ID:     "f5e8f193-89b9-4557-b88d-f5dcb272577b",
-		Name:   "default",
-		Pubkey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDPbrxB59qJKQ7WvJTEt9O8Esidzp6uuIhiPiMiyaHSrxf52R4H9CBqPBO/pC1AprBpk7ujI9YyjBaU7feig0w8xIRI04Uy9vknPTRDcKYIswjJqYu6i7CffjFnWz7Qj9/U/lSvOYV6qpicnx+jVX5aCnupMu8Qtt3udFN4Dnx5nW1hLwaIkBmzblNuGRZY3iYRKlSOijGavYGmNqTB809jBIr7B0+REI1C03zQbLGjQrXybBx0YZ3t+v7Cc/IG0kqBn94m3Q8oJ1yk7MWdMKYGB6iodPGKSfJ0TmlXdDIqPwL1LiHJCu3mRJzw/62iVrwxYYPjqnknzEQ6H2OhrvDtPAB6KqgIJ1V/exxwWYFglF4UUBkZZO8yiMIRQt+0E3NOTaV0uHawfyGsGvAZcphNCyYe5jBdRjolwEhaZCmre398ndL+e5CkjCnHMoAOLFFqCTIMseax/j04pyqcfiO4nP0+OssoEa1XrKWUMyGS6VHuFFbbthXN+/PQDA1x8n18Jnrql7AJrD71XqTYMwCoDY7Be/m4N8xIAqQPyt3/uP3XpkOeFvlJhJJM/uw7OeHtZraB7+CFbmpCKczhsz2xGV/YMiocxigrvEgUXZRSZKDvfLA4KDxDaxPdhDySvLRM0ZNcfSPkpYVdnIYco9x/p2NXyLN7TU/5D4K7GWutUQ== default",
+		ID:        "f5e8f193-89b9-4557-b88d-f5dcb272577b",
+		Name:      "default",
+		ProjectID: models.DefaultProjectID,
+		Pubkey:    "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDPbrxB59qJKQ7WvJTEt9O8Esidzp6uuIhiPiMiyaHSrxf52R4H9CBqPBO/pC1AprBpk7ujI9YyjBaU7feig0w8xIRI04Uy9vknPTRDcKYIswjJqYu6i7CffjFnWz7Qj9/U/lSvOYV6qpicnx+jVX5aCnupMu8Qtt3udFN4Dnx5nW1hLwaIkBmzblNuGRZY3iYRKlSOijGavYGmNqTB809jBIr7B0+REI1C03zQbLGjQrXybBx0YZ3t+v7Cc/IG0kqBn94m3Q8oJ1yk7MWdMKYGB6iodPGKSfJ0TmlXdDIqPwL1LiHJCu3mRJzw/62iVrwxYYPjqnknzEQ6H2OhrvDtPAB6KqgIJ1V/exxwWYFglF4UUBkZZO8yiMIRQt+0E3NOTaV0uHawfyGsGvAZcphNCyYe5jBdRjolwEhaZCmre398ndL+e5CkjCnHMoAOLFFqCTIMseax/j04pyqcfiO4nP0+OssoEa1XrKWUMyGS6VHuFFbbthXN+/PQDA1x8n18Jnrql7AJrD71XqTYMwCoDY7Be/m4N8xIAqQPyt3/uP3XpkOeFvlJhJJM/uw7OeHtZraB7+CFbmpCKczhsz2xGV/YMiocxigrvEgUXZRSZKDvfLA4KDxDaxPdhDySvLRM0ZNcfSPkpYVdnIYco9x/p2NXyLN7TU/5D4K7GWutUQ== default",
 	})
 	// create default network
 	db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Network{
-		ID:   "eb7a6e41-da84-4db4-9cba-97509ddc8a58",
-		Name: "network",
+		ID:        "eb7a6e41-da84-4db4-9cba-97509ddc8a58",
+		Name:      "network",
+		ProjectID: models.DefaultProjectID,
 		IPv4: models.NetworkSpec{
 			Subnet:  "192.168.100.0/24",
 			Gateway: "192.168.100.1",
 		},
 		Nameservers: "1.1.1.1",
+		Mode:        models.NetworkModeNAT,
 	})
+	// networks created before modes existed are either VXLAN or NAT networks
+	if err := db.Model(&models.Network{}).Where("(mode = '' OR mode IS NULL) AND bridge_id != 0").Update("mode", models.NetworkModeVXLAN).Error; err != nil {
+		return err
+	}
+	if err := db.Model(&models.Network{}).Where("mode = '' OR mode IS NULL").Update("mode", models.NetworkModeNAT).Error; err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -467,6 +922,32 @@ type Config struct {
 	StoragePool         string
 	LibvirtURI          string
 	NetworkTransportDev string
+	DNSAddress          string
+	FloatingIPPool      string
+	FloatingIPDevice    string
+	VPNDevice           string
+	VPNRange            string
+	VPNEndpoint         string
+	VPNPort             int
+	VPNKeyPath          string
+	NodeName            string
+	NodeAddress         string
+	NodeLabels          map[string]string
+	MigrationURI        string
+	// AuthRequired rejects requests which only name their user in the metadata.
+	AuthRequired bool
+	// DialOptions carry the credentials used to connect to other nodes, defaults to insecure.
+	DialOptions []grpc.DialOption
+	// Overcommit ratios left zero default to those of DefaultOvercommit.
+	Overcommit Overcommit
+	// ActivityRetention is the age activities and finished webhook deliveries are deleted at,
+	// they are kept forever if zero.
+	ActivityRetention time.Duration
+	// WebhookRetries left zero default to those of DefaultWebhookRetries.
+	WebhookRetries WebhookRetries
+	// WebhookAllowPrivate permits webhooks posting to loopback, link-local and private addresses,
+	// which are refused by default so webhooks cannot reach services on the host or its networks.
+	WebhookAllowPrivate bool
 }
 
 func New(cfg *Config) (*Driver, error) {
@@ -477,16 +958,120 @@ func New(cfg *Config) (*Driver, error) {
 	if err := initModels(db); err != nil {
 		return nil, fmt.Errorf("init models: %w", err)
 	}
+	dbErrors := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sox_db_errors_total",
+		Help: "Number of failed database statements by operation.",
+	}, []string{"operation"})
+	if err := countDBErrors(db, dbErrors); err != nil {
+		return nil, fmt.Errorf("register db callbacks: %w", err)
+	}
 	lv, err := libvirt.New(cfg.LibvirtURI, cfg.StoragePool, cfg.NetworkTransportDev)
 	if err != nil {
 		return nil, fmt.Errorf("init libvirt: %w", err)
 	}
+	// Floating addresses are announced on the transport device unless configured otherwise
+	floatingIPDevice := cfg.FloatingIPDevice
+	if floatingIPDevice == "" {
+		floatingIPDevice = cfg.NetworkTransportDev
+	}
+	natTable, err := nat.New(floatingIPDevice)
+	if err != nil {
+		return nil, fmt.Errorf("init nat: %w", err)
+	}
+	lv.ReserveDevices(floatingIPDevice)
+	// The WireGuard gateway is optional
+	var vpnGateway *vpn.Gateway
+	if cfg.VPNRange != "" {
+		device, port, keyPath := cfg.VPNDevice, cfg.VPNPort, cfg.VPNKeyPath
+		if device == "" {
+			device = "sox-wg0"
+		}
+		if port == 0 {
+			port = 51820
+		}
+		if keyPath == "" {
+			keyPath = "wireguard.key"
+		}
+		if vpnGateway, err = vpn.New(device, cfg.VPNRange, cfg.VPNEndpoint, port, keyPath); err != nil {
+			return nil, fmt.Errorf("init vpn: %w", err)
+		}
+		lv.ReserveDevices(device)
+	}
+	nodeName := cfg.NodeName
+	if nodeName == "" {
+		if nodeName, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("determine node name: %w", err)
+		}
+	}
+	dialOptions := cfg.DialOptions
+	if dialOptions == nil {
+		dialOptions = []grpc.DialOption{grpc.WithInsecure()}
+	}
+	overcommit := cfg.Overcommit
+	if overcommit.CPUs <= 0 {
+		overcommit.CPUs = DefaultOvercommit.CPUs
+	}
+	if overcommit.Memory <= 0 {
+		overcommit.Memory = DefaultOvercommit.Memory
+	}
+	if overcommit.Disk <= 0 {
+		overcommit.Disk = DefaultOvercommit.Disk
+	}
+	webhookRetries := cfg.WebhookRetries
+	if webhookRetries.MaxAttempts <= 0 {
+		webhookRetries.MaxAttempts = DefaultWebhookRetries.MaxAttempts
+	}
+	if webhookRetries.Delay <= 0 {
+		webhookRetries.Delay = DefaultWebhookRetries.Delay
+	}
+	if webhookRetries.MaxDelay <= 0 {
+		webhookRetries.MaxDelay = DefaultWebhookRetries.MaxDelay
+	}
 	driver := &Driver{
-		db: db,
-		lv: lv,
+		nodeName:            nodeName,
+		nodeAddress:         cfg.NodeAddress,
+		nodeLabels:          cfg.NodeLabels,
+		migrationURI:        cfg.MigrationURI,
+		authRequired:        cfg.AuthRequired,
+		dialOptions:         dialOptions,
+		overcommit:          overcommit,
+		events:              events.NewBus(nodeName),
+		webhookQueued:       make(chan struct{}, 1),
+		webhookRetries:      webhookRetries,
+		webhookClient:       webhooks.NewClient(webhookTimeout, cfg.WebhookAllowPrivate),
+		webhookAllowPrivate: cfg.WebhookAllowPrivate,
+		dbErrors:            dbErrors,
+		usage:               make(map[string]*usageHistory),
+		db:                  db,
+		lv:                  lv,
+		nat:                 natTable,
+		vpn:                 vpnGateway,
+		floatingIPPool:      cfg.FloatingIPPool,
+		dhcpServers:         make(map[string]*dhcp.Server),
+		dnsServers:          make(map[string][]*resolver.Server),
+		zone:                resolver.NewZone(),
 	}
 	if err := driver.Recover(); err != nil {
 		return nil, fmt.Errorf("recover: %w", err)
 	}
+	if err := lv.WatchMachineStates(driver.publishMachineState); err != nil {
+		return nil, fmt.Errorf("watch machine states: %w", err)
+	}
+	if err := lv.WatchMachineBoots(driver.clearPendingReboot); err != nil {
+		return nil, fmt.Errorf("watch machine boots: %w", err)
+	}
+	go driver.queueWebhookDeliveries()
+	go driver.deliverWebhooks()
+	go driver.sampleUsage()
+	if cfg.ActivityRetention > 0 {
+		go driver.pruneActivities(cfg.ActivityRetention)
+	}
+	// Let operators resolve machine names from the host
+	if cfg.DNSAddress != "" {
+		if driver.hostResolver, err = resolver.Listen(cfg.DNSAddress, driver.zone, nil); err != nil {
+			driver.Close()
+			return nil, fmt.Errorf("start host resolver: %w", err)
+		}
+	}
 	return driver, nil
 }
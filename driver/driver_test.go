@@ -0,0 +1,35 @@
+//go:build cgo
+// +build cgo
+
+package driver
+
+import "testing"
+
+func TestFindFreeAddress(t *testing.T) {
+	tests := []struct {
+		subnet  string
+		gateway string
+		used    []string
+		want    string
+	}{
+		{"192.168.200.0/28", "", nil, "192.168.200.1/28"},
+		{"192.168.100.0/24", "192.168.100.1", []string{"192.168.100.2/24"}, "192.168.100.3/24"},
+		// The broadcast address is never handed out
+		{"192.168.200.0/30", "", []string{"192.168.200.1/32"}, "192.168.200.2/30"},
+		{"192.168.200.0/30", "", []string{"192.168.200.1/32", "192.168.200.2/32"}, ""},
+		{"fd00::/126", "fd00::1", nil, "fd00::2/126"},
+		{"fd00::/126", "fd00::1", []string{"fd00::2/126"}, "fd00::3/126"},
+	}
+	for _, test := range tests {
+		got, err := findFreeAddress(test.subnet, test.gateway, test.used)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("findFreeAddress(%s, %v) = %s, want subnet to be full", test.subnet, test.used, got)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("findFreeAddress(%s, %v) = %s, %v, want %s", test.subnet, test.used, got, err, test.want)
+		}
+	}
+}
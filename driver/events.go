@@ -0,0 +1,101 @@
+package driver
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"github.com/lnsp/sox/events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// publishActivity publishes the recorded activity and the creation or deletion of its subject.
+func (driver *Driver) publishActivity(activity *models.Activity) {
+	apiActivity := driver.activityToApi(activity)
+	driver.events.Publish(&api.Event{
+		Type:      api.Event_ACTIVITY_RECORDED,
+		Timestamp: apiActivity.Timestamp,
+		Kind:      activity.Kind,
+		Subject:   activity.Subject,
+		ProjectId: activity.ProjectID,
+		Activity:  apiActivity,
+	})
+	var change api.Event_Type
+	switch {
+	case strings.HasSuffix(activity.Type, "_CREATED"), strings.HasSuffix(activity.Type, "_ALLOCATED"):
+		change = api.Event_RESOURCE_CREATED
+	case strings.HasSuffix(activity.Type, "_DELETED"), strings.HasSuffix(activity.Type, "_RELEASED"):
+		change = api.Event_RESOURCE_DELETED
+	default:
+		return
+	}
+	driver.events.Publish(&api.Event{
+		Type:      change,
+		Timestamp: apiActivity.Timestamp,
+		Kind:      activity.Kind,
+		Subject:   activity.Subject,
+		ProjectId: activity.ProjectID,
+	})
+}
+
+// publishMachineState publishes the state reported by a libvirt lifecycle event.
+func (driver *Driver) publishMachineState(id string, state models.MachineState) {
+	var machine models.Machine
+	driver.db.Select("project_id").Where("id = ?", id).Limit(1).Find(&machine)
+	driver.events.Publish(&api.Event{
+		Type:          api.Event_MACHINE_STATE_CHANGED,
+		Kind:          "machine",
+		Subject:       id,
+		ProjectId:     machine.ProjectID,
+		MachineStatus: machineStateToApiStatus(state),
+	})
+}
+
+// WatchEvents streams the events of the resources visible to the caller until the request is canceled.
+func (driver *Driver) WatchEvents(request *api.WatchEventsRequest, stream api.Sox_WatchEventsServer) error {
+	ctx := stream.Context()
+	watcher, err := driver.events.Watch(request.ResumeToken)
+	if errors.Is(err, events.ErrExpired) {
+		return status.Errorf(codes.OutOfRange, "%v", err)
+	} else if err != nil {
+		return status.Errorf(codes.Internal, "watch events: %v", err)
+	}
+	defer driver.events.Stop(watcher)
+	types := make(map[api.Event_Type]bool)
+	for _, t := range request.Types {
+		types[t] = true
+	}
+	projects, all := visibleProjects(ctx)
+	visible := make(map[string]bool)
+	for _, project := range projects {
+		visible[project] = true
+	}
+	var actor string
+	if c := callerFrom(ctx); c != nil {
+		actor = c.user.Name
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.C:
+			if !ok {
+				return status.Errorf(codes.Aborted, "watch fell behind, resume it with the last token")
+			}
+			if len(types) > 0 && !types[event.Type] {
+				continue
+			}
+			if !all && !visible[event.ProjectId] && (event.Activity == nil || event.Activity.Actor != actor) {
+				continue
+			}
+			if err := stream.Send(&api.WatchEventsResponse{
+				Event:       event,
+				ResumeToken: event.Id,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
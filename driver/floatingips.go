@@ -0,0 +1,230 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// restoreFloatingIPs re-programs the mappings of all associated floating IPs.
+func (driver *Driver) restoreFloatingIPs() error {
+	var fips []models.FloatingIP
+	if err := driver.db.Preload("NetworkInterface.Network").Where("network_interface_id IS NOT NULL").Find(&fips).Error; err != nil {
+		return fmt.Errorf("find floating ips: %w", err)
+	}
+	for i := range fips {
+		if err := driver.nat.AddFloatingIP(&fips[i]); err != nil {
+			return fmt.Errorf("add floating ip %s: %w", fips[i].Address, err)
+		}
+	}
+	return nil
+}
+
+// disassociateFloatingIPs detaches all floating IPs from the interfaces of a machine.
+// The addresses stay allocated.
+func (driver *Driver) disassociateFloatingIPs(machineID string) error {
+	driver.natMu.Lock()
+	defer driver.natMu.Unlock()
+	var fips []models.FloatingIP
+	if err := driver.db.Preload("NetworkInterface.Network").
+		Where("network_interface_id IN (?)", driver.db.Model(&models.NetworkInterface{}).Select("id").Where("machine_id = ?", machineID)).
+		Find(&fips).Error; err != nil {
+		return fmt.Errorf("find floating ips: %w", err)
+	}
+	for i := range fips {
+		if err := driver.nat.RemoveFloatingIP(&fips[i]); err != nil {
+			return fmt.Errorf("remove floating ip %s: %w", fips[i].Address, err)
+		}
+		if err := driver.db.Model(&fips[i]).Update("network_interface_id", nil).Error; err != nil {
+			return fmt.Errorf("update floating ip record: %w", err)
+		}
+	}
+	return nil
+}
+
+func floatingIPToApi(fip *models.FloatingIP) *api.FloatingIP {
+	apiFip := &api.FloatingIP{
+		Id:        fip.ID,
+		Address:   fip.Address,
+		ProjectId: fip.ProjectID,
+	}
+	if fip.NetworkInterface != nil {
+		apiFip.MachineId = fip.NetworkInterface.MachineID
+		apiFip.NetworkId = fip.NetworkInterface.NetworkID
+	}
+	return apiFip
+}
+
+func (driver *Driver) AllocateFloatingIP(ctx context.Context, request *api.AllocateFloatingIPRequest) (*api.AllocateFloatingIPResponse, error) {
+	if driver.floatingIPPool == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "no floating ip pool configured")
+	}
+	_, pool, err := net.ParseCIDR(driver.floatingIPPool)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "parse floating ip pool: %v", err)
+	}
+	driver.natMu.Lock()
+	defer driver.natMu.Unlock()
+	var address string
+	if request.Address != "" {
+		ip := net.ParseIP(request.Address).To4()
+		if ip == nil || !pool.Contains(ip) {
+			return nil, status.Errorf(codes.InvalidArgument, "address %s is not part of the pool %s", request.Address, pool)
+		}
+		if broadcast := broadcastAddress(pool); broadcast != nil && (ip.Equal(pool.IP) || ip.Equal(broadcast)) {
+			return nil, status.Errorf(codes.InvalidArgument, "address %s is the network or broadcast address of the pool %s", request.Address, pool)
+		}
+		address = ip.String()
+	} else {
+		var existing []models.FloatingIP
+		if err := driver.db.Find(&existing).Error; err != nil {
+			return nil, status.Errorf(codes.Internal, "retrieve floating ips: %v", err)
+		}
+		used := make([]string, len(existing))
+		for i := range existing {
+			used[i] = existing[i].Address + "/32"
+		}
+		free, err := findFreeAddress(driver.floatingIPPool, "", used)
+		if err != nil {
+			return nil, status.Errorf(codes.ResourceExhausted, "find free address: %v", err)
+		}
+		ip, _, _ := net.ParseCIDR(free)
+		address = ip.String()
+	}
+	fip := models.FloatingIP{
+		ID:        uuid.New().String(),
+		Address:   address,
+		ProjectID: projectFrom(ctx),
+	}
+	if err := driver.reserve(fip.ProjectID, models.Quota{FloatingIPs: 1}, func(tx *gorm.DB) error {
+		if err := tx.Create(&fip).Error; err != nil {
+			return status.Errorf(codes.AlreadyExists, "create floating ip record: %v", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	log.Println("allocated floating ip", fip.Address)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_FLOATING_IP_ALLOCATED, fip.ID)
+	return &api.AllocateFloatingIPResponse{
+		FloatingIp: floatingIPToApi(&fip),
+	}, nil
+}
+
+func (driver *Driver) ListFloatingIPs(ctx context.Context, request *api.ListFloatingIPsRequest) (*api.ListFloatingIPsResponse, error) {
+	fips := []models.FloatingIP{}
+	if err := scoped(ctx, driver.db).Preload("NetworkInterface").Find(&fips).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve floating ips: %v", err)
+	}
+	apiFips := make([]*api.FloatingIP, len(fips))
+	for i := range fips {
+		apiFips[i] = floatingIPToApi(&fips[i])
+	}
+	return &api.ListFloatingIPsResponse{
+		FloatingIps: apiFips,
+	}, nil
+}
+
+func (driver *Driver) AssociateFloatingIP(ctx context.Context, request *api.AssociateFloatingIPRequest) (*api.AssociateFloatingIPResponse, error) {
+	// Concurrent moves of the same address would leave the rules of one of them behind
+	driver.natMu.Lock()
+	defer driver.natMu.Unlock()
+	var fip models.FloatingIP
+	if err := driver.db.Preload("NetworkInterface.Network").Where("id = ? OR address = ?", request.Id, request.Id).First(&fip).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve floating ip: %v", err)
+	}
+	// Find target interface, preferring NAT and routed networks
+	var target *models.NetworkInterface
+	if request.MachineId != "" {
+		var machine models.Machine
+		if err := driver.db.Preload("NetworkInterfaces.Network").Where("id = ? OR name = ?", request.MachineId, request.MachineId).First(&machine).Error; err != nil {
+			return nil, status.Errorf(codes.NotFound, "retrieve machine: %v", err)
+		}
+		for i := range machine.NetworkInterfaces {
+			iface := &machine.NetworkInterfaces[i]
+			if request.NetworkId != "" && request.NetworkId != iface.NetworkID {
+				continue
+			}
+			if target == nil || (!target.Network.IsForwarded() && iface.Network.IsForwarded()) {
+				target = iface
+			}
+		}
+		if target == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "machine has no interface on a matching network")
+		}
+	}
+	// Move the mapping, the guests are not touched
+	previous := fip
+	if fip.NetworkInterface != nil {
+		if err := driver.nat.RemoveFloatingIP(&fip); err != nil {
+			// Rules removed before the failure are added back
+			if err := driver.nat.AddFloatingIP(&fip); err != nil {
+				log.Println("restore floating ip", fip.Address+":", err)
+			}
+			return nil, status.Errorf(codes.Internal, "remove floating ip: %v", err)
+		}
+	}
+	fip.NetworkInterface = target
+	if target != nil {
+		fip.NetworkInterfaceID = &target.ID
+		if err := driver.nat.AddFloatingIP(&fip); err != nil {
+			driver.restoreFloatingIP(&fip, &previous)
+			return nil, status.Errorf(codes.Internal, "add floating ip: %v", err)
+		}
+	} else {
+		fip.NetworkInterfaceID = nil
+	}
+	if err := driver.db.Model(&fip).Update("network_interface_id", fip.NetworkInterfaceID).Error; err != nil {
+		driver.restoreFloatingIP(&fip, &previous)
+		return nil, status.Errorf(codes.Internal, "update floating ip record: %v", err)
+	}
+	log.Println("associated floating ip", fip.Address, "with machine", request.MachineId)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_FLOATING_IP_ASSOCIATED, fip.ID)
+	return &api.AssociateFloatingIPResponse{}, nil
+}
+
+// restoreFloatingIP moves the mapping of a floating IP back to the previous interface after a failed
+// association, so the rules match the unchanged record again.
+func (driver *Driver) restoreFloatingIP(fip, previous *models.FloatingIP) {
+	if fip.NetworkInterface != nil {
+		if err := driver.nat.RemoveFloatingIP(fip); err != nil {
+			log.Println("remove floating ip", fip.Address, "from new interface:", err)
+		}
+	}
+	if previous.NetworkInterface != nil {
+		if err := driver.nat.AddFloatingIP(previous); err != nil {
+			log.Println("restore floating ip", fip.Address, "on previous interface:", err)
+		}
+	}
+}
+
+func (driver *Driver) ReleaseFloatingIP(ctx context.Context, request *api.ReleaseFloatingIPRequest) (*api.ReleaseFloatingIPResponse, error) {
+	driver.natMu.Lock()
+	defer driver.natMu.Unlock()
+	var fip models.FloatingIP
+	if err := driver.db.Preload("NetworkInterface.Network").Where("id = ? OR address = ?", request.Id, request.Id).First(&fip).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve floating ip: %v", err)
+	}
+	if fip.NetworkInterface != nil {
+		if err := driver.nat.RemoveFloatingIP(&fip); err != nil {
+			return nil, status.Errorf(codes.Internal, "remove floating ip: %v", err)
+		}
+	}
+	if err := driver.db.Delete(&fip).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete floating ip record: %v", err)
+	}
+	log.Println("released floating ip", fip.Address)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_FLOATING_IP_RELEASED, fip.ID)
+	return &api.ReleaseFloatingIPResponse{}, nil
+}
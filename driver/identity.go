@@ -0,0 +1,209 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// identityMethods change users, projects, memberships, quotas or tokens, each successful call bumps the identity revision.
+var identityMethods = map[string]bool{
+	"CreateUser":       true,
+	"DeleteUser":       true,
+	"CreateProject":    true,
+	"DeleteProject":    true,
+	"SetMembership":    true,
+	"RemoveMembership": true,
+	"SetQuota":         true,
+	"CreateToken":      true,
+	"DeleteToken":      true,
+}
+
+func (driver *Driver) bumpIdentityRevision() {
+	if err := driver.db.Model(&models.IdentityRevision{}).Where("id = ?", 1).Update("revision", gorm.Expr("revision + 1")).Error; err != nil {
+		log.Println("bump identity revision:", err)
+	}
+}
+
+func (driver *Driver) ExportIdentity(ctx context.Context, request *api.ExportIdentityRequest) (*api.ExportIdentityResponse, error) {
+	var (
+		revision    models.IdentityRevision
+		users       []models.User
+		projects    []models.Project
+		memberships []models.Membership
+		quotas      []models.Quota
+		tokens      []models.Token
+	)
+	// The records are read in one transaction, so they match the revision
+	if err := driver.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", 1).First(&revision).Error; err != nil {
+			return status.Errorf(codes.Internal, "retrieve revision: %v", err)
+		}
+		if err := tx.Order("id").Find(&users).Error; err != nil {
+			return status.Errorf(codes.Internal, "retrieve users: %v", err)
+		}
+		if err := tx.Order("id").Find(&projects).Error; err != nil {
+			return status.Errorf(codes.Internal, "retrieve projects: %v", err)
+		}
+		if err := tx.Order("project_id, user_id").Find(&memberships).Error; err != nil {
+			return status.Errorf(codes.Internal, "retrieve memberships: %v", err)
+		}
+		if err := tx.Order("project_id").Find(&quotas).Error; err != nil {
+			return status.Errorf(codes.Internal, "retrieve quotas: %v", err)
+		}
+		if err := tx.Order("id").Find(&tokens).Error; err != nil {
+			return status.Errorf(codes.Internal, "retrieve tokens: %v", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	snapshot := &api.IdentitySnapshot{
+		Revision: revision.Revision,
+	}
+	for _, user := range users {
+		snapshot.Users = append(snapshot.Users, &api.User{Id: user.ID, Name: user.Name, Admin: user.Admin})
+	}
+	for _, project := range projects {
+		snapshot.Projects = append(snapshot.Projects, &api.Project{Id: project.ID, Name: project.Name})
+	}
+	for _, membership := range memberships {
+		snapshot.Memberships = append(snapshot.Memberships, &api.Membership{
+			UserId:    membership.UserID,
+			ProjectId: membership.ProjectID,
+			Role:      api.Role(api.Role_value[membership.Role]),
+		})
+	}
+	for i := range quotas {
+		snapshot.Quotas = append(snapshot.Quotas, &api.IdentitySnapshot_ProjectQuota{
+			ProjectId: quotas[i].ProjectID,
+			Quota:     quotaToApi(&quotas[i]),
+		})
+	}
+	for i := range tokens {
+		snapshot.Tokens = append(snapshot.Tokens, &api.IdentitySnapshot_TokenHash{
+			Token: tokenToApi(&tokens[i]),
+			Hash:  tokens[i].Hash,
+		})
+	}
+	return &api.ExportIdentityResponse{
+		Snapshot: snapshot,
+	}, nil
+}
+
+// errValidated rolls back imports which only validate the snapshot.
+var errValidated = errors.New("snapshot validated")
+
+func (driver *Driver) ImportIdentity(ctx context.Context, request *api.ImportIdentityRequest) (*api.ImportIdentityResponse, error) {
+	snapshot := request.GetSnapshot()
+	if snapshot == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "snapshot must be given")
+	}
+	keep := make(map[string]bool, len(snapshot.Projects))
+	for _, project := range snapshot.Projects {
+		keep[project.Id] = true
+	}
+	if err := driver.db.Transaction(func(tx *gorm.DB) error {
+		var revision models.IdentityRevision
+		if err := tx.Where("id = ?", 1).First(&revision).Error; err != nil {
+			return status.Errorf(codes.Internal, "retrieve revision: %v", err)
+		}
+		if revision.Revision > snapshot.Revision {
+			return status.Errorf(codes.FailedPrecondition, "snapshot revision %d is older than revision %d", snapshot.Revision, revision.Revision)
+		}
+		// Resources would be left without owner
+		var projects []models.Project
+		if err := tx.Find(&projects).Error; err != nil {
+			return status.Errorf(codes.Internal, "retrieve projects: %v", err)
+		}
+		for _, project := range projects {
+			if keep[project.ID] {
+				continue
+			}
+			for _, model := range projectModels {
+				var owned int64
+				if err := tx.Model(model).Where("project_id = ?", project.ID).Count(&owned).Error; err != nil {
+					return status.Errorf(codes.Internal, "count resources: %v", err)
+				}
+				if owned > 0 {
+					return status.Errorf(codes.FailedPrecondition, "project %s still owns resources", project.Name)
+				}
+			}
+		}
+		for _, model := range []interface{}{&models.Membership{}, &models.Quota{}, &models.Token{}, &models.User{}, &models.Project{}} {
+			if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(model).Error; err != nil {
+				return status.Errorf(codes.Internal, "delete records: %v", err)
+			}
+		}
+		for _, user := range snapshot.Users {
+			if err := tx.Create(&models.User{ID: user.Id, Name: user.Name, Admin: user.Admin}).Error; err != nil {
+				return status.Errorf(codes.InvalidArgument, "create user record: %v", err)
+			}
+		}
+		for _, project := range snapshot.Projects {
+			if err := tx.Create(&models.Project{ID: project.Id, Name: project.Name}).Error; err != nil {
+				return status.Errorf(codes.InvalidArgument, "create project record: %v", err)
+			}
+		}
+		for _, membership := range snapshot.Memberships {
+			if err := tx.Omit(clause.Associations).Create(&models.Membership{
+				UserID:    membership.UserId,
+				ProjectID: membership.ProjectId,
+				Role:      membership.Role.String(),
+			}).Error; err != nil {
+				return status.Errorf(codes.InvalidArgument, "create membership record: %v", err)
+			}
+		}
+		for _, quota := range snapshot.Quotas {
+			limits := quota.GetQuota()
+			if err := tx.Create(&models.Quota{
+				ProjectID:   quota.ProjectId,
+				CPUs:        limits.GetCpus(),
+				Memory:      limits.GetMemory(),
+				Disk:        limits.GetDisk(),
+				Machines:    limits.GetMachines(),
+				FloatingIPs: limits.GetFloatingIps(),
+				Networks:    limits.GetNetworks(),
+			}).Error; err != nil {
+				return status.Errorf(codes.InvalidArgument, "create quota record: %v", err)
+			}
+		}
+		for _, token := range snapshot.Tokens {
+			record := models.Token{
+				ID:        token.GetToken().GetId(),
+				Name:      token.GetToken().GetName(),
+				UserID:    token.GetToken().GetUserId(),
+				Hash:      token.Hash,
+				Scopes:    formatScopes(token.GetToken().GetScopes()),
+				CreatedAt: token.GetToken().GetCreatedAt().AsTime(),
+			}
+			if token.GetToken().GetExpiresAt() != nil {
+				expiresAt := token.Token.ExpiresAt.AsTime()
+				record.ExpiresAt = &expiresAt
+			}
+			if err := tx.Create(&record).Error; err != nil {
+				return status.Errorf(codes.InvalidArgument, "create token record: %v", err)
+			}
+		}
+		if err := tx.Model(&revision).Update("revision", snapshot.Revision).Error; err != nil {
+			return status.Errorf(codes.Internal, "update revision: %v", err)
+		}
+		if request.ValidateOnly {
+			return errValidated
+		}
+		return nil
+	}); errors.Is(err, errValidated) {
+		return &api.ImportIdentityResponse{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	log.Println("imported identity revision", snapshot.Revision)
+	return &api.ImportIdentityResponse{}, nil
+}
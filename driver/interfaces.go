@@ -0,0 +1,141 @@
+package driver
+
+import (
+	"context"
+	"log"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reloadMachine fetches the machine with all interfaces and their networks.
+func (driver *Driver) reloadMachine(id string) (*models.Machine, error) {
+	var machine models.Machine
+	if err := driver.db.Preload("SSHKeys").Preload("NetworkInterfaces.Network").Where("id = ? OR name = ?", id, id).First(&machine).Error; err != nil {
+		return nil, err
+	}
+	return &machine, nil
+}
+
+func (driver *Driver) AttachNetworkInterface(ctx context.Context, request *api.AttachNetworkInterfaceRequest) (*api.AttachNetworkInterfaceResponse, error) {
+	machine, err := driver.reloadMachine(request.MachineId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve machine: %v", err)
+	}
+	for _, iface := range machine.NetworkInterfaces {
+		if iface.NetworkID == request.NetworkId {
+			return nil, status.Errorf(codes.AlreadyExists, "machine is already attached to network %s", request.NetworkId)
+		}
+	}
+	var network models.Network
+	if err := driver.db.Where("id = ?", request.NetworkId).First(&network).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve network: %v", err)
+	}
+	securityGroups := make([]models.SecurityGroup, len(request.SecurityGroupIds))
+	for i := range request.SecurityGroupIds {
+		if err := driver.db.Where("id = ?", request.SecurityGroupIds[i]).First(&securityGroups[i]).Error; err != nil {
+			return nil, status.Errorf(codes.NotFound, "retrieve security group: %v", err)
+		}
+	}
+	iface, err := driver.ConfigureNetworkInterface(ctx, network)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "configure interface: %v", err)
+	}
+	iface.MachineID = machine.ID
+	iface.SecurityGroups = securityGroups
+	if err := driver.db.Create(&iface).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "create interface record: %v", err)
+	}
+	log.Println("created interface record", iface.ID, "for machine", machine.ID)
+	if len(securityGroups) > 0 {
+		if err := driver.applySecurityGroups(); err != nil {
+			return nil, status.Errorf(codes.Internal, "apply security groups: %v", err)
+		}
+	}
+	machine.NetworkInterfaces = append(machine.NetworkInterfaces, iface)
+	live, err := driver.lv.AttachNetworkInterface(machine, &iface)
+	if err != nil {
+		driver.db.Select("SecurityGroups").Delete(&iface)
+		return nil, status.Errorf(codes.Internal, "attach interface: %v", err)
+	}
+	// The guest only configures the interface on its next boot
+	if live {
+		iface.PendingReboot = true
+		if err := driver.db.Model(&iface).Update("pending_reboot", true).Error; err != nil {
+			log.Println("mark interface", iface.ID, "pending reboot:", err)
+		}
+	}
+	driver.zone.Add(machine)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_NETWORK_INTERFACE_ATTACHED, machine.ID)
+	securityGroupIds := make([]string, len(securityGroups))
+	for i := range securityGroups {
+		securityGroupIds[i] = securityGroups[i].ID
+	}
+	return &api.AttachNetworkInterfaceResponse{
+		NetworkInterface: &api.NetworkInterface{
+			NetworkId:        iface.NetworkID,
+			IpV4:             iface.IPv4,
+			IpV6:             iface.IPv6,
+			SecurityGroupIds: securityGroupIds,
+			PendingReboot:    iface.PendingReboot,
+		},
+	}, nil
+}
+
+// clearPendingReboot marks the interfaces of a booted machine as configured.
+func (driver *Driver) clearPendingReboot(id string) {
+	if err := driver.db.Model(&models.NetworkInterface{}).Where("machine_id = ? AND pending_reboot", id).Update("pending_reboot", false).Error; err != nil {
+		log.Println("clear pending reboot of machine", id+":", err)
+	}
+}
+
+func (driver *Driver) DetachNetworkInterface(ctx context.Context, request *api.DetachNetworkInterfaceRequest) (*api.DetachNetworkInterfaceResponse, error) {
+	machine, err := driver.reloadMachine(request.MachineId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve machine: %v", err)
+	}
+	var iface models.NetworkInterface
+	remaining := make([]models.NetworkInterface, 0, len(machine.NetworkInterfaces))
+	for i := range machine.NetworkInterfaces {
+		if machine.NetworkInterfaces[i].NetworkID == request.NetworkId {
+			iface = machine.NetworkInterfaces[i]
+		} else {
+			remaining = append(remaining, machine.NetworkInterfaces[i])
+		}
+	}
+	if iface.ID == 0 {
+		return nil, status.Errorf(codes.NotFound, "machine is not attached to network %s", request.NetworkId)
+	}
+	// Port forwards and floating IPs have to be moved away first
+	var forwards, fips int64
+	if err := driver.db.Model(&models.PortForward{}).Where("network_interface_id = ?", iface.ID).Count(&forwards).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "count port forwards: %v", err)
+	}
+	if err := driver.db.Model(&models.FloatingIP{}).Where("network_interface_id = ?", iface.ID).Count(&fips).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "count floating ips: %v", err)
+	}
+	if forwards > 0 || fips > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "interface is used by %d port forwards and %d floating ips", forwards, fips)
+	}
+	machine.NetworkInterfaces = remaining
+	if err := driver.lv.DetachNetworkInterface(machine, &iface); err != nil {
+		return nil, status.Errorf(codes.Internal, "detach interface: %v", err)
+	}
+	if err := driver.db.Model(&iface).Association("SecurityGroups").Clear(); err != nil {
+		return nil, status.Errorf(codes.Internal, "detach security groups: %v", err)
+	}
+	if err := driver.db.Delete(&iface).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete interface record: %v", err)
+	}
+	log.Println("deleted interface record", iface.ID, "of machine", machine.ID)
+	driver.zone.Add(machine)
+	if err := driver.applySecurityGroups(); err != nil {
+		log.Println("apply security groups:", err)
+	}
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_NETWORK_INTERFACE_DETACHED, machine.ID)
+	return &api.DetachNetworkInterfaceResponse{}, nil
+}
@@ -3,6 +3,7 @@ package libvirt
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
@@ -10,14 +11,14 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
-
-	"text/template"
+	"time"
 
 	"github.com/libvirt/libvirt-go"
 	libvirtxml "github.com/libvirt/libvirt-go-xml"
 	"github.com/lnsp/sox/driver/cloudconfig"
 	"github.com/lnsp/sox/driver/models"
 	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
 	"gopkg.in/yaml.v2"
 )
 
@@ -26,9 +27,22 @@ type Libvirt struct {
 	storagePool      *libvirt.StoragePool
 	storagePath      string
 	transportNetwork string
+	// reservedDevices are used by sox itself and can not be bridged to networks.
+	reservedDevices map[string]bool
 }
 
 func New(uri, storagePath, transportNetwork string) (*Libvirt, error) {
+	// Domain events are only delivered by an event loop registered before connecting
+	if err := libvirt.EventRegisterDefaultImpl(); err != nil {
+		return nil, fmt.Errorf("register event loop: %w", err)
+	}
+	go func() {
+		for {
+			if err := libvirt.EventRunDefaultImpl(); err != nil {
+				log.Println("run libvirt event loop:", err)
+			}
+		}
+	}()
 	conn, err := libvirt.NewConnect(uri)
 	if err != nil {
 		return nil, fmt.Errorf("connect to libvirt: %w", err)
@@ -53,52 +67,42 @@ func New(uri, storagePath, transportNetwork string) (*Libvirt, error) {
 		storagePool:      storagePool,
 		storagePath:      storagePath,
 		transportNetwork: transportNetwork,
+		reservedDevices:  map[string]bool{transportNetwork: true},
 	}, nil
 }
 
-var networkIfaceTemplate = template.Must(template.New("network").Parse(`
-auto {{ .Name }}
-iface {{ .Name }} inet static
-	address {{ .AddressCIDR }}
-	{{ if .Gateway }}gateway {{ .Gateway }}{{ end }}
-	{{ if .Nameservers }}dns-nameservers{{ range .Nameservers }} {{ . }}{{ end }}{{ end }}
-`))
+// ReserveDevices keeps the given devices from being bridged to networks.
+func (lv *Libvirt) ReserveDevices(devices ...string) {
+	for _, device := range devices {
+		lv.reservedDevices[device] = true
+	}
+}
 
-func configureImageNetworkInterface(machine *models.Machine, image string) error {
-	// Create single tempdir
-	netdir, err := os.MkdirTemp("", machine.ID)
+// CheckPhysicalDevice reports whether the device can be bridged to a network. Loopback and
+// reserved devices are refused, as are devices with host addresses, which would be cut off.
+func (lv *Libvirt) CheckPhysicalDevice(name string) error {
+	device, err := netlink.LinkByName(name)
 	if err != nil {
-		return fmt.Errorf("create netcfg dir: %w", err)
+		return fmt.Errorf("find physical device: %w", err)
 	}
-	// Create single tempfile
-	netcfg, err := os.Create(filepath.Join(netdir, "10-netcfg"))
+	return lv.checkPhysicalDevice(device)
+}
+
+func (lv *Libvirt) checkPhysicalDevice(device netlink.Link) error {
+	name := device.Attrs().Name
+	if device.Attrs().Flags&net.FlagLoopback != 0 || lv.reservedDevices[name] {
+		return fmt.Errorf("device %s is used by the host", name)
+	}
+	addrs, err := netlink.AddrList(device, netlink.FAMILY_ALL)
 	if err != nil {
-		return fmt.Errorf("create netcfg file: %w", err)
+		return fmt.Errorf("list addresses of %s: %w", name, err)
+	}
+	for _, addr := range addrs {
+		// Link-local addresses are configured on every device
+		if !addr.IP.IsLinkLocalUnicast() {
+			return fmt.Errorf("device %s has address %s", name, addr.IP)
+		}
 	}
-	defer netcfg.Close()
-	// Write configuration to netcfg
-	for i, iface := range machine.NetworkInterfaces {
-		if err := networkIfaceTemplate.Execute(netcfg, struct {
-			Name        string
-			AddressCIDR string
-			Nameservers []string
-			Gateway     string
-		}{
-			Name:        fmt.Sprintf("enp%ds0", i+1),
-			Nameservers: strings.Fields(iface.Network.Nameservers),
-			AddressCIDR: iface.IPv4,
-			Gateway:     iface.Network.IPv4.Gateway,
-		}); err != nil {
-			return fmt.Errorf("write netcfg item: %w", err)
-		}
-	}
-	// Use virt-customize to push config file into /etc/network/interfaces.d
-	virtCustomizeCmd := exec.Command("virt-customize", "-a", image, "--copy-in", netcfg.Name()+":/etc/network/interfaces.d")
-	virtCustomizeCmd.Stderr = log.Writer()
-	if err := virtCustomizeCmd.Run(); err != nil {
-		return fmt.Errorf("copy netcfg to vm: %w", err)
-	}
-	log.Println("configured image network", netcfg.Name())
 	return nil
 }
 
@@ -134,6 +138,13 @@ func writeCloudConfig(machine *models.Machine) (string, error) {
 			List:   []string{"debian:debian"},
 			Expire: false,
 		},
+		// Re-apply the network config on every boot, so interfaces attached or detached while the machine
+		// runs are configured after a reboot. The NoCloud datasource does not support hotplug events.
+		Updates: &cloudconfig.Updates{
+			Network: cloudconfig.UpdatesNetwork{
+				When: []string{"boot"},
+			},
+		},
 	}
 	content, err := yaml.Marshal(cc)
 	if err != nil {
@@ -151,29 +162,97 @@ func writeCloudConfig(machine *models.Machine) (string, error) {
 	return ccTempFile.Name(), nil
 }
 
+// buildInterfaceXml generates the virtio device of a network interface.
+func buildInterfaceXml(iface *models.NetworkInterface) libvirtxml.DomainInterface {
+	source := &libvirtxml.DomainInterfaceSource{}
+	if iface.Network.IsBridge() {
+		source.Bridge = &libvirtxml.DomainInterfaceSourceBridge{
+			Bridge: iface.Network.NetlinkBridge(),
+		}
+	} else {
+		source.Network = &libvirtxml.DomainInterfaceSourceNetwork{
+			Network: iface.Network.ID,
+		}
+	}
+	var mtu *libvirtxml.DomainInterfaceMTU
+	if iface.Network.MTU > 0 {
+		mtu = &libvirtxml.DomainInterfaceMTU{
+			Size: uint(iface.Network.MTU),
+		}
+	}
+	return libvirtxml.DomainInterface{
+		Source:    source,
+		MTU:       mtu,
+		Bandwidth: buildBandwidthXml(iface.EffectiveQoS()),
+		MAC: &libvirtxml.DomainInterfaceMAC{
+			Address: iface.HwAddr,
+		},
+		Model: &libvirtxml.DomainInterfaceModel{
+			Type: "virtio",
+		},
+		FilterRef: &libvirtxml.DomainInterfaceFilterRef{
+			Filter: InterfaceFilter(iface),
+		},
+	}
+}
+
+// buildBandwidthXml generates the traffic shaping settings of an interface.
+func buildBandwidthXml(qos models.Bandwidth) *libvirtxml.DomainInterfaceBandwidth {
+	if !qos.Inbound.IsSet() && !qos.Outbound.IsSet() {
+		return nil
+	}
+	return &libvirtxml.DomainInterfaceBandwidth{
+		Inbound:  buildBandwidthParamsXml(qos.Inbound),
+		Outbound: buildBandwidthParamsXml(qos.Outbound),
+	}
+}
+
+func buildBandwidthParamsXml(limit models.BandwidthLimit) *libvirtxml.DomainInterfaceBandwidthParams {
+	if !limit.IsSet() {
+		return nil
+	}
+	params := &libvirtxml.DomainInterfaceBandwidthParams{}
+	average := int(limit.Average)
+	params.Average = &average
+	if limit.Peak > 0 {
+		peak := int(limit.Peak)
+		params.Peak = &peak
+	}
+	if limit.Burst > 0 {
+		burst := int(limit.Burst)
+		params.Burst = &burst
+	}
+	return params
+}
+
+// buildConfigDiskXml generates the cdrom device carrying the NoCloud seed.
+func buildConfigDiskXml(configImage string) libvirtxml.DomainDisk {
+	disk := libvirtxml.DomainDisk{
+		Device: "cdrom",
+		Driver: &libvirtxml.DomainDiskDriver{
+			Name: "qemu",
+			Type: "raw",
+		},
+		Target: &libvirtxml.DomainDiskTarget{
+			Dev: "sda",
+			Bus: "sata",
+		},
+	}
+	if configImage != "" {
+		disk.Source = &libvirtxml.DomainDiskSource{
+			File: &libvirtxml.DomainDiskSourceFile{
+				File: configImage,
+			},
+		}
+	}
+	return disk
+}
+
 func buildDomXml(id string, specs models.Specs, configImage, osImage string, ifaces []models.NetworkInterface) string {
 	// Generate network interface list
 	lvIfaces := make([]libvirtxml.DomainInterface, len(ifaces))
 	for i := range ifaces {
-		source := &libvirtxml.DomainInterfaceSource{}
-		if ifaces[i].Network.IsBridge() {
-			source.Bridge = &libvirtxml.DomainInterfaceSourceBridge{
-				Bridge: ifaces[i].Network.NetlinkBridge(),
-			}
-		} else {
-			source.Network = &libvirtxml.DomainInterfaceSourceNetwork{
-				Network: ifaces[i].Network.ID,
-			}
-		}
-		lvIfaces[i] = libvirtxml.DomainInterface{
-			Source: source,
-			MAC: &libvirtxml.DomainInterfaceMAC{
-				Address: ifaces[i].HwAddr,
-			},
-			Model: &libvirtxml.DomainInterfaceModel{
-				Type: "virtio",
-			},
-		}
+		lvIfaces[i] = buildInterfaceXml(&ifaces[i])
 	}
 	// Define domain
 	dom := &libvirtxml.Domain{
@@ -218,22 +297,7 @@ func buildDomXml(id string, specs models.Specs, configImage, osImage string, ifa
 		Devices: &libvirtxml.DomainDeviceList{
 			Emulator: "/usr/bin/qemu-system-x86_64",
 			Disks: []libvirtxml.DomainDisk{
-				{
-					Device: "cdrom",
-					Driver: &libvirtxml.DomainDiskDriver{
-						Name: "qemu",
-						Type: "raw",
-					},
-					Source: &libvirtxml.DomainDiskSource{
-						File: &libvirtxml.DomainDiskSourceFile{
-							File: configImage,
-						},
-					},
-					Target: &libvirtxml.DomainDiskTarget{
-						Dev: "sda",
-						Bus: "sata",
-					},
-				},
+				buildConfigDiskXml(configImage),
 				{
 					Device: "disk",
 					Driver: &libvirtxml.DomainDiskDriver{
@@ -289,6 +353,48 @@ func buildDomXml(id string, specs models.Specs, configImage, osImage string, ifa
 	return xml
 }
 
+const (
+	// vxlanOverhead is the encapsulation overhead of VXLAN over IPv4.
+	vxlanOverhead = 50
+	// maxNATMTU is the largest MTU of the guest facing bridge of NAT networks.
+	maxNATMTU = 65535
+	// defaultMTU is used when no MTU has been requested for a network.
+	defaultMTU = 1500
+)
+
+// MaxMTU returns the largest MTU the network can carry. VXLAN networks are limited
+// by the MTU of the transport device minus the VXLAN encapsulation, networks bridged
+// to a physical device by the MTU of the device.
+func (lv *Libvirt) MaxMTU(network *models.Network) (uint32, error) {
+	if network.Mode == models.NetworkModeBridged {
+		device, err := netlink.LinkByName(network.PhysicalDevice)
+		if err != nil {
+			return 0, fmt.Errorf("find physical device: %w", err)
+		}
+		return uint32(device.Attrs().MTU), nil
+	}
+	if !network.IsBridge() {
+		return maxNATMTU, nil
+	}
+	transport, err := netlink.LinkByName(lv.transportNetwork)
+	if err != nil {
+		return 0, fmt.Errorf("find vxlan transport: %w", err)
+	}
+	return uint32(transport.Attrs().MTU - vxlanOverhead), nil
+}
+
+// DefaultMTU returns the MTU used for networks without an explicit MTU.
+func (lv *Libvirt) DefaultMTU(network *models.Network) (uint32, error) {
+	maxMTU, err := lv.MaxMTU(network)
+	if err != nil {
+		return 0, err
+	}
+	if maxMTU < defaultMTU {
+		return maxMTU, nil
+	}
+	return defaultMTU, nil
+}
+
 func (lv *Libvirt) createVxlanBridge(network *models.Network) (netlink.Link, error) {
 	// Ensure that bridge exists
 	bridge, err := netlink.LinkByName(network.NetlinkBridge())
@@ -303,10 +409,17 @@ func (lv *Libvirt) createVxlanBridge(network *models.Network) (netlink.Link, err
 	if !strings.Contains(err.Error(), "Link not found") {
 		return nil, fmt.Errorf("get bridge: %w", err)
 	}
+	// Bridge and VXLAN device share the MTU of the network
+	mtu := network.MTU
+	if mtu == 0 {
+		if mtu, err = lv.DefaultMTU(network); err != nil {
+			return nil, err
+		}
+	}
 	// Create *netlink.Bridge object.
 	la := netlink.NewLinkAttrs()
 	la.Name = network.NetlinkBridge()
-	la.MTU = 1500
+	la.MTU = int(mtu)
 	bridge = &netlink.Bridge{LinkAttrs: la}
 	if err := netlink.LinkAdd(bridge); err != nil {
 		return nil, fmt.Errorf("add bridge: %w", err)
@@ -316,16 +429,32 @@ func (lv *Libvirt) createVxlanBridge(network *models.Network) (netlink.Link, err
 	if err != nil {
 		return nil, fmt.Errorf("find bridge: %w", err)
 	}
-	// Setup ip address for bridge.
-	_, ipnetv4, err := net.ParseCIDR(network.IPv4.Subnet)
-	if err != nil {
-		return nil, fmt.Errorf("parse subnet CIDR: %w", err)
-	}
-	addrv4 := &netlink.Addr{
-		IPNet: ipnetv4,
-	}
-	if err := netlink.AddrAdd(bridge, addrv4); err != nil {
-		return nil, fmt.Errorf("add bridge addr: %w", err)
+	// Setup ip address for bridge, isolated networks stay unreachable from the host.
+	if network.HasHostAddress() {
+		_, ipnetv4, err := net.ParseCIDR(network.IPv4.Subnet)
+		if err != nil {
+			return nil, fmt.Errorf("parse subnet CIDR: %w", err)
+		}
+		addrv4 := &netlink.Addr{
+			IPNet: ipnetv4,
+		}
+		if err := netlink.AddrAdd(bridge, addrv4); err != nil {
+			return nil, fmt.Errorf("add bridge addr: %w", err)
+		}
+		if network.HostIPv6() != nil {
+			_, ipnetv6, err := net.ParseCIDR(network.IPv6.Subnet)
+			if err != nil {
+				return nil, fmt.Errorf("parse ipv6 subnet CIDR: %w", err)
+			}
+			// Skip duplicate address detection, so the resolver can listen right away
+			addrv6 := &netlink.Addr{
+				IPNet: ipnetv6,
+				Flags: unix.IFA_F_NODAD,
+			}
+			if err := netlink.AddrAdd(bridge, addrv6); err != nil {
+				return nil, fmt.Errorf("add bridge ipv6 addr: %w", err)
+			}
+		}
 	}
 	// Find transport network device
 	transport, err := netlink.LinkByName(lv.transportNetwork)
@@ -335,7 +464,7 @@ func (lv *Libvirt) createVxlanBridge(network *models.Network) (netlink.Link, err
 	// Add vxlan device
 	vxlanAttr := netlink.NewLinkAttrs()
 	vxlanAttr.Name = network.NetlinkVxlan()
-	vxlanAttr.MTU = 1450
+	vxlanAttr.MTU = int(mtu)
 	vxlanAttr.MasterIndex = bridge.Attrs().Index
 	vxlanLink := &netlink.Vxlan{
 		LinkAttrs:    vxlanAttr,
@@ -356,7 +485,49 @@ func (lv *Libvirt) createVxlanBridge(network *models.Network) (netlink.Link, err
 	return bridge, nil
 }
 
-func (lv *Libvirt) createNATNetwork(network *models.Network) (*libvirt.Network, error) {
+// createPhysicalBridge enslaves the physical device of the network to a new bridge.
+// Neither the bridge nor the device get a host address.
+func (lv *Libvirt) createPhysicalBridge(network *models.Network) (netlink.Link, error) {
+	device, err := netlink.LinkByName(network.PhysicalDevice)
+	if err != nil {
+		return nil, fmt.Errorf("find physical device: %w", err)
+	}
+	if err := lv.checkPhysicalDevice(device); err != nil {
+		return nil, err
+	}
+	bridge, err := netlink.LinkByName(network.NetlinkBridge())
+	if _, ok := err.(netlink.LinkNotFoundError); ok {
+		la := netlink.NewLinkAttrs()
+		la.Name = network.NetlinkBridge()
+		la.MTU = device.Attrs().MTU
+		if network.MTU > 0 {
+			la.MTU = int(network.MTU)
+		}
+		if err := netlink.LinkAdd(&netlink.Bridge{LinkAttrs: la}); err != nil {
+			return nil, fmt.Errorf("add bridge: %w", err)
+		}
+		bridge, err = netlink.LinkByName(network.NetlinkBridge())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find bridge: %w", err)
+	}
+	if device.Attrs().MasterIndex != bridge.Attrs().Index {
+		if err := netlink.LinkSetMaster(device, bridge.(*netlink.Bridge)); err != nil {
+			return nil, fmt.Errorf("enslave physical device: %w", err)
+		}
+		log.Println("enslaved", network.PhysicalDevice, "to bridge", network.NetlinkBridge())
+	}
+	if err := netlink.LinkSetUp(device); err != nil {
+		return nil, fmt.Errorf("set physical device up: %w", err)
+	}
+	if err := netlink.LinkSetUp(bridge); err != nil {
+		return nil, fmt.Errorf("set bridge up: %w", err)
+	}
+	return bridge, nil
+}
+
+// createLibvirtNetwork defines the libvirt network of NAT, routed and local isolated networks.
+func (lv *Libvirt) createLibvirtNetwork(network *models.Network) (*libvirt.Network, error) {
 	// Handle libvirt network
 	lvnet, err := lv.conn.LookupNetworkByUUIDString(network.ID)
 	lvErr, _ := err.(libvirt.Error)
@@ -371,16 +542,32 @@ func (lv *Libvirt) createNATNetwork(network *models.Network) (*libvirt.Network,
 		return nil, fmt.Errorf("parse network cidr: %w", err)
 	}
 	prefix, _ := netmask.Mask.Size()
-	lvipXml := []libvirtxml.NetworkIP{
-		{
-			Address: network.IPv4.Gateway,
-			Prefix:  uint(prefix),
-		},
+	var lvipXml []libvirtxml.NetworkIP
+	if network.HasHostAddress() {
+		lvipXml = []libvirtxml.NetworkIP{
+			{
+				Address: network.IPv4.Gateway,
+				Prefix:  uint(prefix),
+			},
+		}
+		if network.HostIPv6() != nil {
+			_, netmaskv6, err := net.ParseCIDR(network.IPv6.Subnet)
+			if err != nil {
+				return nil, fmt.Errorf("parse ipv6 network cidr: %w", err)
+			}
+			prefixv6, _ := netmaskv6.Mask.Size()
+			lvipXml = append(lvipXml, libvirtxml.NetworkIP{
+				Family:  "ipv6",
+				Address: network.IPv6.Gateway,
+				Prefix:  uint(prefixv6),
+			})
+		}
 	}
-	lvnetXml := &libvirtxml.Network{
-		UUID: network.ID,
-		Name: network.ID,
-		Forward: &libvirtxml.NetworkForward{
+	// Isolated networks are not forwarded at all
+	var lvforwardXml *libvirtxml.NetworkForward
+	switch network.Mode {
+	case models.NetworkModeNAT:
+		lvforwardXml = &libvirtxml.NetworkForward{
 			NAT: &libvirtxml.NetworkForwardNAT{
 				Ports: []libvirtxml.NetworkForwardNATPort{
 					{
@@ -389,14 +576,32 @@ func (lv *Libvirt) createNATNetwork(network *models.Network) (*libvirt.Network,
 					},
 				},
 			},
+		}
+	case models.NetworkModeRouted:
+		lvforwardXml = &libvirtxml.NetworkForward{
+			Mode: "route",
+		}
+	}
+	lvnetXml := &libvirtxml.Network{
+		UUID:    network.ID,
+		Name:    network.ID,
+		Forward: lvforwardXml,
+		IPs:     lvipXml,
+		// DNS is served by sox itself
+		DNS: &libvirtxml.NetworkDNS{
+			Enable: "no",
 		},
-		IPs: lvipXml,
 		Bridge: &libvirtxml.NetworkBridge{
 			Name:  network.NetlinkBridge(),
 			STP:   "on",
 			Delay: "0",
 		},
 	}
+	if network.MTU > 0 {
+		lvnetXml.MTU = &libvirtxml.NetworkMTU{
+			Size: uint(network.MTU),
+		}
+	}
 	xml, _ := lvnetXml.Marshal()
 	lvnet, err = lv.conn.NetworkCreateXML(xml)
 	if err != nil {
@@ -407,17 +612,22 @@ func (lv *Libvirt) createNATNetwork(network *models.Network) (*libvirt.Network,
 
 // CreateNetwork ensures that the specified network exists on the machine.
 func (lv *Libvirt) CreateNetwork(network *models.Network) error {
-	if network.IsBridge() {
-		// Handle bridged network
-		_, err := lv.createVxlanBridge(network)
-		if err != nil {
+	switch {
+	case network.Mode == models.NetworkModeBridged:
+		if _, err := lv.createPhysicalBridge(network); err != nil {
+			return fmt.Errorf("create physical bridge: %w", err)
+		}
+		return nil
+	case network.IsBridge():
+		// Handle VXLAN network
+		if _, err := lv.createVxlanBridge(network); err != nil {
 			return fmt.Errorf("create bridge: %w", err)
 		}
 		return nil
 	}
-	lvnet, err := lv.createNATNetwork(network)
+	lvnet, err := lv.createLibvirtNetwork(network)
 	if err != nil {
-		return fmt.Errorf("create NAT network: %w", err)
+		return fmt.Errorf("create libvirt network: %w", err)
 	}
 	// Make sure that network is active
 	active, err := lvnet.IsActive()
@@ -432,9 +642,59 @@ func (lv *Libvirt) CreateNetwork(network *models.Network) error {
 	return nil
 }
 
+// DeleteNetwork removes the bridge or libvirt network of the network. Missing ones are skipped.
+// Bridges shared with other networks must not be deleted.
+func (lv *Libvirt) DeleteNetwork(network *models.Network) error {
+	if !network.IsBridge() {
+		lvnet, err := lv.conn.LookupNetworkByUUIDString(network.ID)
+		if lverr, ok := err.(libvirt.Error); ok && lverr.Code == libvirt.ERR_NO_NETWORK {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("lookup network: %w", err)
+		}
+		defer lvnet.Free()
+		// Networks are transient, so destroying them removes them
+		if err := lvnet.Destroy(); err != nil {
+			return fmt.Errorf("destroy network: %w", err)
+		}
+		return nil
+	}
+	if network.Mode == models.NetworkModeBridged {
+		// Release the physical device before its bridge is gone
+		device, err := netlink.LinkByName(network.PhysicalDevice)
+		if err == nil {
+			err = netlink.LinkSetNoMaster(device)
+		}
+		if _, ok := err.(netlink.LinkNotFoundError); err != nil && !ok {
+			return fmt.Errorf("release physical device: %w", err)
+		}
+	} else {
+		vxlan, err := netlink.LinkByName(network.NetlinkVxlan())
+		if err == nil {
+			err = netlink.LinkDel(vxlan)
+		}
+		if _, ok := err.(netlink.LinkNotFoundError); err != nil && !ok {
+			return fmt.Errorf("delete vxlan: %w", err)
+		}
+	}
+	bridge, err := netlink.LinkByName(network.NetlinkBridge())
+	if err == nil {
+		err = netlink.LinkDel(bridge)
+	}
+	if _, ok := err.(netlink.LinkNotFoundError); err != nil && !ok {
+		return fmt.Errorf("delete bridge: %w", err)
+	}
+	return nil
+}
+
 func (lv *Libvirt) DeleteMachine(machine *models.Machine) error {
 	dom, err := lv.conn.LookupDomainByUUIDString(machine.ID)
-	if err != nil {
+	if lverr, ok := err.(libvirt.Error); ok && lverr.Code == libvirt.ERR_NO_DOMAIN {
+		// Machines prepared for an incoming migration have no domain yet
+		log.Println("libvirt domain", machine.ID, "does not exist")
+		lv.ReleaseMachine(machine, true)
+		return nil
+	} else if err != nil {
 		return fmt.Errorf("lookup domain: %w", err)
 	}
 	// Stop domain if necessary
@@ -454,7 +714,23 @@ func (lv *Libvirt) DeleteMachine(machine *models.Machine) error {
 		return fmt.Errorf("undefine domain: %w", err)
 	}
 	log.Println("undefined libvirt domain", machine.ID)
-	// Delete disks
+	lv.ReleaseMachine(machine, true)
+	return nil
+}
+
+// ReleaseMachine drops the interface filters and disks of a machine whose domain is gone.
+// Disks on shared storage are kept.
+func (lv *Libvirt) ReleaseMachine(machine *models.Machine, deleteDisk bool) {
+	// Drop interface filters
+	for i := range machine.NetworkInterfaces {
+		if err := lv.UndefineInterfaceFilter(&machine.NetworkInterfaces[i]); err != nil {
+			log.Println("attempted to undefine interface filter:", err)
+		}
+	}
+	// Delete disks, the config disk is shared as well
+	if !deleteDisk {
+		return
+	}
 	configDiskPath, imageDiskPath := machine.LiveImagePaths(lv.storagePath)
 	if err := os.Remove(configDiskPath); err != nil {
 		log.Println("attempted to delete config disk:", err)
@@ -462,7 +738,6 @@ func (lv *Libvirt) DeleteMachine(machine *models.Machine) error {
 	if err := os.Remove(imageDiskPath); err != nil {
 		log.Println("attempted to delete image disk:", err)
 	}
-	return nil
 }
 
 // RebootMachine reboots an active machine.
@@ -501,18 +776,254 @@ func (lv *Libvirt) StartMachine(machine *models.Machine) error {
 	return nil
 }
 
-// writeDisabledNetworkConfig creates a basic network config that disabled cloud-init networking setup.
-func writeDisabledNetworkConfig() (string, error) {
-	// Create basic network config
+// writeNetworkConfig creates a cloud-init network config matching interfaces by their MAC address.
+// Bridged interfaces are configured using DHCP, NAT interfaces get static addresses.
+func writeNetworkConfig(machine *models.Machine) (string, error) {
+	ethernets := make(map[string]cloudconfig.NetworkEthernet)
+	for i, iface := range machine.NetworkInterfaces {
+		ethernet := cloudconfig.NetworkEthernet{
+			Match: cloudconfig.NetworkMatch{
+				MACAddress: iface.HwAddr,
+			},
+			MTU: iface.Network.MTU,
+		}
+		switch {
+		case iface.Network.IsBridge() && iface.Network.HasHostAddress():
+			// Served by the sox DHCP responder
+			ethernet.DHCPv4 = true
+			ethernet.DHCPv6 = iface.IPv6 != ""
+		case iface.Network.Mode == models.NetworkModeIsolated:
+			// Without a host there is neither gateway nor resolver
+			ethernet.Addresses = []string{iface.IPv4}
+			if iface.IPv6 != "" {
+				ethernet.Addresses = append(ethernet.Addresses, iface.IPv6)
+			}
+		case iface.Network.Mode == models.NetworkModeBridged:
+			// Gateway and resolvers are part of the physical network
+			ethernet.Addresses = []string{iface.IPv4}
+			ethernet.GatewayIPv4 = iface.Network.IPv4.Gateway
+			if iface.IPv6 != "" {
+				ethernet.Addresses = append(ethernet.Addresses, iface.IPv6)
+				ethernet.GatewayIPv6 = iface.Network.IPv6.Gateway
+			}
+			ethernet.Nameservers = cloudconfig.NetworkNameservers{
+				Addresses:     strings.Fields(iface.Network.Nameservers),
+				SearchDomains: strings.Fields(iface.Network.SearchDomains),
+			}
+		default:
+			ethernet.Addresses = []string{iface.IPv4}
+			ethernet.GatewayIPv4 = iface.Network.IPv4.Gateway
+			if iface.IPv6 != "" {
+				ethernet.Addresses = append(ethernet.Addresses, iface.IPv6)
+				ethernet.GatewayIPv6 = iface.Network.IPv6.Gateway
+			}
+			// The sox resolver listens on the gateway of libvirt networks
+			ethernet.Nameservers = cloudconfig.NetworkNameservers{
+				Addresses:     []string{iface.Network.IPv4.Gateway},
+				SearchDomains: strings.Fields(iface.Network.SearchDomains),
+			}
+		}
+		ethernets[fmt.Sprintf("eth%d", i)] = ethernet
+	}
+	content, err := yaml.Marshal(cloudconfig.NetworkConfig{
+		Version:   2,
+		Ethernets: ethernets,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create network config: %w", err)
+	}
 	netcfg, err := os.CreateTemp("", "netcfg")
 	if err != nil {
 		return "", fmt.Errorf("network config: %w", err)
 	}
 	defer netcfg.Close()
-	fmt.Fprintln(netcfg, "network: { config: disabled }")
+	if _, err := netcfg.Write(content); err != nil {
+		return "", fmt.Errorf("write network config: %w", err)
+	}
 	return netcfg.Name(), nil
 }
 
+// writeConfigImage generates the NoCloud seed of a machine.
+func writeConfigImage(machine *models.Machine, configImagePath string) error {
+	netcfg, err := writeNetworkConfig(machine)
+	if err != nil {
+		return fmt.Errorf("network config: %w", err)
+	}
+	// Create cloud config
+	cloudcfg, err := writeCloudConfig(machine)
+	if err != nil {
+		return fmt.Errorf("cloud config: %w", err)
+	}
+	log.Println("created cloud config", cloudcfg)
+	// Merge into image
+	start := time.Now()
+	if err := exec.Command("cloud-localds", "-v", "-N", netcfg, configImagePath, cloudcfg).Run(); err != nil {
+		return fmt.Errorf("merge config: %w", err)
+	}
+	OperationDurations.WithLabelValues("cloud_localds").Observe(time.Since(start).Seconds())
+	return nil
+}
+
+// updateConfigImage regenerates the NoCloud seed and swaps the media of running domains,
+// so that cloud-init picks up the new network configuration.
+func (lv *Libvirt) updateConfigImage(machine *models.Machine, dom *libvirt.Domain) error {
+	configImagePath, _ := machine.LiveImagePaths(lv.storagePath)
+	if err := writeConfigImage(machine, configImagePath); err != nil {
+		return err
+	}
+	active, err := dom.IsActive()
+	if err != nil {
+		return fmt.Errorf("check domain isActive: %w", err)
+	}
+	if !active {
+		return nil
+	}
+	// Eject and re-insert, qemu keeps the old image open otherwise
+	for _, path := range []string{"", configImagePath} {
+		disk := buildConfigDiskXml(path)
+		diskXml, err := disk.Marshal()
+		if err != nil {
+			return fmt.Errorf("marshal config disk: %w", err)
+		}
+		if err := dom.UpdateDeviceFlags(diskXml, libvirt.DOMAIN_DEVICE_MODIFY_LIVE); err != nil {
+			return fmt.Errorf("update config disk: %w", err)
+		}
+	}
+	return nil
+}
+
+// deviceModifyFlags returns the flags to modify the persistent and, if running, the live domain.
+func deviceModifyFlags(dom *libvirt.Domain) (libvirt.DomainDeviceModifyFlags, error) {
+	active, err := dom.IsActive()
+	if err != nil {
+		return 0, fmt.Errorf("check domain isActive: %w", err)
+	}
+	flags := libvirt.DOMAIN_DEVICE_MODIFY_CONFIG
+	if active {
+		flags |= libvirt.DOMAIN_DEVICE_MODIFY_LIVE
+	}
+	return flags, nil
+}
+
+// AttachNetworkInterface hot-plugs the interface into the domain. The interface must
+// already be part of the machine. It reports whether the interface was plugged into the
+// running domain, whose guest configures it on its next boot.
+func (lv *Libvirt) AttachNetworkInterface(machine *models.Machine, iface *models.NetworkInterface) (bool, error) {
+	dom, err := lv.conn.LookupDomainByUUIDString(machine.ID)
+	if err != nil {
+		return false, fmt.Errorf("lookup domain: %w", err)
+	}
+	if err := lv.DefineInterfaceFilter(iface); err != nil {
+		return false, fmt.Errorf("define interface filter: %w", err)
+	}
+	lvIface := buildInterfaceXml(iface)
+	ifaceXml, err := lvIface.Marshal()
+	if err != nil {
+		return false, fmt.Errorf("marshal interface: %w", err)
+	}
+	flags, err := deviceModifyFlags(dom)
+	if err != nil {
+		return false, err
+	}
+	if err := dom.AttachDeviceFlags(ifaceXml, flags); err != nil {
+		return false, fmt.Errorf("attach interface: %w", err)
+	}
+	log.Println("attached interface", iface.HwAddr, "to libvirt domain", machine.ID)
+	if err := lv.updateConfigImage(machine, dom); err != nil {
+		return false, fmt.Errorf("update config image: %w", err)
+	}
+	return flags&libvirt.DOMAIN_DEVICE_MODIFY_LIVE != 0, nil
+}
+
+// DetachNetworkInterface unplugs the interface from the domain. The interface must
+// no longer be part of the machine.
+func (lv *Libvirt) DetachNetworkInterface(machine *models.Machine, iface *models.NetworkInterface) error {
+	dom, err := lv.conn.LookupDomainByUUIDString(machine.ID)
+	if err != nil {
+		return fmt.Errorf("lookup domain: %w", err)
+	}
+	lvIface := buildInterfaceXml(iface)
+	ifaceXml, err := lvIface.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshal interface: %w", err)
+	}
+	flags, err := deviceModifyFlags(dom)
+	if err != nil {
+		return err
+	}
+	if err := dom.DetachDeviceFlags(ifaceXml, flags); err != nil {
+		return fmt.Errorf("detach interface: %w", err)
+	}
+	log.Println("detached interface", iface.HwAddr, "from libvirt domain", machine.ID)
+	if err := lv.UndefineInterfaceFilter(iface); err != nil {
+		log.Println("attempted to undefine interface filter:", err)
+	}
+	if err := lv.updateConfigImage(machine, dom); err != nil {
+		return fmt.Errorf("update config image: %w", err)
+	}
+	return nil
+}
+
+// UpdateNetworkInterface applies the current settings of the interface to the domain,
+// running domains pick up changed bandwidth limits immediately.
+func (lv *Libvirt) UpdateNetworkInterface(machine *models.Machine, iface *models.NetworkInterface) error {
+	dom, err := lv.conn.LookupDomainByUUIDString(machine.ID)
+	if err != nil {
+		return fmt.Errorf("lookup domain: %w", err)
+	}
+	lvIface := buildInterfaceXml(iface)
+	ifaceXml, err := lvIface.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshal interface: %w", err)
+	}
+	flags, err := deviceModifyFlags(dom)
+	if err != nil {
+		return err
+	}
+	if err := dom.UpdateDeviceFlags(ifaceXml, flags); err != nil {
+		return fmt.Errorf("update interface: %w", err)
+	}
+	log.Println("updated interface", iface.HwAddr, "of libvirt domain", machine.ID)
+	return nil
+}
+
+// HostInfo describes the hardware of the host and the resources currently unused on it.
+type HostInfo struct {
+	CPUModel string
+	// Capacity holds the vCPUs, memory and storage pool size of the host.
+	Capacity models.Specs
+	// Free holds the free memory of the host and the space available in the storage pool.
+	Free models.Specs
+}
+
+// GetHostInfo queries the size of the host and its free memory and storage.
+func (lv *Libvirt) GetHostInfo() (HostInfo, error) {
+	nodeInfo, err := lv.conn.GetNodeInfo()
+	if err != nil {
+		return HostInfo{}, fmt.Errorf("get node info: %w", err)
+	}
+	freeMemory, err := lv.conn.GetFreeMemory()
+	if err != nil {
+		return HostInfo{}, fmt.Errorf("get free memory: %w", err)
+	}
+	poolInfo, err := lv.storagePool.GetInfo()
+	if err != nil {
+		return HostInfo{}, fmt.Errorf("get storage pool info: %w", err)
+	}
+	return HostInfo{
+		CPUModel: nodeInfo.Model,
+		Capacity: models.Specs{
+			CPUs:   int64(nodeInfo.Cpus),
+			Memory: int64(nodeInfo.Memory / 1024),
+			Disk:   int64(poolInfo.Capacity / (1 << 30)),
+		},
+		Free: models.Specs{
+			Memory: int64(freeMemory / (1 << 20)),
+			Disk:   int64(poolInfo.Available / (1 << 30)),
+		},
+	}, nil
+}
+
 func (lv *Libvirt) GetMachineState(id string) (models.MachineState, error) {
 	// No entry found, unlock and get entry
 	dom, err := lv.conn.LookupDomainByUUIDString(id)
@@ -537,44 +1048,257 @@ func (lv *Libvirt) GetMachineState(id string) (models.MachineState, error) {
 	return machineState, nil
 }
 
+// WatchMachineStates calls fn with the ID and new state of a machine on every lifecycle event
+// of its domain. fn is called from the event loop and must not block.
+func (lv *Libvirt) WatchMachineStates(fn func(id string, state models.MachineState)) error {
+	if _, err := lv.conn.DomainEventLifecycleRegister(nil, func(c *libvirt.Connect, dom *libvirt.Domain, event *libvirt.DomainEventLifecycle) {
+		var state models.MachineState
+		switch event.Event {
+		case libvirt.DOMAIN_EVENT_STARTED, libvirt.DOMAIN_EVENT_RESUMED:
+			state = models.StateRunning
+		case libvirt.DOMAIN_EVENT_CRASHED:
+			state = models.StateCrashed
+		case libvirt.DOMAIN_EVENT_SUSPENDED, libvirt.DOMAIN_EVENT_STOPPED, libvirt.DOMAIN_EVENT_SHUTDOWN, libvirt.DOMAIN_EVENT_PMSUSPENDED:
+			state = models.StateStopped
+		default:
+			// Definitions do not change the state
+			return
+		}
+		id, err := dom.GetUUIDString()
+		if err != nil {
+			log.Println("get domain id of lifecycle event:", err)
+			return
+		}
+		fn(id, state)
+	}); err != nil {
+		return fmt.Errorf("register lifecycle callback: %w", err)
+	}
+	return nil
+}
+
+// WatchMachineBoots calls fn with the ID of a machine whenever its domain boots, either started
+// or reset by a reboot of the guest. fn is called from the event loop and must not block.
+func (lv *Libvirt) WatchMachineBoots(fn func(id string)) error {
+	notify := func(dom *libvirt.Domain) {
+		id, err := dom.GetUUIDString()
+		if err != nil {
+			log.Println("get domain id of boot event:", err)
+			return
+		}
+		fn(id)
+	}
+	if _, err := lv.conn.DomainEventLifecycleRegister(nil, func(c *libvirt.Connect, dom *libvirt.Domain, event *libvirt.DomainEventLifecycle) {
+		// Restored and migrated domains continue running their guest
+		if event.Event == libvirt.DOMAIN_EVENT_STARTED && event.Detail == int(libvirt.DOMAIN_EVENT_STARTED_BOOTED) {
+			notify(dom)
+		}
+	}); err != nil {
+		return fmt.Errorf("register lifecycle callback: %w", err)
+	}
+	if _, err := lv.conn.DomainEventRebootRegister(nil, func(c *libvirt.Connect, dom *libvirt.Domain) {
+		notify(dom)
+	}); err != nil {
+		return fmt.Errorf("register reboot callback: %w", err)
+	}
+	return nil
+}
+
 func (lv *Libvirt) CreateMachine(machine *models.Machine) error {
 	// Get source img path
-	configImagePath, osImagePath := machine.LiveImagePaths(lv.storagePath)
+	_, osImagePath := machine.LiveImagePaths(lv.storagePath)
 	osImageSize := fmt.Sprintf("%dG", machine.Specs.Disk)
 	// Create image snapshot
+	start := time.Now()
 	if err := exec.Command("qemu-img", "create", "-b", machine.Image.Path, "-f", "qcow2", "-F", "qcow2", osImagePath, osImageSize).Run(); err != nil {
 		return fmt.Errorf("create image snapshot: %w", err)
 	}
+	OperationDurations.WithLabelValues("image_snapshot").Observe(time.Since(start).Seconds())
 	log.Println("replicated image", machine.Image.ID, "to", osImagePath)
-	// Setup networking in snapshot
-	if err := configureImageNetworkInterface(machine, osImagePath); err != nil {
-		return fmt.Errorf("configure image network: %w", err)
-	}
-	netcfg, err := writeDisabledNetworkConfig()
+	dom, err := lv.defineMachine(machine)
 	if err != nil {
-		return fmt.Errorf("network config: %w", err)
+		return err
 	}
-	// Create cloud config
-	cloudcfg, err := writeCloudConfig(machine)
-	if err != nil {
-		return fmt.Errorf("cloud config: %w", err)
+	// And start domain
+	if err := dom.Create(); err != nil {
+		return fmt.Errorf("create domain: %w", err)
 	}
-	log.Println("created cloud config", cloudcfg)
-	// Merge into image
-	if err := exec.Command("cloud-localds", "-v", "-N", netcfg, configImagePath, cloudcfg).Run(); err != nil {
-		return fmt.Errorf("merge config: %w", err)
+	log.Println("created libvirt domain", machine.ID)
+	return nil
+}
+
+// defineMachine writes the config image and defines the domain on top of an existing disk.
+func (lv *Libvirt) defineMachine(machine *models.Machine) (*libvirt.Domain, error) {
+	configImagePath, osImagePath := machine.LiveImagePaths(lv.storagePath)
+	if err := writeConfigImage(machine, configImagePath); err != nil {
+		return nil, err
+	}
+	// Define interface filters, the domain refuses to start without them
+	for i := range machine.NetworkInterfaces {
+		if err := lv.DefineInterfaceFilter(&machine.NetworkInterfaces[i]); err != nil {
+			return nil, fmt.Errorf("define interface filter: %w", err)
+		}
 	}
 	// Generate domain xml
 	domXml := buildDomXml(machine.ID, machine.Specs, configImagePath, osImagePath, machine.NetworkInterfaces)
 	dom, err := lv.conn.DomainDefineXML(domXml)
 	if err != nil {
-		return fmt.Errorf("define domain: %w", err)
+		return nil, fmt.Errorf("define domain: %w", err)
 	}
 	log.Println("defined libvirt domain", machine.ID)
-	// And start domain
+	return dom, nil
+}
+
+// OpenMachineDisk opens the disk overlay of a machine for reading. The machine should be stopped.
+func (lv *Libvirt) OpenMachineDisk(machine *models.Machine) (*os.File, error) {
+	_, osImagePath := machine.LiveImagePaths(lv.storagePath)
+	return os.Open(osImagePath)
+}
+
+// writeDisk writes the disk data to a new file at path.
+func writeDisk(path string, data io.Reader) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("create disk: %w", err)
+	}
+	if _, err := io.Copy(file, data); err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("copy disk: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("close disk: %w", err)
+	}
+	return nil
+}
+
+// ImportMachine defines a machine whose disk overlay is read from disk. The overlay is
+// rebased onto the local copy of the machine image.
+func (lv *Libvirt) ImportMachine(machine *models.Machine, disk io.Reader, start bool) error {
+	_, osImagePath := machine.LiveImagePaths(lv.storagePath)
+	copyStart := time.Now()
+	if err := writeDisk(osImagePath, disk); err != nil {
+		return err
+	}
+	OperationDurations.WithLabelValues("disk_copy").Observe(time.Since(copyStart).Seconds())
+	if err := exec.Command("qemu-img", "rebase", "-u", "-b", machine.Image.Path, "-F", "qcow2", osImagePath).Run(); err != nil {
+		os.Remove(osImagePath)
+		return fmt.Errorf("rebase disk: %w", err)
+	}
+	log.Println("imported disk of machine", machine.ID, "to", osImagePath)
+	dom, err := lv.defineMachine(machine)
+	if err != nil {
+		return err
+	}
+	if !start {
+		return nil
+	}
 	if err := dom.Create(); err != nil {
 		return fmt.Errorf("create domain: %w", err)
 	}
 	log.Println("created libvirt domain", machine.ID)
 	return nil
 }
+
+// ImagePath returns the path images copied from other nodes are stored at.
+func (lv *Libvirt) ImagePath(id string) string {
+	return filepath.Join(lv.storagePath, id+".qcow2")
+}
+
+// OpenImage opens a base image for reading.
+func (lv *Libvirt) OpenImage(image *models.Image) (*os.File, error) {
+	return os.Open(image.Path)
+}
+
+// ImportImage stores a base image read from data.
+func (lv *Libvirt) ImportImage(image *models.Image, data io.Reader) error {
+	start := time.Now()
+	if err := writeDisk(image.Path, data); err != nil {
+		return err
+	}
+	OperationDurations.WithLabelValues("image_copy").Observe(time.Since(start).Seconds())
+	log.Println("imported image", image.ID, "to", image.Path)
+	return nil
+}
+
+// PrepareMachine readies the node for an incoming live migration and returns the domain
+// definition to migrate with. Unless the disk is on shared storage, an overlay is created
+// for libvirt to copy the disk into.
+func (lv *Libvirt) PrepareMachine(machine *models.Machine, createDisk bool) (string, error) {
+	configImagePath, osImagePath := machine.LiveImagePaths(lv.storagePath)
+	if createDisk {
+		osImageSize := fmt.Sprintf("%dG", machine.Specs.Disk)
+		start := time.Now()
+		if err := exec.Command("qemu-img", "create", "-b", machine.Image.Path, "-f", "qcow2", "-F", "qcow2", osImagePath, osImageSize).Run(); err != nil {
+			return "", fmt.Errorf("create image snapshot: %w", err)
+		}
+		OperationDurations.WithLabelValues("image_snapshot").Observe(time.Since(start).Seconds())
+	}
+	if err := writeConfigImage(machine, configImagePath); err != nil {
+		return "", err
+	}
+	for i := range machine.NetworkInterfaces {
+		if err := lv.DefineInterfaceFilter(&machine.NetworkInterfaces[i]); err != nil {
+			return "", fmt.Errorf("define interface filter: %w", err)
+		}
+	}
+	log.Println("prepared machine", machine.ID, "for migration")
+	return buildDomXml(machine.ID, machine.Specs, configImagePath, osImagePath, machine.NetworkInterfaces), nil
+}
+
+// MigrateMachine live migrates the machine peer-to-peer to the libvirt daemon at uri.
+// The domain is persisted on the destination and undefined here.
+func (lv *Libvirt) MigrateMachine(machine *models.Machine, uri, domXml string, copyStorage bool) error {
+	dom, err := lv.conn.LookupDomainByUUIDString(machine.ID)
+	if err != nil {
+		return fmt.Errorf("lookup domain: %w", err)
+	}
+	params := &libvirt.DomainMigrateParameters{
+		DestXMLSet: domXml != "",
+		DestXML:    domXml,
+	}
+	flags := libvirt.MIGRATE_LIVE | libvirt.MIGRATE_PEER2PEER | libvirt.MIGRATE_PERSIST_DEST | libvirt.MIGRATE_UNDEFINE_SOURCE
+	if copyStorage {
+		// Only the overlay is copied, the config image is written by the destination
+		flags |= libvirt.MIGRATE_NON_SHARED_INC
+		params.MigrateDisksSet = true
+		params.MigrateDisks = []string{"vda"}
+	}
+	if err := dom.MigrateToURI3(uri, params, flags); err != nil {
+		return fmt.Errorf("migrate domain: %w", err)
+	}
+	log.Println("migrated libvirt domain", machine.ID, "to", uri)
+	return nil
+}
+
+// FlushForwarding drops the VXLAN forwarding entries learned for the MAC addresses,
+// so that traffic is flooded until the new location has been learned.
+func (lv *Libvirt) FlushForwarding(macs []string) error {
+	flush := make(map[string]bool, len(macs))
+	for _, mac := range macs {
+		flush[strings.ToLower(mac)] = true
+	}
+	links, err := netlink.LinkList()
+	if err != nil {
+		return fmt.Errorf("list links: %w", err)
+	}
+	for _, link := range links {
+		if link.Type() != "vxlan" {
+			continue
+		}
+		neighs, err := netlink.NeighList(link.Attrs().Index, unix.AF_BRIDGE)
+		if err != nil {
+			return fmt.Errorf("list forwarding entries of %s: %w", link.Attrs().Name, err)
+		}
+		for i := range neighs {
+			if !flush[neighs[i].HardwareAddr.String()] {
+				continue
+			}
+			if err := netlink.NeighDel(&neighs[i]); err != nil {
+				return fmt.Errorf("delete forwarding entry of %s: %w", link.Attrs().Name, err)
+			}
+			log.Println("flushed forwarding entry", neighs[i].HardwareAddr, "on", link.Attrs().Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package libvirt
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+
+	libvirtxml "github.com/libvirt/libvirt-go-xml"
+	"github.com/lnsp/sox/driver/models"
+)
+
+const (
+	// Priorities of rules within the root chain of an interface filter.
+	// Group rules accept traffic before the trailing drop rules are hit.
+	baseRulePriority  = 100
+	groupRulePriority = 500
+	dropRulePriority  = 900
+)
+
+// SecurityGroupFilter returns the name of the nwfilter holding the rules of a group.
+func SecurityGroupFilter(id string) string {
+	return "sox-sg-" + id
+}
+
+// InterfaceFilter returns the name of the nwfilter attached to a network interface.
+func InterfaceFilter(iface *models.NetworkInterface) string {
+	return "sox-nic-" + strings.ReplaceAll(iface.HwAddr, ":", "")
+}
+
+// peer is a single address range matched by a rule.
+type peer struct {
+	addr string
+	mask string
+	ipv6 bool
+}
+
+func parsePeer(cidr string) (peer, error) {
+	ip, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return peer{}, err
+	}
+	ones, _ := ipnet.Mask.Size()
+	return peer{
+		addr: ip.Mask(ipnet.Mask).String(),
+		mask: strconv.Itoa(ones),
+		ipv6: ip.To4() == nil,
+	}, nil
+}
+
+// hostPeer matches exactly the address of the given interface CIDR.
+func hostPeer(cidr string) (peer, error) {
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return peer{}, err
+	}
+	if ip.To4() != nil {
+		return peer{addr: ip.String(), mask: "32"}, nil
+	}
+	return peer{addr: ip.String(), mask: "128", ipv6: true}, nil
+}
+
+func field(value string) libvirtxml.NWFilterField {
+	return libvirtxml.NWFilterField{Str: value}
+}
+
+// buildSecurityGroupRule translates a group rule into nwfilter accept rules, one per peer.
+func buildSecurityGroupRule(rule *models.SecurityGroupRule, members map[string][]string) ([]*libvirtxml.NWFilterRule, error) {
+	var peers []peer
+	switch {
+	case rule.RemoteGroupID != "":
+		for _, addr := range members[rule.RemoteGroupID] {
+			p, err := hostPeer(addr)
+			if err != nil {
+				continue
+			}
+			peers = append(peers, p)
+		}
+	case rule.CIDR != "":
+		p, err := parsePeer(rule.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("parse cidr: %w", err)
+		}
+		peers = append(peers, p)
+	default:
+		peers = []peer{{}, {ipv6: true}}
+	}
+	// Ingress matches traffic sent to the machine, egress traffic sent by it.
+	// nwfilter tracks connections, so replies are accepted automatically.
+	direction := "in"
+	if rule.Direction == "EGRESS" {
+		direction = "out"
+	}
+	var ports libvirtxml.NWFilterRuleCommonPort
+	if rule.PortMin != 0 {
+		portMax := rule.PortMax
+		if portMax < rule.PortMin {
+			portMax = rule.PortMin
+		}
+		ports.DstPortStart = field(strconv.Itoa(int(rule.PortMin)))
+		ports.DstPortEnd = field(strconv.Itoa(int(portMax)))
+	}
+	lvRules := make([]*libvirtxml.NWFilterRule, 0, len(peers))
+	for _, p := range peers {
+		var match libvirtxml.NWFilterRuleCommonIP
+		if p.addr != "" && direction == "in" {
+			match.SrcIPAddr, match.SrcIPMask = field(p.addr), field(p.mask)
+		} else if p.addr != "" {
+			match.DstIPAddr, match.DstIPMask = field(p.addr), field(p.mask)
+		}
+		lvRule := &libvirtxml.NWFilterRule{
+			Action:    "accept",
+			Direction: direction,
+			Priority:  groupRulePriority,
+		}
+		switch {
+		case rule.Protocol == "TCP" && p.ipv6:
+			lvRule.TCPIPv6 = &libvirtxml.NWFilterRuleTCPIPv6{NWFilterRuleCommonIP: match, NWFilterRuleCommonPort: ports}
+		case rule.Protocol == "TCP":
+			lvRule.TCP = &libvirtxml.NWFilterRuleTCP{NWFilterRuleCommonIP: match, NWFilterRuleCommonPort: ports}
+		case rule.Protocol == "UDP" && p.ipv6:
+			lvRule.UDPIPv6 = &libvirtxml.NWFilterRuleUDPIPv6{NWFilterRuleCommonIP: match, NWFilterRuleCommonPort: ports}
+		case rule.Protocol == "UDP":
+			lvRule.UDP = &libvirtxml.NWFilterRuleUDP{NWFilterRuleCommonIP: match, NWFilterRuleCommonPort: ports}
+		case rule.Protocol == "ICMP" && p.ipv6:
+			lvRule.ICMPv6 = &libvirtxml.NWFilterRuleICMPIPv6{NWFilterRuleCommonIP: match}
+		case rule.Protocol == "ICMP":
+			lvRule.ICMP = &libvirtxml.NWFilterRuleICMP{NWFilterRuleCommonIP: match}
+		case p.ipv6:
+			lvRule.AllIPv6 = &libvirtxml.NWFilterRuleAllIPv6{NWFilterRuleCommonIP: match}
+		default:
+			lvRule.All = &libvirtxml.NWFilterRuleAll{NWFilterRuleCommonIP: match}
+		}
+		lvRules = append(lvRules, lvRule)
+	}
+	return lvRules, nil
+}
+
+// DefineSecurityGroup creates or updates the nwfilter of a security group. Members maps group IDs
+// to the addresses of their interfaces and is used to expand rules referencing other groups.
+// Redefining a filter updates all running domains referencing it.
+func (lv *Libvirt) DefineSecurityGroup(group *models.SecurityGroup, members map[string][]string) error {
+	filter := &libvirtxml.NWFilter{
+		Name:  SecurityGroupFilter(group.ID),
+		Chain: "root",
+	}
+	for i := range group.Rules {
+		lvRules, err := buildSecurityGroupRule(&group.Rules[i], members)
+		if err != nil {
+			return fmt.Errorf("build rule %d: %w", group.Rules[i].ID, err)
+		}
+		for _, lvRule := range lvRules {
+			filter.Entries = append(filter.Entries, libvirtxml.NWFilterEntry{Rule: lvRule})
+		}
+	}
+	filterXml, err := filter.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshal filter: %w", err)
+	}
+	if _, err := lv.conn.NWFilterDefineXML(filterXml); err != nil {
+		return fmt.Errorf("define filter: %w", err)
+	}
+	log.Println("defined nwfilter", filter.Name)
+	return nil
+}
+
+// UndefineSecurityGroup removes the nwfilter of a security group.
+func (lv *Libvirt) UndefineSecurityGroup(id string) error {
+	return lv.undefineFilter(SecurityGroupFilter(id))
+}
+
+// DefineInterfaceFilter creates or updates the nwfilter of a network interface. Interfaces without
+// security groups accept all traffic, otherwise only traffic accepted by any of the groups passes.
+// The groups must have been defined before.
+func (lv *Libvirt) DefineInterfaceFilter(iface *models.NetworkInterface) error {
+	filter := &libvirtxml.NWFilter{
+		Name:  InterfaceFilter(iface),
+		Chain: "root",
+	}
+	if len(iface.SecurityGroups) > 0 {
+		// DHCP and neighbor discovery have to work regardless of the rules
+		filter.Entries = append(filter.Entries,
+			libvirtxml.NWFilterEntry{Rule: &libvirtxml.NWFilterRule{
+				Action: "accept", Direction: "out", Priority: baseRulePriority,
+				UDP: &libvirtxml.NWFilterRuleUDP{NWFilterRuleCommonPort: libvirtxml.NWFilterRuleCommonPort{DstPortStart: field("67")}},
+			}},
+			libvirtxml.NWFilterEntry{Rule: &libvirtxml.NWFilterRule{
+				Action: "accept", Direction: "in", Priority: baseRulePriority,
+				UDP: &libvirtxml.NWFilterRuleUDP{NWFilterRuleCommonPort: libvirtxml.NWFilterRuleCommonPort{DstPortStart: field("68")}},
+			}},
+			libvirtxml.NWFilterEntry{Rule: &libvirtxml.NWFilterRule{
+				Action: "accept", Direction: "out", Priority: baseRulePriority,
+				UDPIPv6: &libvirtxml.NWFilterRuleUDPIPv6{NWFilterRuleCommonPort: libvirtxml.NWFilterRuleCommonPort{DstPortStart: field("547")}},
+			}},
+			libvirtxml.NWFilterEntry{Rule: &libvirtxml.NWFilterRule{
+				Action: "accept", Direction: "in", Priority: baseRulePriority,
+				UDPIPv6: &libvirtxml.NWFilterRuleUDPIPv6{NWFilterRuleCommonPort: libvirtxml.NWFilterRuleCommonPort{DstPortStart: field("546")}},
+			}},
+			libvirtxml.NWFilterEntry{Rule: &libvirtxml.NWFilterRule{
+				Action: "accept", Direction: "inout", Priority: baseRulePriority,
+				ICMPv6: &libvirtxml.NWFilterRuleICMPIPv6{},
+			}},
+		)
+		for _, group := range iface.SecurityGroups {
+			filter.Entries = append(filter.Entries, libvirtxml.NWFilterEntry{
+				Ref: &libvirtxml.NWFilterRef{Filter: SecurityGroupFilter(group.ID)},
+			})
+		}
+		filter.Entries = append(filter.Entries,
+			libvirtxml.NWFilterEntry{Rule: &libvirtxml.NWFilterRule{
+				Action: "drop", Direction: "inout", Priority: dropRulePriority,
+				All: &libvirtxml.NWFilterRuleAll{},
+			}},
+			libvirtxml.NWFilterEntry{Rule: &libvirtxml.NWFilterRule{
+				Action: "drop", Direction: "inout", Priority: dropRulePriority,
+				AllIPv6: &libvirtxml.NWFilterRuleAllIPv6{},
+			}},
+		)
+	}
+	filterXml, err := filter.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshal filter: %w", err)
+	}
+	if _, err := lv.conn.NWFilterDefineXML(filterXml); err != nil {
+		return fmt.Errorf("define filter: %w", err)
+	}
+	log.Println("defined nwfilter", filter.Name)
+	return nil
+}
+
+// UndefineInterfaceFilter removes the nwfilter of a network interface.
+func (lv *Libvirt) UndefineInterfaceFilter(iface *models.NetworkInterface) error {
+	return lv.undefineFilter(InterfaceFilter(iface))
+}
+
+func (lv *Libvirt) undefineFilter(name string) error {
+	filter, err := lv.conn.LookupNWFilterByName(name)
+	if err != nil {
+		return fmt.Errorf("lookup filter: %w", err)
+	}
+	if err := filter.Undefine(); err != nil {
+		return fmt.Errorf("undefine filter: %w", err)
+	}
+	log.Println("undefined nwfilter", name)
+	return nil
+}
@@ -0,0 +1,131 @@
+package libvirt
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/libvirt/libvirt-go"
+	libvirtxml "github.com/libvirt/libvirt-go-xml"
+	"github.com/lnsp/sox/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OperationDurations measures the disk and config image operations done for machines and images.
+var OperationDurations = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "sox_operation_duration_seconds",
+	Help:    "Time taken by disk and config image operations, like copying images or running cloud-localds.",
+	Buckets: metrics.DefaultBuckets,
+}, []string{"operation"})
+
+// DomainStats are the counters of a running domain.
+type DomainStats struct {
+	ID string
+	// CPUTime is the time spent by all vCPUs of the domain.
+	CPUTime time.Duration
+	// BalloonCurrent and BalloonMaximum are the memory assigned to the domain in KiB.
+	BalloonCurrent uint64
+	BalloonMaximum uint64
+	// MemoryUsed is the memory used by the guest in KiB as reported by its balloon driver,
+	// or the resident memory of the domain if the guest does not report it.
+	MemoryUsed uint64
+	Disks      []DiskStats
+	Interfaces []InterfaceStats
+}
+
+type DiskStats struct {
+	Device        string
+	ReadBytes     uint64
+	WriteBytes    uint64
+	ReadRequests  uint64
+	WriteRequests uint64
+}
+
+type InterfaceStats struct {
+	Device string
+	// HwAddr is the MAC address of the interface, empty if it could not be looked up.
+	HwAddr    string
+	RxBytes   uint64
+	TxBytes   uint64
+	RxPackets uint64
+	TxPackets uint64
+}
+
+// GetDomainStats returns the stats of all running domains, gathered with a single bulk request.
+func (lv *Libvirt) GetDomainStats() ([]DomainStats, error) {
+	types := libvirt.DOMAIN_STATS_CPU_TOTAL | libvirt.DOMAIN_STATS_BALLOON | libvirt.DOMAIN_STATS_INTERFACE | libvirt.DOMAIN_STATS_BLOCK
+	bulk, err := lv.conn.GetAllDomainStats(nil, types, libvirt.CONNECT_GET_ALL_DOMAINS_STATS_RUNNING)
+	if err != nil {
+		return nil, fmt.Errorf("get all domain stats: %w", err)
+	}
+	stats := make([]DomainStats, 0, len(bulk))
+	for _, s := range bulk {
+		id, err := s.Domain.GetUUIDString()
+		if err != nil {
+			log.Println("get domain id of stats:", err)
+			s.Domain.Free()
+			continue
+		}
+		domainStats := DomainStats{ID: id}
+		if s.Cpu != nil {
+			domainStats.CPUTime = time.Duration(s.Cpu.Time)
+		}
+		if s.Balloon != nil {
+			domainStats.BalloonCurrent = s.Balloon.Current
+			domainStats.BalloonMaximum = s.Balloon.Maximum
+			if s.Balloon.AvailableSet && s.Balloon.UsableSet {
+				domainStats.MemoryUsed = s.Balloon.Available - s.Balloon.Usable
+			} else {
+				domainStats.MemoryUsed = s.Balloon.Rss
+			}
+		}
+		for _, block := range s.Block {
+			domainStats.Disks = append(domainStats.Disks, DiskStats{
+				Device:        block.Name,
+				ReadBytes:     block.RdBytes,
+				WriteBytes:    block.WrBytes,
+				ReadRequests:  block.RdReqs,
+				WriteRequests: block.WrReqs,
+			})
+		}
+		// The stats only name the host device of interfaces, which is mapped to their MAC address
+		hwAddrs := interfaceHwAddrs(s.Domain)
+		for _, iface := range s.Net {
+			domainStats.Interfaces = append(domainStats.Interfaces, InterfaceStats{
+				Device:    iface.Name,
+				HwAddr:    hwAddrs[iface.Name],
+				RxBytes:   iface.RxBytes,
+				TxBytes:   iface.TxBytes,
+				RxPackets: iface.RxPkts,
+				TxPackets: iface.TxPkts,
+			})
+		}
+		s.Domain.Free()
+		stats = append(stats, domainStats)
+	}
+	return stats, nil
+}
+
+// interfaceHwAddrs maps the host devices of the interfaces of a running domain to their MAC addresses.
+func interfaceHwAddrs(dom *libvirt.Domain) map[string]string {
+	hwAddrs := make(map[string]string)
+	domXml, err := dom.GetXMLDesc(0)
+	if err != nil {
+		log.Println("get domain xml of stats:", err)
+		return hwAddrs
+	}
+	var domCfg libvirtxml.Domain
+	if err := domCfg.Unmarshal(domXml); err != nil {
+		log.Println("parse domain xml of stats:", err)
+		return hwAddrs
+	}
+	if domCfg.Devices == nil {
+		return hwAddrs
+	}
+	for _, iface := range domCfg.Devices.Interfaces {
+		if iface.Target != nil && iface.MAC != nil {
+			hwAddrs[iface.Target.Dev] = iface.MAC.Address
+		}
+	}
+	return hwAddrs
+}
@@ -0,0 +1,136 @@
+package driver
+
+import (
+	"errors"
+	"log"
+
+	"github.com/lnsp/sox/driver/libvirt"
+	"github.com/lnsp/sox/driver/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+// countDBErrors registers callbacks counting the failed statements by operation. Missing records are not counted,
+// since they are expected by lookups.
+func countDBErrors(db *gorm.DB, counter *prometheus.CounterVec) error {
+	count := func(op string) func(*gorm.DB) {
+		return func(db *gorm.DB) {
+			if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+				counter.WithLabelValues(op).Inc()
+			}
+		}
+	}
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().After("gorm:create").Register("sox:count_errors", count("create")),
+		callbacks.Query().After("gorm:query").Register("sox:count_errors", count("query")),
+		callbacks.Update().After("gorm:update").Register("sox:count_errors", count("update")),
+		callbacks.Delete().After("gorm:delete").Register("sox:count_errors", count("delete")),
+		callbacks.Row().After("gorm:row").Register("sox:count_errors", count("row")),
+		callbacks.Raw().After("gorm:raw").Register("sox:count_errors", count("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Collectors returns the metrics of the node, including the usage of its running machines.
+func (driver *Driver) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		libvirt.OperationDurations,
+		driver.dbErrors,
+		machineStats{driver},
+	}
+}
+
+var (
+	machineLabels   = []string{"machine", "project"}
+	diskLabels      = []string{"machine", "project", "device"}
+	interfaceLabels = []string{"machine", "project", "network", "device"}
+
+	machineCPUDesc        = prometheus.NewDesc("sox_machine_cpu_seconds_total", "Time spent by all vCPUs of the machine.", machineLabels, nil)
+	machineBalloonDesc    = prometheus.NewDesc("sox_machine_memory_balloon_bytes", "Memory currently assigned to the machine by the balloon driver.", machineLabels, nil)
+	machineBalloonMaxDesc = prometheus.NewDesc("sox_machine_memory_balloon_max_bytes", "Memory the balloon driver may assign to the machine.", machineLabels, nil)
+	diskReadBytesDesc     = prometheus.NewDesc("sox_machine_disk_read_bytes_total", "Bytes read from the disks of the machine.", diskLabels, nil)
+	diskWriteBytesDesc    = prometheus.NewDesc("sox_machine_disk_write_bytes_total", "Bytes written to the disks of the machine.", diskLabels, nil)
+	diskReadsDesc         = prometheus.NewDesc("sox_machine_disk_read_requests_total", "Read requests to the disks of the machine.", diskLabels, nil)
+	diskWritesDesc        = prometheus.NewDesc("sox_machine_disk_write_requests_total", "Write requests to the disks of the machine.", diskLabels, nil)
+	rxBytesDesc           = prometheus.NewDesc("sox_machine_network_receive_bytes_total", "Bytes received by the interfaces of the machine.", interfaceLabels, nil)
+	txBytesDesc           = prometheus.NewDesc("sox_machine_network_transmit_bytes_total", "Bytes transmitted by the interfaces of the machine.", interfaceLabels, nil)
+	rxPacketsDesc         = prometheus.NewDesc("sox_machine_network_receive_packets_total", "Packets received by the interfaces of the machine.", interfaceLabels, nil)
+	txPacketsDesc         = prometheus.NewDesc("sox_machine_network_transmit_packets_total", "Packets transmitted by the interfaces of the machine.", interfaceLabels, nil)
+)
+
+// machineStats reads the bulk stats of all running machines when collected and labels them with
+// the machine name, project and network.
+type machineStats struct {
+	driver *Driver
+}
+
+func (machineStats) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		machineCPUDesc, machineBalloonDesc, machineBalloonMaxDesc,
+		diskReadBytesDesc, diskWriteBytesDesc, diskReadsDesc, diskWritesDesc,
+		rxBytesDesc, txBytesDesc, rxPacketsDesc, txPacketsDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (m machineStats) Collect(ch chan<- prometheus.Metric) {
+	driver := m.driver
+	stats, err := driver.lv.GetDomainStats()
+	if err != nil {
+		log.Println("collect machine stats:", err)
+		return
+	}
+	var machines []models.Machine
+	if err := driver.db.Preload("NetworkInterfaces.Network").Find(&machines).Error; err != nil {
+		log.Println("collect machine stats: retrieve machines:", err)
+		return
+	}
+	var projects []models.Project
+	if err := driver.db.Find(&projects).Error; err != nil {
+		log.Println("collect machine stats: retrieve projects:", err)
+		return
+	}
+	projectNames := make(map[string]string)
+	for _, p := range projects {
+		projectNames[p.ID] = p.Name
+	}
+	machinesByID := make(map[string]*models.Machine)
+	networksByHwAddr := make(map[string]string)
+	for i := range machines {
+		machinesByID[machines[i].ID] = &machines[i]
+		for _, iface := range machines[i].NetworkInterfaces {
+			networksByHwAddr[iface.HwAddr] = iface.Network.Name
+		}
+	}
+
+	for _, s := range stats {
+		// Domains not managed by sox are skipped
+		machine, ok := machinesByID[s.ID]
+		if !ok {
+			continue
+		}
+		project := projectNames[machine.ProjectID]
+		ch <- prometheus.MustNewConstMetric(machineCPUDesc, prometheus.CounterValue, s.CPUTime.Seconds(), machine.Name, project)
+		ch <- prometheus.MustNewConstMetric(machineBalloonDesc, prometheus.GaugeValue, float64(s.BalloonCurrent*1024), machine.Name, project)
+		ch <- prometheus.MustNewConstMetric(machineBalloonMaxDesc, prometheus.GaugeValue, float64(s.BalloonMaximum*1024), machine.Name, project)
+		for _, disk := range s.Disks {
+			ch <- prometheus.MustNewConstMetric(diskReadBytesDesc, prometheus.CounterValue, float64(disk.ReadBytes), machine.Name, project, disk.Device)
+			ch <- prometheus.MustNewConstMetric(diskWriteBytesDesc, prometheus.CounterValue, float64(disk.WriteBytes), machine.Name, project, disk.Device)
+			ch <- prometheus.MustNewConstMetric(diskReadsDesc, prometheus.CounterValue, float64(disk.ReadRequests), machine.Name, project, disk.Device)
+			ch <- prometheus.MustNewConstMetric(diskWritesDesc, prometheus.CounterValue, float64(disk.WriteRequests), machine.Name, project, disk.Device)
+		}
+		for _, iface := range s.Interfaces {
+			network := networksByHwAddr[iface.HwAddr]
+			ch <- prometheus.MustNewConstMetric(rxBytesDesc, prometheus.CounterValue, float64(iface.RxBytes), machine.Name, project, network, iface.Device)
+			ch <- prometheus.MustNewConstMetric(txBytesDesc, prometheus.CounterValue, float64(iface.TxBytes), machine.Name, project, network, iface.Device)
+			ch <- prometheus.MustNewConstMetric(rxPacketsDesc, prometheus.CounterValue, float64(iface.RxPackets), machine.Name, project, network, iface.Device)
+			ch <- prometheus.MustNewConstMetric(txPacketsDesc, prometheus.CounterValue, float64(iface.TxPackets), machine.Name, project, network, iface.Device)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// diskChunkSize is the amount of disk data sent per message.
+const diskChunkSize = 1 << 20
+
+func (driver *Driver) ExportMachine(ctx context.Context, request *api.ExportMachineRequest) (*api.ExportMachineResponse, error) {
+	var machine models.Machine
+	if err := driver.db.Preload("SSHKeys").Preload("NetworkInterfaces.SecurityGroups").Where("id = ?", request.Id).First(&machine).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve machine: %v", err)
+	}
+	// Port forwards and floating IPs are bound to the host addresses of this node
+	var forwards, fips int64
+	if err := driver.db.Model(&models.PortForward{}).Where("machine_id = ?", machine.ID).Count(&forwards).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "count port forwards: %v", err)
+	}
+	if err := driver.db.Model(&models.FloatingIP{}).
+		Where("network_interface_id IN (?)", driver.db.Model(&models.NetworkInterface{}).Select("id").Where("machine_id = ?", machine.ID)).
+		Count(&fips).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "count floating ips: %v", err)
+	}
+	if forwards > 0 || fips > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "machine %s has port forwards or floating ips", machine.Name)
+	}
+	var image models.Image
+	if err := driver.db.Where("id = ?", machine.ImageID).First(&image).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve image: %v", err)
+	}
+	ifaces := make([]*api.NetworkInterface, len(machine.NetworkInterfaces))
+	for i, iface := range machine.NetworkInterfaces {
+		ifaces[i] = &api.NetworkInterface{
+			NetworkId:     iface.NetworkID,
+			IpV4:          iface.IPv4,
+			IpV6:          iface.IPv6,
+			Qos:           bandwidthToApi(iface.QoS),
+			MacAddress:    iface.HwAddr,
+			PendingReboot: iface.PendingReboot,
+		}
+		for _, group := range iface.SecurityGroups {
+			ifaces[i].SecurityGroupIds = append(ifaces[i].SecurityGroupIds, group.ID)
+		}
+	}
+	sshKeys := make([]*api.SSHKey, len(machine.SSHKeys))
+	sshKeyIds := make([]string, len(machine.SSHKeys))
+	for i, key := range machine.SSHKeys {
+		sshKeys[i] = &api.SSHKey{
+			Id:        key.ID,
+			Name:      key.Name,
+			Pubkey:    key.Pubkey,
+			ProjectId: key.ProjectID,
+		}
+		sshKeyIds[i] = key.ID
+	}
+	return &api.ExportMachineResponse{
+		Machine: &api.Machine{
+			Id:   machine.ID,
+			Name: machine.Name,
+			Specs: &api.Machine_Specs{
+				Cpus:   machine.Specs.CPUs,
+				Memory: machine.Specs.Memory,
+				Disk:   machine.Specs.Disk,
+			},
+			Networks:  ifaces,
+			ImageId:   machine.ImageID,
+			SshKeyIds: sshKeyIds,
+			User:      machine.User,
+			Node:      driver.nodeName,
+			ProjectId: machine.ProjectID,
+		},
+		SshKeys: sshKeys,
+		Image: &api.Image{
+			Id:        image.ID,
+			Name:      image.Name,
+			System:    api.Image_OS(api.Image_OS_value[image.OS]),
+			ProjectId: image.ProjectID,
+			Public:    image.Public,
+		},
+	}, nil
+}
+
+func (driver *Driver) ReadMachineDisk(request *api.ReadMachineDiskRequest, stream api.Sox_ReadMachineDiskServer) error {
+	var machine models.Machine
+	if err := driver.db.Where("id = ?", request.Id).First(&machine).Error; err != nil {
+		return status.Errorf(codes.NotFound, "retrieve machine: %v", err)
+	}
+	// The disk is only consistent while the machine is not running
+	state, err := driver.lv.GetMachineState(machine.ID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "get machine state: %v", err)
+	}
+	if state == models.StateRunning {
+		return status.Errorf(codes.FailedPrecondition, "machine %s is running", machine.Name)
+	}
+	disk, err := driver.lv.OpenMachineDisk(&machine)
+	if err != nil {
+		return status.Errorf(codes.Internal, "open disk: %v", err)
+	}
+	defer disk.Close()
+	return sendChunks(disk, func(data []byte) error {
+		return stream.Send(&api.ReadMachineDiskResponse{Data: data})
+	})
+}
+
+func (driver *Driver) ReadImage(request *api.ReadImageRequest, stream api.Sox_ReadImageServer) error {
+	var image models.Image
+	if err := driver.db.Where("id = ?", request.Id).First(&image).Error; err != nil {
+		return status.Errorf(codes.NotFound, "retrieve image: %v", err)
+	}
+	file, err := driver.lv.OpenImage(&image)
+	if err != nil {
+		return status.Errorf(codes.Internal, "open image: %v", err)
+	}
+	defer file.Close()
+	return sendChunks(file, func(data []byte) error {
+		return stream.Send(&api.ReadImageResponse{Data: data})
+	})
+}
+
+// sendChunks streams the reader in chunks of diskChunkSize.
+func sendChunks(r io.Reader, send func(data []byte) error) error {
+	buf := make([]byte, diskChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if err := send(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return status.Errorf(codes.Internal, "read: %v", err)
+		}
+	}
+}
+
+// chunkReader turns a stream of chunks into a reader.
+type chunkReader struct {
+	recv func() ([]byte, error)
+	buf  []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		data, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// dialNode connects to another node of the cluster.
+func (driver *Driver) dialNode(ctx context.Context, address string) (*grpc.ClientConn, error) {
+	conn, err := grpc.DialContext(ctx, address, driver.dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("dial node %s: %w", address, err)
+	}
+	return conn, nil
+}
+
+// onBehalf names the caller of the request in the metadata of requests to other nodes.
+// Tokens reach other nodes only with the next identity sync of the aggregator, so the
+// certificate of this node vouches for the caller instead.
+func onBehalf(ctx context.Context) context.Context {
+	c := callerFrom(ctx)
+	if c == nil {
+		return ctx
+	}
+	return auth.NewOutgoingContext(ctx, auth.Identity{User: c.user.Name})
+}
+
+// importImage copies the image from the source node unless it exists already.
+func (driver *Driver) importImage(ctx context.Context, apiImage *api.Image, source string) (models.Image, error) {
+	var image models.Image
+	if err := driver.db.Where("id = ?", apiImage.GetId()).Limit(1).Find(&image).Error; err != nil {
+		return image, status.Errorf(codes.Internal, "retrieve image: %v", err)
+	}
+	if image.ID != "" {
+		return image, nil
+	}
+	image = models.Image{
+		ID:        apiImage.Id,
+		Name:      apiImage.Name,
+		ProjectID: apiImage.ProjectId,
+		Public:    apiImage.Public,
+		OS:        apiImage.System.String(),
+		Path:      driver.lv.ImagePath(apiImage.Id),
+	}
+	conn, err := driver.dialNode(ctx, source)
+	if err != nil {
+		return image, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	defer conn.Close()
+	stream, err := api.NewSoxClient(conn).ReadImage(onBehalf(ctx), &api.ReadImageRequest{
+		Id: image.ID,
+	})
+	if err != nil {
+		return image, status.Errorf(codes.Internal, "read image: %v", err)
+	}
+	if err := driver.lv.ImportImage(&image, &chunkReader{recv: func() ([]byte, error) {
+		resp, err := stream.Recv()
+		return resp.GetData(), err
+	}}); err != nil {
+		return image, status.Errorf(codes.Internal, "import image: %v", err)
+	}
+	if err := driver.db.Create(&image).Error; err != nil {
+		return image, status.Errorf(codes.AlreadyExists, "create image record: %v", err)
+	}
+	driver.recordActivity(ctx, api.Activity_IMAGE_CREATED, image.ID)
+	return image, nil
+}
+
+// importInterface rebuilds an exported interface, keeping its addresses and MAC.
+func (driver *Driver) importInterface(apiIface *api.NetworkInterface) (models.NetworkInterface, error) {
+	var network models.Network
+	if err := driver.db.Where("id = ?", apiIface.NetworkId).First(&network).Error; err != nil {
+		return models.NetworkInterface{}, status.Errorf(codes.FailedPrecondition, "retrieve network: %v", err)
+	}
+	var taken int64
+	if err := driver.db.Model(&models.NetworkInterface{}).Where("network_id = ? AND ipv4 = ?", network.ID, apiIface.IpV4).Count(&taken).Error; err != nil {
+		return models.NetworkInterface{}, status.Errorf(codes.Internal, "check address: %v", err)
+	}
+	if taken > 0 {
+		return models.NetworkInterface{}, status.Errorf(codes.AlreadyExists, "address %s is already used in network %s", apiIface.IpV4, network.Name)
+	}
+	qos, err := bandwidthFromApi(apiIface.Qos)
+	if err != nil {
+		return models.NetworkInterface{}, status.Errorf(codes.InvalidArgument, "invalid qos: %v", err)
+	}
+	// Dropping security groups would open up the machine, so all of them have to exist
+	securityGroups := make([]models.SecurityGroup, len(apiIface.SecurityGroupIds))
+	for i := range apiIface.SecurityGroupIds {
+		if err := driver.db.Where("id = ?", apiIface.SecurityGroupIds[i]).First(&securityGroups[i]).Error; err != nil {
+			return models.NetworkInterface{}, status.Errorf(codes.FailedPrecondition, "retrieve security group: %v", err)
+		}
+	}
+	return models.NetworkInterface{
+		Network:        network,
+		IPv4:           apiIface.IpV4,
+		IPv6:           apiIface.IpV6,
+		HwAddr:         apiIface.MacAddress,
+		SecurityGroups: securityGroups,
+		QoS:            qos,
+		PendingReboot:  apiIface.PendingReboot,
+	}, nil
+}
+
+func (driver *Driver) ImportMachine(ctx context.Context, request *api.ImportMachineRequest) (*api.ImportMachineResponse, error) {
+	export := request.Export
+	if export.GetMachine() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "machine must be given")
+	}
+	if cordoned, err := driver.cordoned(); err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	} else if cordoned {
+		return nil, status.Errorf(codes.FailedPrecondition, "node %s is cordoned", driver.nodeName)
+	}
+	if err := driver.db.Where("id = ?", export.Machine.ProjectId).First(&models.Project{}).Error; err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "retrieve project: %v", err)
+	}
+	image, err := driver.importImage(ctx, export.Image, request.SourceAddress)
+	if err != nil {
+		return nil, err
+	}
+	// Keys are copied over as they are only used for the cloud-init config
+	sshKeys := make([]models.SSHKey, len(export.SshKeys))
+	for i, key := range export.SshKeys {
+		sshKeys[i] = models.SSHKey{
+			ID:        key.Id,
+			Name:      key.Name,
+			ProjectID: key.ProjectId,
+			Pubkey:    key.Pubkey,
+		}
+		if err := driver.db.Where("id = ?", key.Id).FirstOrCreate(&sshKeys[i]).Error; err != nil {
+			return nil, status.Errorf(codes.Internal, "create ssh key %s: %v", key.Name, err)
+		}
+	}
+	ifaces := make([]models.NetworkInterface, len(export.Machine.Networks))
+	for i := range export.Machine.Networks {
+		iface, err := driver.importInterface(export.Machine.Networks[i])
+		if err != nil {
+			return nil, err
+		}
+		ifaces[i] = iface
+	}
+	machine := models.Machine{
+		ID:        export.Machine.Id,
+		Name:      export.Machine.Name,
+		ProjectID: export.Machine.ProjectId,
+		User:      export.Machine.User,
+		Image:     image,
+		Specs: models.Specs{
+			CPUs:   export.Machine.Specs.GetCpus(),
+			Memory: export.Machine.Specs.GetMemory(),
+			Disk:   export.Machine.Specs.GetDisk(),
+		},
+		SSHKeys:           sshKeys,
+		NetworkInterfaces: ifaces,
+	}
+	// Imports are not charged to the quota of the project, but have to fit on the host
+	driver.quotaMu.Lock()
+	err = driver.db.Transaction(func(tx *gorm.DB) error {
+		if err := driver.checkCapacity(tx, machine.Specs); err != nil {
+			return err
+		}
+		if err := tx.Create(&machine).Error; err != nil {
+			return status.Errorf(codes.AlreadyExists, "create machine record: %v", err)
+		}
+		return nil
+	})
+	driver.quotaMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	log.Println("created machine record", machine.ID, "for import from", request.SourceAddress)
+	// Live migrations define the domain themselves
+	var domXml string
+	if request.Live {
+		domXml, err = driver.lv.PrepareMachine(&machine, request.CopyStorage)
+	} else {
+		err = driver.copyMachine(ctx, &machine, request.SourceAddress, request.Start)
+	}
+	if err != nil {
+		driver.lv.ReleaseMachine(&machine, request.CopyStorage || !request.Live)
+		driver.db.Select("NetworkInterfaces").Delete(&machine)
+		return nil, status.Errorf(codes.Internal, "import machine: %v", err)
+	}
+	driver.zone.Add(&machine)
+	if err := driver.applySecurityGroups(); err != nil {
+		return nil, status.Errorf(codes.Internal, "apply security groups: %v", err)
+	}
+	log.Println("imported machine instance", machine.ID)
+	return &api.ImportMachineResponse{
+		DomainXml: domXml,
+	}, nil
+}
+
+// copyMachine reads the machine disk from the source node and defines the machine on top of it.
+func (driver *Driver) copyMachine(ctx context.Context, machine *models.Machine, source string, start bool) error {
+	conn, err := driver.dialNode(ctx, source)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stream, err := api.NewSoxClient(conn).ReadMachineDisk(onBehalf(ctx), &api.ReadMachineDiskRequest{
+		Id: machine.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("read disk: %w", err)
+	}
+	return driver.lv.ImportMachine(machine, &chunkReader{recv: func() ([]byte, error) {
+		resp, err := stream.Recv()
+		return resp.GetData(), err
+	}}, start)
+}
+
+func (driver *Driver) SendMachine(ctx context.Context, request *api.SendMachineRequest) (*api.SendMachineResponse, error) {
+	machine, err := driver.reloadMachine(request.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve machine: %v", err)
+	}
+	if err := driver.lv.MigrateMachine(machine, request.DestinationUri, request.DomainXml, request.CopyStorage); err != nil {
+		return nil, status.Errorf(codes.Internal, "migrate machine: %v", err)
+	}
+	// The machine runs on the destination now, only forget about it here
+	driver.lv.ReleaseMachine(machine, request.CopyStorage)
+	if err := driver.removeMachineRecord(machine); err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return &api.SendMachineResponse{}, nil
+}
+
+// AbortImport drops a machine prepared for a live migration which did not happen. Unlike DeleteMachine,
+// it keeps disks shared with the source node, where the machine is still running.
+func (driver *Driver) AbortImport(ctx context.Context, request *api.AbortImportRequest) (*api.AbortImportResponse, error) {
+	machine, err := driver.reloadMachine(request.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve machine: %v", err)
+	}
+	// A domain means the migration went through after all
+	if _, err := driver.lv.GetMachineState(machine.ID); err == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "machine %s is running on node %s", machine.ID, driver.nodeName)
+	}
+	driver.lv.ReleaseMachine(machine, request.CopyStorage)
+	if err := driver.removeMachineRecord(machine); err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	log.Println("aborted import of machine", machine.ID)
+	return &api.AbortImportResponse{}, nil
+}
+
+func (driver *Driver) FlushForwarding(ctx context.Context, request *api.FlushForwardingRequest) (*api.FlushForwardingResponse, error) {
+	if err := driver.lv.FlushForwarding(request.MacAddresses); err != nil {
+		return nil, status.Errorf(codes.Internal, "flush forwarding: %v", err)
+	}
+	return &api.FlushForwardingResponse{}, nil
+}
@@ -2,7 +2,9 @@ package models
 
 import (
 	"fmt"
+	"net"
 	"path/filepath"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -18,8 +20,9 @@ const (
 )
 
 type Machine struct {
-	ID   string `gorm:"primaryKey"`
-	Name string `gorm:"uniqueIndex"`
+	ID        string `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex"`
+	ProjectID string `gorm:"index"`
 
 	ImageID string
 	Image   Image
@@ -49,23 +52,41 @@ func (s Specs) String() string {
 }
 
 type Image struct {
-	ID   string `gorm:"primaryKey"`
-	Name string `gorm:"uniqueIndex"`
-	OS   string
-	Path string
+	ID        string `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex"`
+	ProjectID string `gorm:"index"`
+	// Public images can be used by all projects.
+	Public bool
+	OS     string
+	Path   string
 }
 
 type SSHKey struct {
-	ID     string `gorm:"primaryKey"`
-	Name   string `gorm:"uniqueIndex"`
-	Pubkey string
+	ID        string `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex"`
+	ProjectID string `gorm:"index"`
+	Pubkey    string
 }
 
 type Activity struct {
 	gorm.Model
 
 	Type    string
-	Subject string
+	Subject string `gorm:"index"`
+	// Kind of the subject, like machine or network.
+	Kind string
+	// ProjectID is the project owning the subject, empty if it is not owned by a project.
+	ProjectID string `gorm:"index"`
+	// Actor is the name of the user the activity was caused by.
+	Actor string
+	// SourceAddress is the IP address of the client.
+	SourceAddress string
+	// Method is the gRPC method of the request.
+	Method string
+	// Details holds the request parameters as JSON with secrets redacted.
+	Details string
+	// Error is the error the request failed with, empty if it succeeded.
+	Error string
 }
 
 type NetworkInterface struct {
@@ -79,11 +100,50 @@ type NetworkInterface struct {
 	IPv6 string
 
 	HwAddr string
+
+	SecurityGroups []SecurityGroup `gorm:"many2many:network_interface_security_groups"`
+
+	QoS Bandwidth `gorm:"embedded;embeddedPrefix:qos_"`
+
+	// PendingReboot is set for interfaces attached to a running machine until the guest boots
+	// again, cloud-init only applies the network config on boot.
+	PendingReboot bool
+}
+
+// EffectiveQoS returns the interface limits, falling back to the network defaults per direction.
+// The network of the interface has to be loaded.
+func (i *NetworkInterface) EffectiveQoS() Bandwidth {
+	qos := i.QoS
+	if !qos.Inbound.IsSet() {
+		qos.Inbound = i.Network.DefaultQoS.Inbound
+	}
+	if !qos.Outbound.IsSet() {
+		qos.Outbound = i.Network.DefaultQoS.Outbound
+	}
+	return qos
+}
+
+// BandwidthLimit is a traffic shaping limit. Rates are in KiB/s, the burst in KiB.
+// There is no packet rate, libvirt can not shape it and would remove our own tc rules.
+type BandwidthLimit struct {
+	Average uint32
+	Peak    uint32
+	Burst   uint32
+}
+
+func (l BandwidthLimit) IsSet() bool {
+	return l.Average > 0
+}
+
+type Bandwidth struct {
+	Inbound  BandwidthLimit `gorm:"embedded;embeddedPrefix:inbound_"`
+	Outbound BandwidthLimit `gorm:"embedded;embeddedPrefix:outbound_"`
 }
 
 type Network struct {
-	ID   string `gorm:"primaryKey"`
-	Name string `gorm:"uniqueIndex"`
+	ID        string `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex"`
+	ProjectID string `gorm:"index"`
 
 	IPv4 NetworkSpec `gorm:"embedded;embeddedPrefix:ipv4_"`
 	IPv6 NetworkSpec `gorm:"embedded;embeddedPrefix:ipv6_"`
@@ -91,6 +151,12 @@ type Network struct {
 	Nameservers   string
 	SearchDomains string
 	BridgeID      uint32
+	MTU           uint32
+	// Mode holds the API enum name.
+	Mode           string
+	PhysicalDevice string
+
+	DefaultQoS Bandwidth `gorm:"embedded;embeddedPrefix:qos_"`
 }
 
 func (n *Network) NetlinkVxlan() string {
@@ -102,17 +168,229 @@ func (n *Network) NetlinkVxlanId() int {
 }
 
 func (n *Network) NetlinkBridge() string {
-	if n.BridgeID != 0 {
+	switch {
+	case n.Mode == NetworkModeBridged:
+		return fmt.Sprintf("br-%s", n.PhysicalDevice)
+	case n.BridgeID != 0:
 		return fmt.Sprintf("vxbr-%d", n.BridgeID)
 	}
 	return fmt.Sprintf("natbr-%s", n.Name)
 }
 
+// IsBridge reports whether guests attach to a bridge managed by sox instead of a libvirt network.
 func (n *Network) IsBridge() bool {
-	return n.BridgeID != 0
+	return n.BridgeID != 0 || n.Mode == NetworkModeBridged
 }
 
+// HasHostAddress reports whether the host takes part in the network.
+func (n *Network) HasHostAddress() bool {
+	return n.Mode != NetworkModeIsolated && n.Mode != NetworkModeBridged
+}
+
+// IsForwarded reports whether the host forwards traffic between the network and the outside.
+func (n *Network) IsForwarded() bool {
+	return n.Mode == NetworkModeNAT || n.Mode == NetworkModeRouted
+}
+
+// HostIPv4 returns the address the host owns on the network. Libvirt networks use their
+// gateway, VXLAN networks carry the base address of their subnet.
+func (n *Network) HostIPv4() net.IP {
+	if !n.HasHostAddress() {
+		return nil
+	}
+	if !n.IsBridge() {
+		return net.ParseIP(n.IPv4.Gateway).To4()
+	}
+	ip, _, err := net.ParseCIDR(n.IPv4.Subnet)
+	if err != nil {
+		return nil
+	}
+	return ip.To4()
+}
+
+// HostIPv6 returns the IPv6 address the host owns on the network, picked like HostIPv4.
+// Networks without IPv6 subnet have none.
+func (n *Network) HostIPv6() net.IP {
+	if !n.HasHostAddress() || n.IPv6.Subnet == "" {
+		return nil
+	}
+	if !n.IsBridge() {
+		return net.ParseIP(n.IPv6.Gateway)
+	}
+	ip, _, err := net.ParseCIDR(n.IPv6.Subnet)
+	if err != nil {
+		return nil
+	}
+	return ip
+}
+
+// Network modes, holding the API enum names.
+const (
+	NetworkModeNAT      = "NAT"
+	NetworkModeRouted   = "ROUTED"
+	NetworkModeIsolated = "ISOLATED"
+	NetworkModeBridged  = "BRIDGED"
+	NetworkModeVXLAN    = "VXLAN"
+)
+
 type NetworkSpec struct {
 	Subnet  string
 	Gateway string
 }
+
+type SecurityGroup struct {
+	ID        string `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex"`
+	ProjectID string `gorm:"index"`
+
+	Rules []SecurityGroupRule `gorm:"foreignkey:security_group_id"`
+}
+
+type SecurityGroupRule struct {
+	ID int64 `gorm:"primaryKey"`
+
+	SecurityGroupID string
+	// Direction and Protocol hold the API enum names.
+	Direction string
+	Protocol  string
+	PortMin   uint32
+	PortMax   uint32
+
+	CIDR          string
+	RemoteGroupID string
+}
+
+type PortForward struct {
+	ID        string `gorm:"primaryKey"`
+	ProjectID string `gorm:"index"`
+
+	MachineID          string
+	NetworkInterfaceID int64
+	NetworkInterface   NetworkInterface
+
+	HostIP   string `gorm:"index:idx_host_port,unique"`
+	HostPort uint32 `gorm:"index:idx_host_port,unique"`
+	// Protocol holds the API enum name.
+	Protocol    string `gorm:"index:idx_host_port,unique"`
+	MachinePort uint32
+}
+
+type FloatingIP struct {
+	ID        string `gorm:"primaryKey"`
+	Address   string `gorm:"uniqueIndex"`
+	ProjectID string `gorm:"index"`
+
+	// NetworkInterface is nil as long as the address is not associated.
+	NetworkInterfaceID *int64
+	NetworkInterface   *NetworkInterface
+}
+
+type VPNPeer struct {
+	ID        string `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex"`
+	PublicKey string `gorm:"uniqueIndex"`
+	// Address is the tunnel address of the peer as host route.
+	Address string `gorm:"uniqueIndex"`
+
+	Networks []Network `gorm:"many2many:vpn_peer_networks"`
+}
+
+// NodeState keeps the scheduling state of the node across restarts.
+type NodeState struct {
+	Name     string `gorm:"primaryKey"`
+	Cordoned bool
+}
+
+// IdentityRevision counts the changes to users, projects, memberships, quotas and tokens. Aggregators
+// replicate the records of the node with the highest revision to all other nodes.
+type IdentityRevision struct {
+	ID       int `gorm:"primaryKey"`
+	Revision int64
+}
+
+// The default project and its admin are created on first start,
+// resources created before projects existed belong to the default project.
+const (
+	DefaultProjectID = "f705a6b2-10b4-447d-aaf1-c9be7eb94d5c"
+	DefaultUserID    = "a30752b5-d4a6-47e6-8ece-42c8b4a6e7bf"
+)
+
+// User is a caller of the API. Admins may act in all projects.
+type User struct {
+	ID    string `gorm:"primaryKey"`
+	Name  string `gorm:"uniqueIndex"`
+	Admin bool
+}
+
+// Project owns machines, networks, SSH keys, images, security groups and floating IPs.
+type Project struct {
+	ID   string `gorm:"primaryKey"`
+	Name string `gorm:"uniqueIndex"`
+}
+
+// Membership grants a user a role within a project.
+type Membership struct {
+	UserID    string `gorm:"primaryKey"`
+	User      User
+	ProjectID string `gorm:"primaryKey"`
+	// Role holds the API enum name.
+	Role string
+}
+
+// Token authenticates API requests as its user. Only the hash of the secret is stored.
+type Token struct {
+	ID     string `gorm:"primaryKey"`
+	Name   string
+	UserID string `gorm:"index"`
+	Hash   string `gorm:"uniqueIndex"`
+	// Scopes holds comma-separated API enum names, empty if not limited.
+	Scopes    string
+	CreatedAt time.Time
+	// ExpiresAt is nil for tokens without expiry.
+	ExpiresAt *time.Time
+}
+
+// Quota limits the resources of a project, limits of zero are not enforced.
+type Quota struct {
+	ProjectID string `gorm:"primaryKey"`
+	CPUs      int64
+	// Memory in MB.
+	Memory int64
+	// Disk in GB.
+	Disk        int64
+	Machines    int64
+	FloatingIPs int64
+	Networks    int64
+}
+
+// Webhook posts the events of its project to a URL.
+type Webhook struct {
+	ID        string `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex"`
+	ProjectID string `gorm:"index"`
+	URL       string
+	// Secret is the key deliveries are signed with.
+	Secret string
+	// EventTypes, ActivityTypes and MachineStatuses hold comma-separated API enum names, empty to match all.
+	EventTypes      string
+	ActivityTypes   string
+	MachineStatuses string
+	CreatedAt       time.Time
+}
+
+// WebhookDelivery is an event queued for a webhook and the outcome of its attempts.
+type WebhookDelivery struct {
+	ID        uint   `gorm:"primaryKey"`
+	WebhookID string `gorm:"index"`
+	// Event holds the JSON encoded API event.
+	Event     string
+	EventType string
+	// Status holds the API enum name.
+	Status        string `gorm:"index"`
+	Attempts      int32
+	ResponseCode  int32
+	Error         string
+	CreatedAt     time.Time
+	LastAttemptAt *time.Time
+	NextAttemptAt time.Time `gorm:"index"`
+}
@@ -0,0 +1,288 @@
+package nat
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/lnsp/sox/driver/models"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// dnatChain holds the destination NAT rules, jumped to from PREROUTING and OUTPUT.
+	dnatChain = "SOX-DNAT"
+	// snatChain holds the source NAT rules of floating IPs, it precedes the libvirt masquerading.
+	snatChain = "SOX-SNAT"
+	// forwardChain accepts translated traffic before libvirt rejects new connections into NAT networks.
+	forwardChain = "SOX-FORWARD"
+)
+
+// Table programs the host firewall with the NAT rules of sox.
+type Table struct {
+	mu  sync.Mutex
+	ipt *iptables.IPTables
+	// device carries the floating addresses.
+	device string
+}
+
+// New creates the sox chains and hooks them into the builtin chains.
+// Floating addresses are assigned to the given device.
+func New(device string) (*Table, error) {
+	ipt, err := iptables.New()
+	if err != nil {
+		return nil, fmt.Errorf("init iptables: %w", err)
+	}
+	table := &Table{ipt: ipt, device: device}
+	if err := table.ensureChain("nat", dnatChain, "PREROUTING", "OUTPUT"); err != nil {
+		return nil, err
+	}
+	if err := table.ensureChain("nat", snatChain, "POSTROUTING"); err != nil {
+		return nil, err
+	}
+	if err := table.ensureChain("filter", forwardChain, "FORWARD"); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// ensureChain creates the chain if necessary and puts a jump to it on top of each parent.
+func (table *Table) ensureChain(tableName, chain string, parents ...string) error {
+	exists, err := table.ipt.ChainExists(tableName, chain)
+	if err != nil {
+		return fmt.Errorf("check chain %s: %w", chain, err)
+	}
+	if !exists {
+		if err := table.ipt.NewChain(tableName, chain); err != nil {
+			return fmt.Errorf("create chain %s: %w", chain, err)
+		}
+	}
+	for _, parent := range parents {
+		jump := []string{"-j", chain}
+		exists, err := table.ipt.Exists(tableName, parent, jump...)
+		if err != nil {
+			return fmt.Errorf("check jump from %s: %w", parent, err)
+		}
+		if exists {
+			continue
+		}
+		if err := table.ipt.Insert(tableName, parent, 1, jump...); err != nil {
+			return fmt.Errorf("insert jump from %s: %w", parent, err)
+		}
+	}
+	return nil
+}
+
+// Flush removes all rules from the sox chains.
+func (table *Table) Flush() error {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	if err := table.ipt.ClearChain("nat", dnatChain); err != nil {
+		return fmt.Errorf("clear chain %s: %w", dnatChain, err)
+	}
+	if err := table.ipt.ClearChain("nat", snatChain); err != nil {
+		return fmt.Errorf("clear chain %s: %w", snatChain, err)
+	}
+	if err := table.ipt.ClearChain("filter", forwardChain); err != nil {
+		return fmt.Errorf("clear chain %s: %w", forwardChain, err)
+	}
+	return nil
+}
+
+// portForwardRules returns the DNAT and forward rule of a port forward.
+// The network interface of the port forward has to be loaded.
+func portForwardRules(pf *models.PortForward) ([]string, []string, error) {
+	machineIP, _, err := net.ParseCIDR(pf.NetworkInterface.IPv4)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse machine address: %w", err)
+	}
+	protocol := strings.ToLower(pf.Protocol)
+	comment := []string{"-m", "comment", "--comment", "sox-pf-" + pf.ID}
+	dnat := []string{"-p", protocol}
+	if pf.HostIP != "" {
+		dnat = append(dnat, "-d", pf.HostIP)
+	} else {
+		dnat = append(dnat, "-m", "addrtype", "--dst-type", "LOCAL")
+	}
+	dnat = append(dnat,
+		"--dport", strconv.Itoa(int(pf.HostPort)),
+		"-j", "DNAT", "--to-destination", net.JoinHostPort(machineIP.String(), strconv.Itoa(int(pf.MachinePort))),
+	)
+	forward := []string{
+		"-p", protocol,
+		"-d", machineIP.String(),
+		"--dport", strconv.Itoa(int(pf.MachinePort)),
+		"-m", "conntrack", "--ctstate", "DNAT",
+		"-j", "ACCEPT",
+	}
+	return append(dnat, comment...), append(forward, comment...), nil
+}
+
+// AddPortForward programs the rules of a port forward.
+func (table *Table) AddPortForward(pf *models.PortForward) error {
+	dnat, forward, err := portForwardRules(pf)
+	if err != nil {
+		return err
+	}
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	if err := table.ipt.AppendUnique("nat", dnatChain, dnat...); err != nil {
+		return fmt.Errorf("append dnat rule: %w", err)
+	}
+	if err := table.ipt.AppendUnique("filter", forwardChain, forward...); err != nil {
+		return fmt.Errorf("append forward rule: %w", err)
+	}
+	log.Println("added port forward", pf.ID)
+	return nil
+}
+
+// RemovePortForward deletes the rules of a port forward.
+func (table *Table) RemovePortForward(pf *models.PortForward) error {
+	dnat, forward, err := portForwardRules(pf)
+	if err != nil {
+		return err
+	}
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	if err := table.ipt.DeleteIfExists("nat", dnatChain, dnat...); err != nil {
+		return fmt.Errorf("delete dnat rule: %w", err)
+	}
+	if err := table.ipt.DeleteIfExists("filter", forwardChain, forward...); err != nil {
+		return fmt.Errorf("delete forward rule: %w", err)
+	}
+	log.Println("removed port forward", pf.ID)
+	return nil
+}
+
+// floatingIPRules returns the DNAT, SNAT and forward rule of an associated floating IP.
+// The network interface of the floating IP and its network have to be loaded.
+func floatingIPRules(fip *models.FloatingIP) ([]string, []string, []string, error) {
+	if fip.NetworkInterface == nil {
+		return nil, nil, nil, fmt.Errorf("floating ip %s is not associated", fip.Address)
+	}
+	machineIP, _, err := net.ParseCIDR(fip.NetworkInterface.IPv4)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parse machine address: %w", err)
+	}
+	comment := []string{"-m", "comment", "--comment", "sox-fip-" + fip.ID}
+	dnat := []string{"-d", fip.Address, "-j", "DNAT", "--to-destination", machineIP.String()}
+	// Traffic within the private network keeps its source address
+	snat := []string{"-s", machineIP.String(), "!", "-d", fip.NetworkInterface.Network.IPv4.Subnet, "-j", "SNAT", "--to-source", fip.Address}
+	forward := []string{"-d", machineIP.String(), "-m", "conntrack", "--ctstate", "DNAT", "-j", "ACCEPT"}
+	return append(dnat, comment...), append(snat, comment...), append(forward, comment...), nil
+}
+
+// hostAddr returns the floating address as a host route on the floating device.
+func (table *Table) hostAddr(address string) (netlink.Link, *netlink.Addr, error) {
+	link, err := netlink.LinkByName(table.device)
+	if err != nil {
+		return nil, nil, fmt.Errorf("find device %s: %w", table.device, err)
+	}
+	addr, err := netlink.ParseAddr(address + "/32")
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse floating address: %w", err)
+	}
+	return link, addr, nil
+}
+
+// AddFloatingIP claims the floating address on the host and maps it 1:1 to the associated interface.
+func (table *Table) AddFloatingIP(fip *models.FloatingIP) error {
+	dnat, snat, forward, err := floatingIPRules(fip)
+	if err != nil {
+		return err
+	}
+	link, addr, err := table.hostAddr(fip.Address)
+	if err != nil {
+		return err
+	}
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	if err := netlink.AddrAdd(link, addr); err != nil && !errors.Is(err, unix.EEXIST) {
+		return fmt.Errorf("add floating address: %w", err)
+	}
+	if err := table.ipt.AppendUnique("nat", dnatChain, dnat...); err != nil {
+		return fmt.Errorf("append dnat rule: %w", err)
+	}
+	if err := table.ipt.AppendUnique("nat", snatChain, snat...); err != nil {
+		return fmt.Errorf("append snat rule: %w", err)
+	}
+	if err := table.ipt.AppendUnique("filter", forwardChain, forward...); err != nil {
+		return fmt.Errorf("append forward rule: %w", err)
+	}
+	log.Println("added floating ip", fip.Address, "for", fip.NetworkInterface.IPv4)
+	return nil
+}
+
+// RemoveFloatingIP deletes the mapping of a floating IP and releases the address on the host.
+func (table *Table) RemoveFloatingIP(fip *models.FloatingIP) error {
+	dnat, snat, forward, err := floatingIPRules(fip)
+	if err != nil {
+		return err
+	}
+	link, addr, err := table.hostAddr(fip.Address)
+	if err != nil {
+		return err
+	}
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	if err := table.ipt.DeleteIfExists("nat", dnatChain, dnat...); err != nil {
+		return fmt.Errorf("delete dnat rule: %w", err)
+	}
+	if err := table.ipt.DeleteIfExists("nat", snatChain, snat...); err != nil {
+		return fmt.Errorf("delete snat rule: %w", err)
+	}
+	if err := table.ipt.DeleteIfExists("filter", forwardChain, forward...); err != nil {
+		return fmt.Errorf("delete forward rule: %w", err)
+	}
+	if err := netlink.AddrDel(link, addr); err != nil && !errors.Is(err, unix.EADDRNOTAVAIL) {
+		return fmt.Errorf("delete floating address: %w", err)
+	}
+	log.Println("removed floating ip", fip.Address)
+	return nil
+}
+
+// vpnPeerRules returns the forward rules that let a VPN peer reach its networks.
+// The networks of the peer have to be loaded.
+func vpnPeerRules(peer *models.VPNPeer, device string) [][]string {
+	comment := []string{"-m", "comment", "--comment", "sox-vpn-" + peer.ID}
+	rules := make([][]string, 0, 2*len(peer.Networks))
+	for _, network := range peer.Networks {
+		rules = append(rules,
+			append([]string{"-i", device, "-s", peer.Address, "-d", network.IPv4.Subnet, "-j", "ACCEPT"}, comment...),
+			append([]string{"-o", device, "-s", network.IPv4.Subnet, "-d", peer.Address, "-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "ACCEPT"}, comment...),
+		)
+	}
+	return rules
+}
+
+// AddVPNPeer forwards traffic between the VPN device and the networks of the peer.
+func (table *Table) AddVPNPeer(peer *models.VPNPeer, device string) error {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	for _, rule := range vpnPeerRules(peer, device) {
+		if err := table.ipt.AppendUnique("filter", forwardChain, rule...); err != nil {
+			return fmt.Errorf("append forward rule: %w", err)
+		}
+	}
+	log.Println("added vpn peer routes", peer.ID)
+	return nil
+}
+
+// RemoveVPNPeer deletes the forward rules of a VPN peer.
+func (table *Table) RemoveVPNPeer(peer *models.VPNPeer, device string) error {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	for _, rule := range vpnPeerRules(peer, device) {
+		if err := table.ipt.DeleteIfExists("filter", forwardChain, rule...); err != nil {
+			return fmt.Errorf("delete forward rule: %w", err)
+		}
+	}
+	log.Println("removed vpn peer routes", peer.ID)
+	return nil
+}
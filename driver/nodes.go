@@ -0,0 +1,103 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NodeInfo describes the capacity of this node and the resources allocated by its machines.
+func (driver *Driver) NodeInfo() (*api.Node, error) {
+	host, err := driver.lv.GetHostInfo()
+	if err != nil {
+		return nil, fmt.Errorf("get host info: %w", err)
+	}
+	allocated, err := allocatedSpecs(driver.db)
+	if err != nil {
+		return nil, err
+	}
+	cordoned, err := driver.cordoned()
+	if err != nil {
+		return nil, err
+	}
+	var imageIds, networkIds []string
+	if err := driver.db.Model(&models.Image{}).Pluck("id", &imageIds).Error; err != nil {
+		return nil, fmt.Errorf("list images: %w", err)
+	}
+	if err := driver.db.Model(&models.Network{}).Pluck("id", &networkIds).Error; err != nil {
+		return nil, fmt.Errorf("list networks: %w", err)
+	}
+	return &api.Node{
+		Name:    driver.nodeName,
+		Address: driver.nodeAddress,
+		// Schedulers place machines up to the overcommitted capacity
+		Capacity:      specsToApi(driver.overcommit.limit(host.Capacity)),
+		Allocated:     specsToApi(allocated),
+		LastHeartbeat: timestamppb.Now(),
+		Status:        api.Node_READY,
+		ImageIds:      imageIds,
+		NetworkIds:    networkIds,
+		Labels:        driver.nodeLabels,
+		Cordoned:      cordoned,
+		MigrationUri:  driver.migrationURI,
+	}, nil
+}
+
+// ListNodes returns the node itself, as a standalone node is a cluster of one.
+func (driver *Driver) ListNodes(ctx context.Context, request *api.ListNodesRequest) (*api.ListNodesResponse, error) {
+	node, err := driver.NodeInfo()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get node info: %v", err)
+	}
+	return &api.ListNodesResponse{
+		Nodes: []*api.Node{node},
+	}, nil
+}
+
+// cordoned reports if the node has been cordoned.
+func (driver *Driver) cordoned() (bool, error) {
+	var state models.NodeState
+	if err := driver.db.Where("name = ?", driver.nodeName).Limit(1).Find(&state).Error; err != nil {
+		return false, fmt.Errorf("retrieve node state: %w", err)
+	}
+	return state.Cordoned, nil
+}
+
+func (driver *Driver) setCordoned(name string, cordoned bool) error {
+	if name != driver.nodeName {
+		return status.Errorf(codes.NotFound, "node %s not found", name)
+	}
+	if err := driver.db.Save(&models.NodeState{
+		Name:     driver.nodeName,
+		Cordoned: cordoned,
+	}).Error; err != nil {
+		return status.Errorf(codes.Internal, "save node state: %v", err)
+	}
+	return nil
+}
+
+func (driver *Driver) CordonNode(ctx context.Context, request *api.CordonNodeRequest) (*api.CordonNodeResponse, error) {
+	if err := driver.setCordoned(request.Name, true); err != nil {
+		return nil, err
+	}
+	log.Println("cordoned node", driver.nodeName)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_NODE_CORDONED, driver.nodeName)
+	return &api.CordonNodeResponse{}, nil
+}
+
+func (driver *Driver) UncordonNode(ctx context.Context, request *api.UncordonNodeRequest) (*api.UncordonNodeResponse, error) {
+	if err := driver.setCordoned(request.Name, false); err != nil {
+		return nil, err
+	}
+	log.Println("uncordoned node", driver.nodeName)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_NODE_UNCORDONED, driver.nodeName)
+	return &api.UncordonNodeResponse{}, nil
+}
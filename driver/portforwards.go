@@ -0,0 +1,164 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// restorePortForwards re-programs all port forwards, the host firewall does not survive reboots.
+func (driver *Driver) restorePortForwards() error {
+	var forwards []models.PortForward
+	if err := driver.db.Preload("NetworkInterface").Find(&forwards).Error; err != nil {
+		return fmt.Errorf("find port forwards: %w", err)
+	}
+	for i := range forwards {
+		if err := driver.nat.AddPortForward(&forwards[i]); err != nil {
+			return fmt.Errorf("add port forward %s: %w", forwards[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// deletePortForwards removes all port forwards of a machine.
+func (driver *Driver) deletePortForwards(machineID string) error {
+	var forwards []models.PortForward
+	if err := driver.db.Preload("NetworkInterface").Where("machine_id = ?", machineID).Find(&forwards).Error; err != nil {
+		return fmt.Errorf("find port forwards: %w", err)
+	}
+	for i := range forwards {
+		if err := driver.nat.RemovePortForward(&forwards[i]); err != nil {
+			return fmt.Errorf("remove port forward %s: %w", forwards[i].ID, err)
+		}
+		if err := driver.db.Delete(&forwards[i]).Error; err != nil {
+			return fmt.Errorf("delete port forward record: %w", err)
+		}
+	}
+	return nil
+}
+
+func (driver *Driver) CreatePortForward(ctx context.Context, request *api.CreatePortForwardRequest) (*api.CreatePortForwardResponse, error) {
+	if request.HostPort == 0 || request.HostPort > 65535 || request.MachinePort == 0 || request.MachinePort > 65535 {
+		return nil, status.Errorf(codes.InvalidArgument, "ports must be between 1 and 65535")
+	}
+	// Forwards on all addresses are stored without host IP
+	var hostIP string
+	if request.HostIp != "" {
+		ip := net.ParseIP(request.HostIp).To4()
+		if ip == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "host ip must be an IPv4 address")
+		}
+		if !ip.IsUnspecified() {
+			hostIP = ip.String()
+		}
+	}
+	protocol := request.Protocol
+	if protocol == api.PortForward_PROTOCOL_UNSPECIFIED {
+		protocol = api.PortForward_TCP
+	}
+	// Find interface on a NAT network
+	var machine models.Machine
+	if err := driver.db.Preload("NetworkInterfaces.Network").Where("id = ? OR name = ?", request.MachineId, request.MachineId).First(&machine).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve machine: %v", err)
+	}
+	var iface *models.NetworkInterface
+	for i := range machine.NetworkInterfaces {
+		if !machine.NetworkInterfaces[i].Network.IsForwarded() {
+			continue
+		}
+		if request.NetworkId == "" || request.NetworkId == machine.NetworkInterfaces[i].NetworkID {
+			iface = &machine.NetworkInterfaces[i]
+			break
+		}
+	}
+	if iface == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "machine has no interface on a matching NAT or routed network")
+	}
+	pf := models.PortForward{
+		ID:                 uuid.New().String(),
+		ProjectID:          machine.ProjectID,
+		MachineID:          machine.ID,
+		NetworkInterfaceID: iface.ID,
+		NetworkInterface:   *iface,
+		HostIP:             hostIP,
+		HostPort:           request.HostPort,
+		Protocol:           protocol.String(),
+		MachinePort:        request.MachinePort,
+	}
+	driver.natMu.Lock()
+	defer driver.natMu.Unlock()
+	// The unique index does not cover forwards on all addresses overlapping those on a single one
+	var conflicts int64
+	if err := driver.db.Model(&models.PortForward{}).
+		Where("host_port = ? AND protocol = ?", pf.HostPort, pf.Protocol).
+		Where("host_ip = ? OR host_ip = '' OR ? = ''", pf.HostIP, pf.HostIP).
+		Count(&conflicts).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve port forwards: %v", err)
+	}
+	if conflicts > 0 {
+		return nil, status.Errorf(codes.AlreadyExists, "host port %d/%s is already forwarded", pf.HostPort, pf.Protocol)
+	}
+	if err := driver.db.Omit("NetworkInterface").Create(&pf).Error; err != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "create port forward record: %v", err)
+	}
+	log.Println("created port forward record", pf.ID)
+	if err := driver.nat.AddPortForward(&pf); err != nil {
+		driver.db.Delete(&pf)
+		return nil, status.Errorf(codes.Internal, "add port forward: %v", err)
+	}
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_PORT_FORWARD_CREATED, pf.ID)
+	return &api.CreatePortForwardResponse{
+		Id: pf.ID,
+	}, nil
+}
+
+func (driver *Driver) ListPortForwards(ctx context.Context, request *api.ListPortForwardsRequest) (*api.ListPortForwardsResponse, error) {
+	forwards := []models.PortForward{}
+	query := scoped(ctx, driver.db).Preload("NetworkInterface")
+	if request.MachineId != "" {
+		query = query.Where("machine_id = ?", request.MachineId)
+	}
+	if err := query.Find(&forwards).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve port forwards: %v", err)
+	}
+	apiForwards := make([]*api.PortForward, len(forwards))
+	for i := range forwards {
+		apiForwards[i] = &api.PortForward{
+			Id:          forwards[i].ID,
+			MachineId:   forwards[i].MachineID,
+			NetworkId:   forwards[i].NetworkInterface.NetworkID,
+			HostIp:      forwards[i].HostIP,
+			HostPort:    forwards[i].HostPort,
+			MachinePort: forwards[i].MachinePort,
+			Protocol:    api.PortForward_Protocol(api.PortForward_Protocol_value[forwards[i].Protocol]),
+			ProjectId:   forwards[i].ProjectID,
+		}
+	}
+	return &api.ListPortForwardsResponse{
+		PortForwards: apiForwards,
+	}, nil
+}
+
+func (driver *Driver) DeletePortForward(ctx context.Context, request *api.DeletePortForwardRequest) (*api.DeletePortForwardResponse, error) {
+	var pf models.PortForward
+	if err := driver.db.Preload("NetworkInterface").Where("id = ?", request.Id).First(&pf).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve port forward: %v", err)
+	}
+	if err := driver.nat.RemovePortForward(&pf); err != nil {
+		return nil, status.Errorf(codes.Internal, "remove port forward: %v", err)
+	}
+	if err := driver.db.Delete(&pf).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete port forward record: %v", err)
+	}
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_PORT_FORWARD_DELETED, pf.ID)
+	return &api.DeletePortForwardResponse{}, nil
+}
@@ -0,0 +1,88 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func bandwidthLimitFromApi(limit *api.Bandwidth_Limit) (models.BandwidthLimit, error) {
+	if limit == nil {
+		return models.BandwidthLimit{}, nil
+	}
+	if limit.Average == 0 && (limit.Peak > 0 || limit.Burst > 0) {
+		return models.BandwidthLimit{}, fmt.Errorf("peak and burst require an average rate")
+	}
+	if limit.Peak > 0 && limit.Peak < limit.Average {
+		return models.BandwidthLimit{}, fmt.Errorf("peak rate %d is below average rate %d", limit.Peak, limit.Average)
+	}
+	return models.BandwidthLimit{
+		Average: limit.Average,
+		Peak:    limit.Peak,
+		Burst:   limit.Burst,
+	}, nil
+}
+
+// bandwidthFromApi validates and converts the QoS settings of a request.
+func bandwidthFromApi(qos *api.Bandwidth) (models.Bandwidth, error) {
+	var bandwidth models.Bandwidth
+	var err error
+	if bandwidth.Inbound, err = bandwidthLimitFromApi(qos.GetInbound()); err != nil {
+		return bandwidth, fmt.Errorf("inbound: %w", err)
+	}
+	if bandwidth.Outbound, err = bandwidthLimitFromApi(qos.GetOutbound()); err != nil {
+		return bandwidth, fmt.Errorf("outbound: %w", err)
+	}
+	return bandwidth, nil
+}
+
+func bandwidthToApi(qos models.Bandwidth) *api.Bandwidth {
+	return &api.Bandwidth{
+		Inbound: &api.Bandwidth_Limit{
+			Average: qos.Inbound.Average,
+			Peak:    qos.Inbound.Peak,
+			Burst:   qos.Inbound.Burst,
+		},
+		Outbound: &api.Bandwidth_Limit{
+			Average: qos.Outbound.Average,
+			Peak:    qos.Outbound.Peak,
+			Burst:   qos.Outbound.Burst,
+		},
+	}
+}
+
+func (driver *Driver) UpdateNetworkInterfaceQoS(ctx context.Context, request *api.UpdateNetworkInterfaceQoSRequest) (*api.UpdateNetworkInterfaceQoSResponse, error) {
+	qos, err := bandwidthFromApi(request.Qos)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid qos: %v", err)
+	}
+	machine, err := driver.reloadMachine(request.MachineId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve machine: %v", err)
+	}
+	var iface *models.NetworkInterface
+	for i := range machine.NetworkInterfaces {
+		if machine.NetworkInterfaces[i].NetworkID == request.NetworkId {
+			iface = &machine.NetworkInterfaces[i]
+		}
+	}
+	if iface == nil {
+		return nil, status.Errorf(codes.NotFound, "machine is not attached to network %s", request.NetworkId)
+	}
+	iface.QoS = qos
+	if err := driver.lv.UpdateNetworkInterface(machine, iface); err != nil {
+		return nil, status.Errorf(codes.Internal, "update interface: %v", err)
+	}
+	if err := driver.db.Omit("Network", "SecurityGroups").Save(iface).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "update interface record: %v", err)
+	}
+	log.Println("updated qos of interface", iface.ID, "of machine", machine.ID)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_NETWORK_INTERFACE_UPDATED, machine.ID)
+	return &api.UpdateNetworkInterfaceQoSResponse{}, nil
+}
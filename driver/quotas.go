@@ -0,0 +1,163 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// projectUsage returns the resources allocated by the project.
+func projectUsage(tx *gorm.DB, project string) (models.Quota, error) {
+	usage := models.Quota{ProjectID: project}
+	var machines []models.Machine
+	if err := tx.Where("project_id = ?", project).Find(&machines).Error; err != nil {
+		return usage, fmt.Errorf("retrieve machines: %w", err)
+	}
+	for _, machine := range machines {
+		// Machines created with negative specs before they were rejected must not free up quota
+		usage.CPUs += nonNegative(machine.Specs.CPUs)
+		usage.Memory += nonNegative(machine.Specs.Memory)
+		usage.Disk += nonNegative(machine.Specs.Disk)
+	}
+	usage.Machines = int64(len(machines))
+	if err := tx.Model(&models.FloatingIP{}).Where("project_id = ?", project).Count(&usage.FloatingIPs).Error; err != nil {
+		return usage, fmt.Errorf("count floating ips: %w", err)
+	}
+	if err := tx.Model(&models.Network{}).Where("project_id = ?", project).Count(&usage.Networks).Error; err != nil {
+		return usage, fmt.Errorf("count networks: %w", err)
+	}
+	return usage, nil
+}
+
+func nonNegative(value int64) int64 {
+	if value < 0 {
+		return 0
+	}
+	return value
+}
+
+// validateSpecs rejects missing and non-positive specs, which would lower the usage counted against quotas.
+func validateSpecs(specs *api.Machine_Specs) error {
+	if specs == nil {
+		return status.Errorf(codes.InvalidArgument, "specs must be given")
+	}
+	if specs.Cpus <= 0 || specs.Memory <= 0 || specs.Disk <= 0 {
+		return status.Errorf(codes.InvalidArgument, "specs must be positive")
+	}
+	return nil
+}
+
+// reserve checks that the project has room for the requested resources and creates them
+// within the same transaction. Requested machines also have to fit on the host. Concurrent
+// reservations are serialized, so they can not overshoot the quota together.
+func (driver *Driver) reserve(project string, requested models.Quota, create func(tx *gorm.DB) error) error {
+	driver.quotaMu.Lock()
+	defer driver.quotaMu.Unlock()
+	return driver.db.Transaction(func(tx *gorm.DB) error {
+		var quota models.Quota
+		if err := tx.Where("project_id = ?", project).Limit(1).Find(&quota).Error; err != nil {
+			return status.Errorf(codes.Internal, "retrieve quota: %v", err)
+		}
+		if quota.ProjectID != "" {
+			usage, err := projectUsage(tx, project)
+			if err != nil {
+				return status.Errorf(codes.Internal, "%v", err)
+			}
+			for _, limit := range []struct {
+				name                   string
+				quota, used, requested int64
+			}{
+				{"vCPUs", quota.CPUs, usage.CPUs, requested.CPUs},
+				{"MB memory", quota.Memory, usage.Memory, requested.Memory},
+				{"GB disk", quota.Disk, usage.Disk, requested.Disk},
+				{"machines", quota.Machines, usage.Machines, requested.Machines},
+				{"floating ips", quota.FloatingIPs, usage.FloatingIPs, requested.FloatingIPs},
+				{"networks", quota.Networks, usage.Networks, requested.Networks},
+			} {
+				if limit.quota > 0 && limit.requested > 0 && limit.used+limit.requested > limit.quota {
+					return status.Errorf(codes.ResourceExhausted, "quota of project %s exceeded: %d of %d %s in use, %d requested", project, limit.used, limit.quota, limit.name, limit.requested)
+				}
+			}
+		}
+		if requested.Machines > 0 {
+			if err := driver.checkCapacity(tx, models.Specs{
+				CPUs:   requested.CPUs,
+				Memory: requested.Memory,
+				Disk:   requested.Disk,
+			}); err != nil {
+				return err
+			}
+		}
+		return create(tx)
+	})
+}
+
+func quotaToApi(quota *models.Quota) *api.Quota {
+	return &api.Quota{
+		Cpus:        quota.CPUs,
+		Memory:      quota.Memory,
+		Disk:        quota.Disk,
+		Machines:    quota.Machines,
+		FloatingIps: quota.FloatingIPs,
+		Networks:    quota.Networks,
+	}
+}
+
+func (driver *Driver) GetQuotaUsage(ctx context.Context, request *api.GetQuotaUsageRequest) (*api.GetQuotaUsageResponse, error) {
+	project := projectFrom(ctx)
+	if request.ProjectId != "" {
+		var err error
+		if project, _, err = driver.projectOf(projectRef(request.ProjectId)); err != nil {
+			return nil, err
+		}
+	}
+	if project == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "project must be given")
+	}
+	var quota models.Quota
+	if err := driver.db.Where("project_id = ?", project).Limit(1).Find(&quota).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve quota: %v", err)
+	}
+	usage, err := projectUsage(driver.db, project)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return &api.GetQuotaUsageResponse{
+		ProjectId: project,
+		Quota:     quotaToApi(&quota),
+		Usage:     quotaToApi(&usage),
+	}, nil
+}
+
+func (driver *Driver) SetQuota(ctx context.Context, request *api.SetQuotaRequest) (*api.SetQuotaResponse, error) {
+	project, _, err := driver.projectOf(projectRef(request.ProjectId))
+	if err != nil {
+		return nil, err
+	}
+	limits := request.GetQuota()
+	quota := models.Quota{
+		ProjectID:   project,
+		CPUs:        limits.GetCpus(),
+		Memory:      limits.GetMemory(),
+		Disk:        limits.GetDisk(),
+		Machines:    limits.GetMachines(),
+		FloatingIPs: limits.GetFloatingIps(),
+		Networks:    limits.GetNetworks(),
+	}
+	if quota.CPUs < 0 || quota.Memory < 0 || quota.Disk < 0 || quota.Machines < 0 || quota.FloatingIPs < 0 || quota.Networks < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limits must not be negative")
+	}
+	if err := driver.db.Save(&quota).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "save quota: %v", err)
+	}
+	log.Println("updated quota of project", project)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_QUOTA_UPDATED, project)
+	return &api.SetQuotaResponse{}, nil
+}
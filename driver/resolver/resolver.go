@@ -0,0 +1,238 @@
+package resolver
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lnsp/sox/driver/models"
+	"github.com/miekg/dns"
+)
+
+// Domain is the top-level domain machine names are resolved under.
+const Domain = "sox."
+
+// recordTTL is the TTL of authoritative answers. Records change whenever machines
+// are created or deleted, so keep it short.
+const recordTTL = 30
+
+// forwardTimeout limits the time spent waiting for a single upstream.
+const forwardTimeout = 5 * time.Second
+
+type record struct {
+	name string
+	ip   net.IP
+}
+
+// Zone holds the address records of all machines.
+type Zone struct {
+	mu      sync.RWMutex
+	records map[string][]record
+	names   map[string][]net.IP
+	ptrs    map[string]string
+}
+
+// NewZone creates an empty zone.
+func NewZone() *Zone {
+	return &Zone{
+		records: make(map[string][]record),
+		names:   make(map[string][]net.IP),
+		ptrs:    make(map[string]string),
+	}
+}
+
+// Name returns the fully qualified domain name of a machine on a network.
+func Name(machine, network string) string {
+	return dns.Fqdn(strings.ToLower(machine + "." + network + "." + Domain))
+}
+
+// Add registers the addresses of all interfaces of the machine.
+// The network interfaces must have their network loaded.
+func (zone *Zone) Add(machine *models.Machine) {
+	var records []record
+	for _, iface := range machine.NetworkInterfaces {
+		name := Name(machine.Name, iface.Network.Name)
+		for _, addr := range []string{iface.IPv4, iface.IPv6} {
+			ip, _, err := net.ParseCIDR(addr)
+			if err != nil {
+				continue
+			}
+			records = append(records, record{name: name, ip: ip})
+		}
+	}
+	zone.mu.Lock()
+	defer zone.mu.Unlock()
+	zone.records[machine.ID] = records
+	zone.rebuild()
+}
+
+// Remove drops all records of the machine.
+func (zone *Zone) Remove(machineID string) {
+	zone.mu.Lock()
+	defer zone.mu.Unlock()
+	delete(zone.records, machineID)
+	zone.rebuild()
+}
+
+// rebuild regenerates the lookup indices, zone.mu has to be held.
+func (zone *Zone) rebuild() {
+	zone.names = make(map[string][]net.IP)
+	zone.ptrs = make(map[string]string)
+	for _, records := range zone.records {
+		for _, r := range records {
+			zone.names[r.name] = append(zone.names[r.name], r.ip)
+			if ptr, err := dns.ReverseAddr(r.ip.String()); err == nil {
+				zone.ptrs[ptr] = r.name
+			}
+		}
+	}
+}
+
+func (zone *Zone) lookup(name string) ([]net.IP, bool) {
+	zone.mu.RLock()
+	defer zone.mu.RUnlock()
+	ips, ok := zone.names[strings.ToLower(name)]
+	return ips, ok
+}
+
+func (zone *Zone) lookupPTR(name string) (string, bool) {
+	zone.mu.RLock()
+	defer zone.mu.RUnlock()
+	target, ok := zone.ptrs[strings.ToLower(name)]
+	return target, ok
+}
+
+// Server answers queries for the zone and forwards everything else to its upstreams.
+type Server struct {
+	zone      *Zone
+	upstreams []string
+
+	udp *dns.Server
+	tcp *dns.Server
+}
+
+// Listen starts a DNS server on the given address. If no upstreams are given,
+// queries outside of the zone are refused.
+func Listen(addr string, zone *Zone, upstreams []string) (*Server, error) {
+	server := &Server{
+		zone: zone,
+	}
+	for _, upstream := range upstreams {
+		if _, _, err := net.SplitHostPort(upstream); err != nil {
+			upstream = net.JoinHostPort(upstream, "53")
+		}
+		server.upstreams = append(server.upstreams, upstream)
+	}
+	packetConn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen udp: %w", err)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		packetConn.Close()
+		return nil, fmt.Errorf("listen tcp: %w", err)
+	}
+	server.udp = &dns.Server{PacketConn: packetConn, Handler: server}
+	server.tcp = &dns.Server{Listener: listener, Handler: server}
+	for _, s := range []*dns.Server{server.udp, server.tcp} {
+		go func(s *dns.Server) {
+			if err := s.ActivateAndServe(); err != nil {
+				log.Println("dns server on", addr, "stopped:", err)
+			}
+		}(s)
+	}
+	log.Println("started dns server on", addr)
+	return server, nil
+}
+
+// Close shuts down the server.
+func (server *Server) Close() error {
+	if err := server.udp.Shutdown(); err != nil {
+		return fmt.Errorf("shutdown udp: %w", err)
+	}
+	if err := server.tcp.Shutdown(); err != nil {
+		return fmt.Errorf("shutdown tcp: %w", err)
+	}
+	return nil
+}
+
+// answer tries to answer the question from the zone. It returns false if the
+// question is outside of the zone.
+func (server *Server) answer(reply *dns.Msg, question dns.Question) bool {
+	name := strings.ToLower(question.Name)
+	header := dns.RR_Header{Name: question.Name, Class: dns.ClassINET, Ttl: recordTTL}
+	if target, ok := server.zone.lookupPTR(name); ok {
+		if question.Qtype == dns.TypePTR || question.Qtype == dns.TypeANY {
+			header.Rrtype = dns.TypePTR
+			reply.Answer = append(reply.Answer, &dns.PTR{Hdr: header, Ptr: target})
+		}
+		return true
+	}
+	if !dns.IsSubDomain(Domain, name) {
+		return false
+	}
+	ips, ok := server.zone.lookup(name)
+	if !ok {
+		reply.Rcode = dns.RcodeNameError
+		return true
+	}
+	for _, ip := range ips {
+		if ip4 := ip.To4(); ip4 != nil && (question.Qtype == dns.TypeA || question.Qtype == dns.TypeANY) {
+			header.Rrtype = dns.TypeA
+			reply.Answer = append(reply.Answer, &dns.A{Hdr: header, A: ip4})
+		} else if ip4 == nil && (question.Qtype == dns.TypeAAAA || question.Qtype == dns.TypeANY) {
+			header.Rrtype = dns.TypeAAAA
+			reply.Answer = append(reply.Answer, &dns.AAAA{Hdr: header, AAAA: ip})
+		}
+	}
+	return true
+}
+
+// forward relays the request to the first upstream that responds.
+func (server *Server) forward(request *dns.Msg, network string) (*dns.Msg, error) {
+	client := &dns.Client{
+		Net:     network,
+		Timeout: forwardTimeout,
+	}
+	var lastErr error
+	for _, upstream := range server.upstreams {
+		reply, _, err := client.Exchange(request, upstream)
+		if err == nil {
+			return reply, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// ServeDNS implements dns.Handler.
+func (server *Server) ServeDNS(w dns.ResponseWriter, request *dns.Msg) {
+	reply := new(dns.Msg)
+	reply.SetReply(request)
+	if len(request.Question) != 1 {
+		reply.SetRcode(request, dns.RcodeFormatError)
+		w.WriteMsg(reply)
+		return
+	}
+	if server.answer(reply, request.Question[0]) {
+		reply.Authoritative = true
+		w.WriteMsg(reply)
+		return
+	}
+	if len(server.upstreams) == 0 {
+		reply.SetRcode(request, dns.RcodeRefused)
+		w.WriteMsg(reply)
+		return
+	}
+	forwarded, err := server.forward(request, w.LocalAddr().Network())
+	if err != nil {
+		log.Println("dns: forward query:", err)
+		reply.SetRcode(request, dns.RcodeServerFailure)
+		w.WriteMsg(reply)
+		return
+	}
+	w.WriteMsg(forwarded)
+}
@@ -0,0 +1,253 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// securityGroupRulesFromApi validates and converts a list of API rules.
+func (driver *Driver) securityGroupRulesFromApi(apiRules []*api.SecurityGroup_Rule) ([]models.SecurityGroupRule, error) {
+	rules := make([]models.SecurityGroupRule, len(apiRules))
+	for i, rule := range apiRules {
+		if rule.Direction == api.SecurityGroup_DIRECTION_UNSPECIFIED {
+			return nil, fmt.Errorf("rule %d: direction must be specified", i)
+		}
+		if rule.PortMin > 65535 || rule.PortMax > 65535 || (rule.PortMax != 0 && rule.PortMax < rule.PortMin) {
+			return nil, fmt.Errorf("rule %d: invalid port range %d-%d", i, rule.PortMin, rule.PortMax)
+		}
+		if rule.PortMin != 0 && rule.Protocol != api.SecurityGroup_TCP && rule.Protocol != api.SecurityGroup_UDP {
+			return nil, fmt.Errorf("rule %d: ports require protocol TCP or UDP", i)
+		}
+		if rule.Cidr != "" && rule.RemoteGroupId != "" {
+			return nil, fmt.Errorf("rule %d: cidr and remote group are mutually exclusive", i)
+		}
+		if rule.Cidr != "" {
+			if _, _, err := net.ParseCIDR(rule.Cidr); err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i, err)
+			}
+		}
+		if rule.RemoteGroupId != "" {
+			if err := driver.db.Where("id = ?", rule.RemoteGroupId).First(&models.SecurityGroup{}).Error; err != nil {
+				return nil, fmt.Errorf("rule %d: retrieve remote group: %w", i, err)
+			}
+		}
+		rules[i] = models.SecurityGroupRule{
+			Direction:     rule.Direction.String(),
+			Protocol:      rule.Protocol.String(),
+			PortMin:       rule.PortMin,
+			PortMax:       rule.PortMax,
+			CIDR:          rule.Cidr,
+			RemoteGroupID: rule.RemoteGroupId,
+		}
+	}
+	return rules, nil
+}
+
+// applySecurityGroups redefines the filters of all security groups. Rules referencing other
+// groups resolve to the addresses of their members, so any membership change affects them.
+func (driver *Driver) applySecurityGroups() error {
+	var groups []models.SecurityGroup
+	if err := driver.db.Preload("Rules").Find(&groups).Error; err != nil {
+		return fmt.Errorf("find security groups: %w", err)
+	}
+	var ifaces []models.NetworkInterface
+	if err := driver.db.Preload("SecurityGroups").Find(&ifaces).Error; err != nil {
+		return fmt.Errorf("find interfaces: %w", err)
+	}
+	members := make(map[string][]string)
+	for _, iface := range ifaces {
+		for _, group := range iface.SecurityGroups {
+			members[group.ID] = append(members[group.ID], iface.IPv4)
+			if iface.IPv6 != "" {
+				members[group.ID] = append(members[group.ID], iface.IPv6)
+			}
+		}
+	}
+	for i := range groups {
+		if err := driver.lv.DefineSecurityGroup(&groups[i], members); err != nil {
+			return fmt.Errorf("define security group %s: %w", groups[i].ID, err)
+		}
+	}
+	return nil
+}
+
+func (driver *Driver) CreateSecurityGroup(ctx context.Context, request *api.CreateSecurityGroupRequest) (*api.CreateSecurityGroupResponse, error) {
+	if request.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name must not be empty")
+	}
+	rules, err := driver.securityGroupRulesFromApi(request.Rules)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid rules: %v", err)
+	}
+	group := models.SecurityGroup{
+		ID:        uuid.New().String(),
+		Name:      request.Name,
+		ProjectID: projectFrom(ctx),
+		Rules:     rules,
+	}
+	if err := driver.db.Create(&group).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "create security group record: %v", err)
+	}
+	log.Println("created security group record", group.ID)
+	if err := driver.applySecurityGroups(); err != nil {
+		return nil, status.Errorf(codes.Internal, "apply security groups: %v", err)
+	}
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_SECURITY_GROUP_CREATED, group.ID)
+	return &api.CreateSecurityGroupResponse{
+		Id: group.ID,
+	}, nil
+}
+
+func (driver *Driver) ListSecurityGroups(ctx context.Context, request *api.ListSecurityGroupsRequest) (*api.ListSecurityGroupsResponse, error) {
+	groups := []models.SecurityGroup{}
+	if err := scoped(ctx, driver.db).Preload("Rules").Find(&groups).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve security groups: %v", err)
+	}
+	apiGroups := make([]*api.SecurityGroup, len(groups))
+	for i := range groups {
+		apiRules := make([]*api.SecurityGroup_Rule, len(groups[i].Rules))
+		for j, rule := range groups[i].Rules {
+			apiRules[j] = &api.SecurityGroup_Rule{
+				Direction:     api.SecurityGroup_Direction(api.SecurityGroup_Direction_value[rule.Direction]),
+				Protocol:      api.SecurityGroup_Protocol(api.SecurityGroup_Protocol_value[rule.Protocol]),
+				PortMin:       rule.PortMin,
+				PortMax:       rule.PortMax,
+				Cidr:          rule.CIDR,
+				RemoteGroupId: rule.RemoteGroupID,
+			}
+		}
+		apiGroups[i] = &api.SecurityGroup{
+			Id:        groups[i].ID,
+			Name:      groups[i].Name,
+			Rules:     apiRules,
+			ProjectId: groups[i].ProjectID,
+		}
+	}
+	return &api.ListSecurityGroupsResponse{
+		SecurityGroups: apiGroups,
+	}, nil
+}
+
+func (driver *Driver) UpdateSecurityGroup(ctx context.Context, request *api.UpdateSecurityGroupRequest) (*api.UpdateSecurityGroupResponse, error) {
+	var group models.SecurityGroup
+	if err := driver.db.Where("id = ?", request.Id).First(&group).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve security group: %v", err)
+	}
+	rules, err := driver.securityGroupRulesFromApi(request.Rules)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid rules: %v", err)
+	}
+	// Replace rule set
+	if err := driver.db.Where("security_group_id = ?", group.ID).Delete(&models.SecurityGroupRule{}).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete security group rules: %v", err)
+	}
+	if len(rules) > 0 {
+		for i := range rules {
+			rules[i].SecurityGroupID = group.ID
+		}
+		if err := driver.db.Create(&rules).Error; err != nil {
+			return nil, status.Errorf(codes.Internal, "create security group rules: %v", err)
+		}
+	}
+	if err := driver.applySecurityGroups(); err != nil {
+		return nil, status.Errorf(codes.Internal, "apply security groups: %v", err)
+	}
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_SECURITY_GROUP_UPDATED, group.ID)
+	return &api.UpdateSecurityGroupResponse{}, nil
+}
+
+func (driver *Driver) DeleteSecurityGroup(ctx context.Context, request *api.DeleteSecurityGroupRequest) (*api.DeleteSecurityGroupResponse, error) {
+	var group models.SecurityGroup
+	if err := driver.db.Where("id = ?", request.Id).First(&group).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve security group: %v", err)
+	}
+	// Groups still in use would leave dangling filter references behind
+	var attached int64
+	if err := driver.db.Table("network_interface_security_groups").Where("security_group_id = ?", group.ID).Count(&attached).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "count attached interfaces: %v", err)
+	}
+	if attached > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "security group is attached to %d interfaces", attached)
+	}
+	var referenced int64
+	if err := driver.db.Model(&models.SecurityGroupRule{}).Where("remote_group_id = ?", group.ID).Count(&referenced).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "count referencing rules: %v", err)
+	}
+	if referenced > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "security group is referenced by %d rules", referenced)
+	}
+	if err := driver.db.Select("Rules").Delete(&group).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete security group record: %v", err)
+	}
+	if err := driver.lv.UndefineSecurityGroup(group.ID); err != nil {
+		log.Println("attempted to undefine security group:", err)
+	}
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_SECURITY_GROUP_DELETED, group.ID)
+	return &api.DeleteSecurityGroupResponse{}, nil
+}
+
+// findMachineInterface looks up the interface a machine has on a network.
+func (driver *Driver) findMachineInterface(machineID, networkID string) (*models.NetworkInterface, error) {
+	var iface models.NetworkInterface
+	if err := driver.db.Preload("SecurityGroups").Where("machine_id = ? AND network_id = ?", machineID, networkID).First(&iface).Error; err != nil {
+		return nil, err
+	}
+	return &iface, nil
+}
+
+func (driver *Driver) AttachSecurityGroup(ctx context.Context, request *api.AttachSecurityGroupRequest) (*api.AttachSecurityGroupResponse, error) {
+	var group models.SecurityGroup
+	if err := driver.db.Where("id = ?", request.SecurityGroupId).First(&group).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve security group: %v", err)
+	}
+	iface, err := driver.findMachineInterface(request.MachineId, request.NetworkId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve interface: %v", err)
+	}
+	if err := driver.db.Model(iface).Association("SecurityGroups").Append(&group); err != nil {
+		return nil, status.Errorf(codes.Internal, "attach security group: %v", err)
+	}
+	if err := driver.applySecurityGroups(); err != nil {
+		return nil, status.Errorf(codes.Internal, "apply security groups: %v", err)
+	}
+	if err := driver.lv.DefineInterfaceFilter(iface); err != nil {
+		return nil, status.Errorf(codes.Internal, "define interface filter: %v", err)
+	}
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_SECURITY_GROUP_UPDATED, group.ID)
+	return &api.AttachSecurityGroupResponse{}, nil
+}
+
+func (driver *Driver) DetachSecurityGroup(ctx context.Context, request *api.DetachSecurityGroupRequest) (*api.DetachSecurityGroupResponse, error) {
+	var group models.SecurityGroup
+	if err := driver.db.Where("id = ?", request.SecurityGroupId).First(&group).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve security group: %v", err)
+	}
+	iface, err := driver.findMachineInterface(request.MachineId, request.NetworkId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve interface: %v", err)
+	}
+	if err := driver.db.Model(iface).Association("SecurityGroups").Delete(&group); err != nil {
+		return nil, status.Errorf(codes.Internal, "detach security group: %v", err)
+	}
+	if err := driver.lv.DefineInterfaceFilter(iface); err != nil {
+		return nil, status.Errorf(codes.Internal, "define interface filter: %v", err)
+	}
+	if err := driver.applySecurityGroups(); err != nil {
+		return nil, status.Errorf(codes.Internal, "apply security groups: %v", err)
+	}
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_SECURITY_GROUP_UPDATED, group.ID)
+	return &api.DetachSecurityGroupResponse{}, nil
+}
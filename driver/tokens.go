@@ -0,0 +1,194 @@
+package driver
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/auth"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// parseScopes decodes the stored scopes of a token, nil if the token is not limited.
+func parseScopes(value string) []api.Token_Scope {
+	if value == "" {
+		return nil
+	}
+	names := strings.Split(value, ",")
+	scopes := make([]api.Token_Scope, len(names))
+	for i := range names {
+		scopes[i] = api.Token_Scope(api.Token_Scope_value[names[i]])
+	}
+	return scopes
+}
+
+func formatScopes(scopes []api.Token_Scope) string {
+	names := make([]string, len(scopes))
+	for i := range scopes {
+		names[i] = scopes[i].String()
+	}
+	return strings.Join(names, ",")
+}
+
+func tokenToApi(token *models.Token) *api.Token {
+	apiToken := &api.Token{
+		Id:        token.ID,
+		Name:      token.Name,
+		UserId:    token.UserID,
+		Scopes:    parseScopes(token.Scopes),
+		CreatedAt: timestamppb.New(token.CreatedAt),
+	}
+	if token.ExpiresAt != nil {
+		apiToken.ExpiresAt = timestamppb.New(*token.ExpiresAt)
+	}
+	return apiToken
+}
+
+// tokenOwner returns the user whose tokens the request manages. Only admins can manage the tokens of others.
+func (driver *Driver) tokenOwner(c *caller, userID string) (models.User, error) {
+	if userID == "" || userID == c.user.ID || userID == c.user.Name {
+		return c.user, nil
+	}
+	if !c.user.Admin {
+		return models.User{}, status.Errorf(codes.PermissionDenied, "only admins can manage tokens of other users")
+	}
+	var user models.User
+	if err := driver.db.Where("id = ? OR name = ?", userID, userID).First(&user).Error; err != nil {
+		return user, status.Errorf(codes.NotFound, "retrieve user: %v", err)
+	}
+	return user, nil
+}
+
+func (driver *Driver) GetCurrentUser(ctx context.Context, request *api.GetCurrentUserRequest) (*api.GetCurrentUserResponse, error) {
+	c := callerFrom(ctx)
+	if c == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "request is not authenticated")
+	}
+	memberships := []models.Membership{}
+	if err := driver.db.Where("user_id = ?", c.user.ID).Find(&memberships).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve memberships: %v", err)
+	}
+	apiMemberships := make([]*api.Membership, len(memberships))
+	for i := range memberships {
+		apiMemberships[i] = &api.Membership{
+			UserId:    memberships[i].UserID,
+			UserName:  c.user.Name,
+			ProjectId: memberships[i].ProjectID,
+			Role:      api.Role(api.Role_value[memberships[i].Role]),
+		}
+	}
+	return &api.GetCurrentUserResponse{
+		User: &api.User{
+			Id:    c.user.ID,
+			Name:  c.user.Name,
+			Admin: c.user.Admin,
+		},
+		Memberships: apiMemberships,
+		Scopes:      c.scopes,
+	}, nil
+}
+
+func (driver *Driver) CreateToken(ctx context.Context, request *api.CreateTokenRequest) (*api.CreateTokenResponse, error) {
+	c := callerFrom(ctx)
+	if c == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "request is not authenticated")
+	}
+	if request.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name must not be empty")
+	}
+	if request.Ttl < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "ttl must not be negative")
+	}
+	user, err := driver.tokenOwner(c, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	// Tokens can not grant more than the token they are created with
+	scopes := request.Scopes
+	if len(scopes) == 0 {
+		scopes = c.scopes
+	}
+	for _, scope := range scopes {
+		if scope == api.Token_SCOPE_UNSPECIFIED {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown scope")
+		}
+		if !c.allows(scope) {
+			return nil, status.Errorf(codes.PermissionDenied, "scope %s exceeds the scopes of the caller", scope)
+		}
+	}
+	secret, hash, err := auth.GenerateToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "generate token: %v", err)
+	}
+	token := models.Token{
+		ID:     uuid.New().String(),
+		Name:   request.Name,
+		UserID: user.ID,
+		Hash:   hash,
+		Scopes: formatScopes(scopes),
+	}
+	if request.Ttl > 0 {
+		expiresAt := time.Now().Add(time.Duration(request.Ttl) * time.Second)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := driver.db.Create(&token).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "create token record: %v", err)
+	}
+	log.Println("created token", token.Name, "for user", user.Name)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_TOKEN_CREATED, token.ID)
+	return &api.CreateTokenResponse{
+		Token:  tokenToApi(&token),
+		Secret: secret,
+	}, nil
+}
+
+func (driver *Driver) ListTokens(ctx context.Context, request *api.ListTokensRequest) (*api.ListTokensResponse, error) {
+	c := callerFrom(ctx)
+	if c == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "request is not authenticated")
+	}
+	user, err := driver.tokenOwner(c, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	tokens := []models.Token{}
+	if err := driver.db.Where("user_id = ?", user.ID).Order("created_at").Find(&tokens).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve tokens: %v", err)
+	}
+	apiTokens := make([]*api.Token, len(tokens))
+	for i := range tokens {
+		apiTokens[i] = tokenToApi(&tokens[i])
+	}
+	return &api.ListTokensResponse{
+		Tokens: apiTokens,
+	}, nil
+}
+
+func (driver *Driver) DeleteToken(ctx context.Context, request *api.DeleteTokenRequest) (*api.DeleteTokenResponse, error) {
+	c := callerFrom(ctx)
+	if c == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "request is not authenticated")
+	}
+	query := driver.db.Where("id = ?", request.Id)
+	if !c.user.Admin {
+		query = query.Where("user_id = ?", c.user.ID)
+	}
+	var token models.Token
+	if err := query.First(&token).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve token: %v", err)
+	}
+	if err := driver.db.Delete(&token).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete token record: %v", err)
+	}
+	log.Println("deleted token", token.Name)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_TOKEN_DELETED, token.ID)
+	return &api.DeleteTokenResponse{}, nil
+}
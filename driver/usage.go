@@ -0,0 +1,179 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/libvirt"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// usageInterval is the time between samples of the machine usage.
+	usageInterval = 10 * time.Second
+	// usageSamples is the number of samples kept per machine, which covers an hour.
+	usageSamples = 360
+)
+
+// usageHistory keeps the latest usage samples of a machine in a ring buffer.
+type usageHistory struct {
+	samples []*api.MachineUsage
+	// next is the index the next sample is written to once the buffer is full.
+	next int
+	// last holds the counters of the previous sampling the rates are computed from,
+	// nil if the machine was not running.
+	last     *libvirt.DomainStats
+	lastTime time.Time
+}
+
+func (h *usageHistory) add(sample *api.MachineUsage) {
+	if len(h.samples) < usageSamples {
+		h.samples = append(h.samples, sample)
+		return
+	}
+	h.samples[h.next] = sample
+	h.next = (h.next + 1) % usageSamples
+}
+
+// list returns the samples taken after since, oldest first.
+func (h *usageHistory) list(since time.Time) []*api.MachineUsage {
+	var samples []*api.MachineUsage
+	for i := range h.samples {
+		sample := h.samples[(h.next+i)%len(h.samples)]
+		if sample.Time.AsTime().After(since) {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// latest returns the newest sample, nil if there is none.
+func (h *usageHistory) latest() *api.MachineUsage {
+	if len(h.samples) == 0 {
+		return nil
+	}
+	return h.samples[(h.next+len(h.samples)-1)%len(h.samples)]
+}
+
+// sampleUsage records the usage of all running machines in their history every interval.
+func (driver *Driver) sampleUsage() {
+	ticker := time.NewTicker(usageInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := driver.recordUsage(); err != nil {
+			log.Println("sample machine usage:", err)
+		}
+	}
+}
+
+func (driver *Driver) recordUsage() error {
+	stats, err := driver.lv.GetDomainStats()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var machines []models.Machine
+	if err := driver.db.Find(&machines).Error; err != nil {
+		return fmt.Errorf("retrieve machines: %w", err)
+	}
+	statsByID := make(map[string]libvirt.DomainStats, len(stats))
+	for _, s := range stats {
+		statsByID[s.ID] = s
+	}
+	driver.usageMu.Lock()
+	defer driver.usageMu.Unlock()
+	// The histories of deleted machines are dropped
+	histories := make(map[string]*usageHistory, len(machines))
+	for i := range machines {
+		machine := &machines[i]
+		h, ok := driver.usage[machine.ID]
+		if !ok {
+			h = &usageHistory{}
+		}
+		histories[machine.ID] = h
+		s, ok := statsByID[machine.ID]
+		if !ok {
+			// The counters start over once the machine is running again
+			h.last = nil
+			continue
+		}
+		if h.last != nil {
+			h.add(usageSample(machine, h.last, &s, now, now.Sub(h.lastTime)))
+		}
+		h.last, h.lastTime = &s, now
+	}
+	driver.usage = histories
+	return nil
+}
+
+// usageSample computes the usage of a machine from the counters of two consecutive samplings.
+func usageSample(machine *models.Machine, prev, cur *libvirt.DomainStats, at time.Time, elapsed time.Duration) *api.MachineUsage {
+	seconds := elapsed.Seconds()
+	rate := func(prev, cur uint64) float64 {
+		// Counters are reset when devices are detached
+		if cur < prev {
+			return 0
+		}
+		return float64(cur-prev) / seconds
+	}
+	prevRead, prevWrite, prevRx, prevTx := usageTotals(prev)
+	curRead, curWrite, curRx, curTx := usageTotals(cur)
+	sample := &api.MachineUsage{
+		Time:                timestamppb.New(at),
+		MemoryUsed:          int64(cur.MemoryUsed) << 10,
+		MemoryTotal:         int64(cur.BalloonCurrent) << 10,
+		DiskReadRate:        rate(prevRead, curRead),
+		DiskWriteRate:       rate(prevWrite, curWrite),
+		NetworkReceiveRate:  rate(prevRx, curRx),
+		NetworkTransmitRate: rate(prevTx, curTx),
+	}
+	if sample.MemoryTotal == 0 {
+		sample.MemoryTotal = machine.Specs.Memory << 20
+	}
+	if machine.Specs.CPUs > 0 && cur.CPUTime >= prev.CPUTime {
+		sample.CpuPercent = 100 * (cur.CPUTime - prev.CPUTime).Seconds() / seconds / float64(machine.Specs.CPUs)
+	}
+	return sample
+}
+
+// usageTotals sums up the byte counters of all disks and interfaces.
+func usageTotals(s *libvirt.DomainStats) (read, write, rx, tx uint64) {
+	for _, disk := range s.Disks {
+		read += disk.ReadBytes
+		write += disk.WriteBytes
+	}
+	for _, iface := range s.Interfaces {
+		rx += iface.RxBytes
+		tx += iface.TxBytes
+	}
+	return
+}
+
+func (driver *Driver) GetMachineStats(ctx context.Context, request *api.GetMachineStatsRequest) (*api.GetMachineStatsResponse, error) {
+	var machine models.Machine
+	if err := driver.db.Where("id = ? OR name = ?", request.Id, request.Id).First(&machine).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve machine: %v", err)
+	}
+	var since time.Time
+	if request.Since != nil {
+		since = request.Since.AsTime()
+	}
+	resp := &api.GetMachineStatsResponse{
+		Interval: int64(usageInterval / time.Second),
+	}
+	driver.usageMu.Lock()
+	defer driver.usageMu.Unlock()
+	if h, ok := driver.usage[machine.ID]; ok {
+		resp.History = h.list(since)
+		if h.last != nil {
+			resp.Current = h.latest()
+		}
+	}
+	return resp, nil
+}
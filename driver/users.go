@@ -0,0 +1,229 @@
+package driver
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// projectModels are the models owned by projects.
+var projectModels = []interface{}{
+	&models.Machine{},
+	&models.Network{},
+	&models.SSHKey{},
+	&models.Image{},
+	&models.SecurityGroup{},
+	&models.PortForward{},
+	&models.FloatingIP{},
+	&models.Webhook{},
+}
+
+func (driver *Driver) CreateUser(ctx context.Context, request *api.CreateUserRequest) (*api.CreateUserResponse, error) {
+	if request.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name must not be empty")
+	}
+	user := models.User{
+		ID:    uuid.New().String(),
+		Name:  request.Name,
+		Admin: request.Admin,
+	}
+	if err := driver.db.Create(&user).Error; err != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "create user record: %v", err)
+	}
+	log.Println("created user", user.Name)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_USER_CREATED, user.ID)
+	return &api.CreateUserResponse{
+		Id: user.ID,
+	}, nil
+}
+
+func (driver *Driver) ListUsers(ctx context.Context, request *api.ListUsersRequest) (*api.ListUsersResponse, error) {
+	users := []models.User{}
+	if err := driver.db.Order("name").Find(&users).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve users: %v", err)
+	}
+	apiUsers := make([]*api.User, len(users))
+	for i := range users {
+		apiUsers[i] = &api.User{
+			Id:    users[i].ID,
+			Name:  users[i].Name,
+			Admin: users[i].Admin,
+		}
+	}
+	return &api.ListUsersResponse{
+		Users: apiUsers,
+	}, nil
+}
+
+func (driver *Driver) DeleteUser(ctx context.Context, request *api.DeleteUserRequest) (*api.DeleteUserResponse, error) {
+	var user models.User
+	if err := driver.db.Where("id = ? OR name = ?", request.Id, request.Id).First(&user).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve user: %v", err)
+	}
+	if c := callerFrom(ctx); c != nil && c.user.ID == user.ID {
+		return nil, status.Errorf(codes.FailedPrecondition, "users can not delete themselves")
+	}
+	if err := driver.db.Where("user_id = ?", user.ID).Delete(&models.Membership{}).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete memberships: %v", err)
+	}
+	if err := driver.db.Delete(&user).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete user record: %v", err)
+	}
+	log.Println("deleted user", user.Name)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_USER_DELETED, user.ID)
+	return &api.DeleteUserResponse{}, nil
+}
+
+func (driver *Driver) CreateProject(ctx context.Context, request *api.CreateProjectRequest) (*api.CreateProjectResponse, error) {
+	if request.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name must not be empty")
+	}
+	project := models.Project{
+		ID:   uuid.New().String(),
+		Name: request.Name,
+	}
+	if err := driver.db.Create(&project).Error; err != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "create project record: %v", err)
+	}
+	log.Println("created project", project.Name)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_PROJECT_CREATED, project.ID)
+	return &api.CreateProjectResponse{
+		Id: project.ID,
+	}, nil
+}
+
+func (driver *Driver) ListProjects(ctx context.Context, request *api.ListProjectsRequest) (*api.ListProjectsResponse, error) {
+	query := driver.db.Order("name")
+	if projects, all := visibleProjects(ctx); !all {
+		query = query.Where("id IN ?", projects)
+	}
+	projects := []models.Project{}
+	if err := query.Find(&projects).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve projects: %v", err)
+	}
+	apiProjects := make([]*api.Project, len(projects))
+	for i := range projects {
+		apiProjects[i] = &api.Project{
+			Id:   projects[i].ID,
+			Name: projects[i].Name,
+		}
+	}
+	return &api.ListProjectsResponse{
+		Projects: apiProjects,
+	}, nil
+}
+
+func (driver *Driver) DeleteProject(ctx context.Context, request *api.DeleteProjectRequest) (*api.DeleteProjectResponse, error) {
+	var project models.Project
+	if err := driver.db.Where("id = ? OR name = ?", request.Id, request.Id).First(&project).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve project: %v", err)
+	}
+	// Resources would be left without owner
+	for _, model := range projectModels {
+		var owned int64
+		if err := driver.db.Model(model).Where("project_id = ?", project.ID).Count(&owned).Error; err != nil {
+			return nil, status.Errorf(codes.Internal, "count resources: %v", err)
+		}
+		if owned > 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "project %s still owns resources", project.Name)
+		}
+	}
+	if err := driver.db.Where("project_id = ?", project.ID).Delete(&models.Membership{}).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete memberships: %v", err)
+	}
+	if err := driver.db.Where("project_id = ?", project.ID).Delete(&models.Quota{}).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete quota: %v", err)
+	}
+	if err := driver.db.Delete(&project).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete project record: %v", err)
+	}
+	log.Println("deleted project", project.Name)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_PROJECT_DELETED, project.ID)
+	return &api.DeleteProjectResponse{}, nil
+}
+
+// findMembership resolves the project and user of a membership given by ID or name.
+func (driver *Driver) findMembership(projectID, userID string) (models.Project, models.User, error) {
+	var (
+		project models.Project
+		user    models.User
+	)
+	if err := driver.db.Where("id = ? OR name = ?", projectID, projectID).First(&project).Error; err != nil {
+		return project, user, status.Errorf(codes.NotFound, "retrieve project: %v", err)
+	}
+	if err := driver.db.Where("id = ? OR name = ?", userID, userID).First(&user).Error; err != nil {
+		return project, user, status.Errorf(codes.NotFound, "retrieve user: %v", err)
+	}
+	return project, user, nil
+}
+
+func (driver *Driver) ListMemberships(ctx context.Context, request *api.ListMembershipsRequest) (*api.ListMembershipsResponse, error) {
+	var project models.Project
+	if err := driver.db.Where("id = ? OR name = ?", request.ProjectId, request.ProjectId).First(&project).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve project: %v", err)
+	}
+	memberships := []models.Membership{}
+	if err := driver.db.Preload("User").Where("project_id = ?", project.ID).Find(&memberships).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve memberships: %v", err)
+	}
+	apiMemberships := make([]*api.Membership, len(memberships))
+	for i := range memberships {
+		apiMemberships[i] = &api.Membership{
+			UserId:    memberships[i].UserID,
+			UserName:  memberships[i].User.Name,
+			ProjectId: memberships[i].ProjectID,
+			Role:      api.Role(api.Role_value[memberships[i].Role]),
+		}
+	}
+	return &api.ListMembershipsResponse{
+		Memberships: apiMemberships,
+	}, nil
+}
+
+func (driver *Driver) SetMembership(ctx context.Context, request *api.SetMembershipRequest) (*api.SetMembershipResponse, error) {
+	if request.Role == api.Role_ROLE_UNSPECIFIED {
+		return nil, status.Errorf(codes.InvalidArgument, "role must be given")
+	}
+	project, user, err := driver.findMembership(request.ProjectId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := driver.db.Save(&models.Membership{
+		UserID:    user.ID,
+		ProjectID: project.ID,
+		Role:      request.Role.String(),
+	}).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "save membership: %v", err)
+	}
+	log.Println("granted", request.Role, "role in project", project.Name, "to user", user.Name)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_MEMBERSHIP_UPDATED, project.ID)
+	return &api.SetMembershipResponse{}, nil
+}
+
+func (driver *Driver) RemoveMembership(ctx context.Context, request *api.RemoveMembershipRequest) (*api.RemoveMembershipResponse, error) {
+	project, user, err := driver.findMembership(request.ProjectId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	result := driver.db.Where("project_id = ? AND user_id = ?", project.ID, user.ID).Delete(&models.Membership{})
+	if result.Error != nil {
+		return nil, status.Errorf(codes.Internal, "delete membership: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, status.Errorf(codes.NotFound, "user %s is not a member of project %s", user.Name, project.Name)
+	}
+	log.Println("removed user", user.Name, "from project", project.Name)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_MEMBERSHIP_UPDATED, project.ID)
+	return &api.RemoveMembershipResponse{}, nil
+}
@@ -0,0 +1,258 @@
+package vpn
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vishvananda/netlink"
+)
+
+// Gateway manages the WireGuard device peers use to reach sox networks. All changes
+// are applied to the network namespace sox runs in, so the gateway can be tried out
+// in a namespace with ip netns exec.
+type Gateway struct {
+	mu sync.Mutex
+	// device is the name of the WireGuard link.
+	device   string
+	port     int
+	endpoint string
+	// address is the host address within the peer range.
+	address   *net.IPNet
+	publicKey string
+}
+
+// New brings up the WireGuard device with the first address of addressRange. The private
+// key is read from keyPath and generated if the file does not exist yet. The kernel module
+// is preferred, wireguard-go is used as fallback.
+func New(device, addressRange, endpoint string, port int, keyPath string) (*Gateway, error) {
+	ip, ipnet, err := net.ParseCIDR(addressRange)
+	if err != nil {
+		return nil, fmt.Errorf("parse vpn range: %w", err)
+	}
+	ip = ip.Mask(ipnet.Mask).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("vpn range %s is not an IPv4 network", addressRange)
+	}
+	ip[3]++
+	if endpoint == "" {
+		if endpoint, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("determine endpoint: %w", err)
+		}
+	}
+	gateway := &Gateway{
+		device:   device,
+		port:     port,
+		endpoint: net.JoinHostPort(endpoint, strconv.Itoa(port)),
+		address:  &net.IPNet{IP: ip, Mask: ipnet.Mask},
+	}
+	if err := ensureKey(keyPath); err != nil {
+		return nil, err
+	}
+	privateKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("read private key: %w", err)
+	}
+	if gateway.publicKey, err = PublicKey(strings.TrimSpace(string(privateKey))); err != nil {
+		return nil, err
+	}
+	if err := gateway.ensureDevice(); err != nil {
+		return nil, err
+	}
+	if _, err := wg("set", device, "listen-port", strconv.Itoa(port), "private-key", keyPath); err != nil {
+		return nil, fmt.Errorf("configure device: %w", err)
+	}
+	log.Println("started vpn gateway", device, "on port", port)
+	return gateway, nil
+}
+
+// wg runs the wireguard tool with the given arguments and returns its output.
+func wg(args ...string) (string, error) {
+	return wgInput("", args...)
+}
+
+func wgInput(input string, args ...string) (string, error) {
+	cmd := exec.Command("wg", args...)
+	cmd.Stdin = strings.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("wg %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ensureKey generates a private key at path unless one exists.
+func ensureKey(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("check private key: %w", err)
+	}
+	key, err := wg("genkey")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(key+"\n"), 0600); err != nil {
+		return fmt.Errorf("write private key: %w", err)
+	}
+	log.Println("generated vpn private key", path)
+	return nil
+}
+
+// ensureDevice creates the WireGuard link if necessary, assigns the gateway address and brings it up.
+func (gateway *Gateway) ensureDevice() error {
+	link, err := netlink.LinkByName(gateway.device)
+	if _, ok := err.(netlink.LinkNotFoundError); ok {
+		link, err = gateway.createDevice()
+	}
+	if err != nil {
+		return fmt.Errorf("find device %s: %w", gateway.device, err)
+	}
+	if err := netlink.AddrReplace(link, &netlink.Addr{IPNet: gateway.address}); err != nil {
+		return fmt.Errorf("add gateway address: %w", err)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return fmt.Errorf("set device up: %w", err)
+	}
+	return nil
+}
+
+func (gateway *Gateway) createDevice() (netlink.Link, error) {
+	attrs := netlink.NewLinkAttrs()
+	attrs.Name = gateway.device
+	err := netlink.LinkAdd(&netlink.GenericLink{LinkAttrs: attrs, LinkType: "wireguard"})
+	if err == nil {
+		log.Println("created wireguard device", gateway.device)
+		return netlink.LinkByName(gateway.device)
+	}
+	// Without kernel support the userspace implementation creates a tun device
+	log.Println("kernel wireguard unavailable, falling back to wireguard-go:", err)
+	if output, err := exec.Command("wireguard-go", gateway.device).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("start wireguard-go: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	// wireguard-go daemonizes before the device is guaranteed to exist
+	for i := 0; i < 50; i++ {
+		link, err := netlink.LinkByName(gateway.device)
+		if err == nil {
+			return link, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil, fmt.Errorf("device %s did not appear", gateway.device)
+}
+
+// Device returns the name of the WireGuard link.
+func (gateway *Gateway) Device() string {
+	return gateway.device
+}
+
+// Range returns the peer address range.
+func (gateway *Gateway) Range() *net.IPNet {
+	return &net.IPNet{IP: gateway.address.IP.Mask(gateway.address.Mask), Mask: gateway.address.Mask}
+}
+
+// Address returns the address of the host within the peer range.
+func (gateway *Gateway) Address() net.IP {
+	return gateway.address.IP
+}
+
+// GenerateKeyPair returns a new private and public key in base64 encoding.
+func GenerateKeyPair() (string, string, error) {
+	privateKey, err := wg("genkey")
+	if err != nil {
+		return "", "", err
+	}
+	publicKey, err := PublicKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	return privateKey, publicKey, nil
+}
+
+// PublicKey derives the public key of a private key.
+func PublicKey(privateKey string) (string, error) {
+	return wgInput(privateKey, "pubkey")
+}
+
+// ValidateKey checks that key is a base64 encoded Curve25519 key.
+func ValidateKey(key string) error {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return fmt.Errorf("decode key: %w", err)
+	}
+	if len(raw) != 32 {
+		return fmt.Errorf("key must be 32 bytes long, got %d", len(raw))
+	}
+	return nil
+}
+
+// AddPeer allows the peer to send from its tunnel address.
+func (gateway *Gateway) AddPeer(publicKey, address string) error {
+	gateway.mu.Lock()
+	defer gateway.mu.Unlock()
+	if _, err := wg("set", gateway.device, "peer", publicKey, "allowed-ips", address); err != nil {
+		return fmt.Errorf("add peer: %w", err)
+	}
+	log.Println("added vpn peer", address)
+	return nil
+}
+
+// RemovePeer drops the peer from the device.
+func (gateway *Gateway) RemovePeer(publicKey string) error {
+	gateway.mu.Lock()
+	defer gateway.mu.Unlock()
+	if _, err := wg("set", gateway.device, "peer", publicKey, "remove"); err != nil {
+		return fmt.Errorf("remove peer: %w", err)
+	}
+	log.Println("removed vpn peer", publicKey)
+	return nil
+}
+
+// Prune removes all peers from the device whose public key is not in keep.
+func (gateway *Gateway) Prune(keep map[string]struct{}) error {
+	gateway.mu.Lock()
+	defer gateway.mu.Unlock()
+	output, err := wg("show", gateway.device, "peers")
+	if err != nil {
+		return fmt.Errorf("list peers: %w", err)
+	}
+	for _, key := range strings.Fields(output) {
+		if _, ok := keep[key]; ok {
+			continue
+		}
+		if _, err := wg("set", gateway.device, "peer", key, "remove"); err != nil {
+			return fmt.Errorf("remove stale peer: %w", err)
+		}
+		log.Println("removed stale vpn peer", key)
+	}
+	return nil
+}
+
+// ClientConfig renders a wg-quick configuration for the peer. An empty private key
+// leaves a placeholder for the peer to fill in.
+func (gateway *Gateway) ClientConfig(privateKey, address string, subnets []string) string {
+	if privateKey == "" {
+		privateKey = "<private key>"
+	}
+	allowedIPs := append([]string{gateway.address.IP.String() + "/32"}, subnets...)
+	var config strings.Builder
+	fmt.Fprintf(&config, "[Interface]\n")
+	fmt.Fprintf(&config, "PrivateKey = %s\n", privateKey)
+	fmt.Fprintf(&config, "Address = %s\n", address)
+	fmt.Fprintf(&config, "\n[Peer]\n")
+	fmt.Fprintf(&config, "PublicKey = %s\n", gateway.publicKey)
+	fmt.Fprintf(&config, "Endpoint = %s\n", gateway.endpoint)
+	fmt.Fprintf(&config, "AllowedIPs = %s\n", strings.Join(allowedIPs, ", "))
+	fmt.Fprintf(&config, "PersistentKeepalive = 25\n")
+	return config.String()
+}
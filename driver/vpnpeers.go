@@ -0,0 +1,170 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"github.com/lnsp/sox/driver/vpn"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// restoreVPNPeers re-programs the WireGuard peers and their forward rules.
+func (driver *Driver) restoreVPNPeers() error {
+	if driver.vpn == nil {
+		return nil
+	}
+	var peers []models.VPNPeer
+	if err := driver.db.Preload("Networks").Find(&peers).Error; err != nil {
+		return fmt.Errorf("find vpn peers: %w", err)
+	}
+	keep := make(map[string]struct{}, len(peers))
+	for i := range peers {
+		keep[peers[i].PublicKey] = struct{}{}
+		if err := driver.vpn.AddPeer(peers[i].PublicKey, peers[i].Address); err != nil {
+			return fmt.Errorf("add vpn peer %s: %w", peers[i].Name, err)
+		}
+		if err := driver.nat.AddVPNPeer(&peers[i], driver.vpn.Device()); err != nil {
+			return fmt.Errorf("add vpn peer routes %s: %w", peers[i].Name, err)
+		}
+	}
+	return driver.vpn.Prune(keep)
+}
+
+func vpnPeerToApi(peer *models.VPNPeer) *api.VPNPeer {
+	networkIds := make([]string, len(peer.Networks))
+	for i := range peer.Networks {
+		networkIds[i] = peer.Networks[i].ID
+	}
+	return &api.VPNPeer{
+		Id:         peer.ID,
+		Name:       peer.Name,
+		PublicKey:  peer.PublicKey,
+		Address:    peer.Address,
+		NetworkIds: networkIds,
+	}
+}
+
+func (driver *Driver) CreateVPNPeer(ctx context.Context, request *api.CreateVPNPeerRequest) (*api.CreateVPNPeerResponse, error) {
+	if driver.vpn == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "no vpn range configured")
+	}
+	if request.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name must not be empty")
+	}
+	networks := make([]models.Network, len(request.NetworkIds))
+	for i := range request.NetworkIds {
+		if err := driver.db.Where("id = ? OR name = ?", request.NetworkIds[i], request.NetworkIds[i]).First(&networks[i]).Error; err != nil {
+			return nil, status.Errorf(codes.NotFound, "retrieve network: %v", err)
+		}
+		if !networks[i].HasHostAddress() {
+			return nil, status.Errorf(codes.FailedPrecondition, "network %s is not reachable from the host", networks[i].Name)
+		}
+	}
+	// Only hand out private keys which are generated right now
+	privateKey, publicKey := "", request.PublicKey
+	if publicKey != "" {
+		if err := vpn.ValidateKey(publicKey); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid public key: %v", err)
+		}
+	} else {
+		var err error
+		if privateKey, publicKey, err = vpn.GenerateKeyPair(); err != nil {
+			return nil, status.Errorf(codes.Internal, "generate key pair: %v", err)
+		}
+	}
+	// Allocate tunnel address
+	var existing []models.VPNPeer
+	if err := driver.db.Find(&existing).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve vpn peers: %v", err)
+	}
+	used := make([]string, len(existing))
+	for i := range existing {
+		used[i] = existing[i].Address
+	}
+	free, err := findFreeAddress(driver.vpn.Range().String(), driver.vpn.Address().String(), used)
+	if err != nil {
+		return nil, status.Errorf(codes.ResourceExhausted, "find free address: %v", err)
+	}
+	ip, _, _ := net.ParseCIDR(free)
+	peer := models.VPNPeer{
+		ID:        uuid.New().String(),
+		Name:      request.Name,
+		PublicKey: publicKey,
+		Address:   ip.String() + "/32",
+		Networks:  networks,
+	}
+	if err := driver.db.Create(&peer).Error; err != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "create vpn peer record: %v", err)
+	}
+	if err := driver.vpn.AddPeer(peer.PublicKey, peer.Address); err != nil {
+		driver.db.Select("Networks").Delete(&peer)
+		return nil, status.Errorf(codes.Internal, "add vpn peer: %v", err)
+	}
+	if err := driver.nat.AddVPNPeer(&peer, driver.vpn.Device()); err != nil {
+		// Rules appended before the failure are removed along with the peer
+		if err := driver.nat.RemoveVPNPeer(&peer, driver.vpn.Device()); err != nil {
+			log.Println("remove routes of vpn peer", peer.Name+":", err)
+		}
+		if err := driver.vpn.RemovePeer(peer.PublicKey); err != nil {
+			log.Println("remove vpn peer", peer.Name+":", err)
+		}
+		if err := driver.db.Select("Networks").Delete(&peer).Error; err != nil {
+			log.Println("delete vpn peer record", peer.Name+":", err)
+		}
+		return nil, status.Errorf(codes.Internal, "add vpn peer routes: %v", err)
+	}
+	log.Println("created vpn peer", peer.Name, "with address", peer.Address)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_VPN_PEER_CREATED, peer.ID)
+	subnets := make([]string, len(networks))
+	for i := range networks {
+		subnets[i] = networks[i].IPv4.Subnet
+	}
+	return &api.CreateVPNPeerResponse{
+		VpnPeer:      vpnPeerToApi(&peer),
+		ClientConfig: driver.vpn.ClientConfig(privateKey, peer.Address, subnets),
+	}, nil
+}
+
+func (driver *Driver) ListVPNPeers(ctx context.Context, request *api.ListVPNPeersRequest) (*api.ListVPNPeersResponse, error) {
+	peers := []models.VPNPeer{}
+	if err := driver.db.Preload("Networks").Find(&peers).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve vpn peers: %v", err)
+	}
+	apiPeers := make([]*api.VPNPeer, len(peers))
+	for i := range peers {
+		apiPeers[i] = vpnPeerToApi(&peers[i])
+	}
+	return &api.ListVPNPeersResponse{
+		VpnPeers: apiPeers,
+	}, nil
+}
+
+func (driver *Driver) DeleteVPNPeer(ctx context.Context, request *api.DeleteVPNPeerRequest) (*api.DeleteVPNPeerResponse, error) {
+	if driver.vpn == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "no vpn range configured")
+	}
+	var peer models.VPNPeer
+	if err := driver.db.Preload("Networks").Where("id = ? OR name = ?", request.Id, request.Id).First(&peer).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve vpn peer: %v", err)
+	}
+	if err := driver.vpn.RemovePeer(peer.PublicKey); err != nil {
+		return nil, status.Errorf(codes.Internal, "remove vpn peer: %v", err)
+	}
+	if err := driver.nat.RemoveVPNPeer(&peer, driver.vpn.Device()); err != nil {
+		return nil, status.Errorf(codes.Internal, "remove vpn peer routes: %v", err)
+	}
+	if err := driver.db.Select("Networks").Delete(&peer).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete vpn peer record: %v", err)
+	}
+	log.Println("deleted vpn peer", peer.Name)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_VPN_PEER_DELETED, peer.ID)
+	return &api.DeleteVPNPeerResponse{}, nil
+}
@@ -0,0 +1,111 @@
+//go:build cgo && linux
+// +build cgo,linux
+
+package driver
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"github.com/lnsp/sox/driver/nat"
+	"github.com/lnsp/sox/driver/vpn"
+	"github.com/lnsp/sox/events"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The VPN tests change the WireGuard devices and firewall of the network namespace they run in,
+// scripts/test-vpn.sh runs them as root in a new one.
+
+const testVPNDevice = "soxtest0"
+
+func newVPNDriver(t *testing.T) *Driver {
+	if os.Getenv("SOX_NETNS_TEST") == "" {
+		t.Skip("SOX_NETNS_TEST is not set, run scripts/test-vpn.sh")
+	}
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "sox.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Network{}, &models.VPNPeer{}, &models.Activity{}); err != nil {
+		t.Fatalf("migrate db: %v", err)
+	}
+	network := models.Network{ID: "network", Name: "network", Mode: models.NetworkModeNAT}
+	network.IPv4.Subnet = "192.168.100.0/24"
+	network.IPv4.Gateway = "192.168.100.1"
+	if err := db.Create(&network).Error; err != nil {
+		t.Fatalf("create network: %v", err)
+	}
+	gateway, err := vpn.New(testVPNDevice, "10.99.0.0/24", "vpn.test", 51820, filepath.Join(t.TempDir(), "vpn.key"))
+	if err != nil {
+		t.Fatalf("start vpn gateway: %v", err)
+	}
+	t.Cleanup(func() { exec.Command("ip", "link", "del", testVPNDevice).Run() })
+	table, err := nat.New("lo")
+	if err != nil {
+		t.Fatalf("create nat table: %v", err)
+	}
+	return &Driver{db: db, vpn: gateway, nat: table, events: events.NewBus("test")}
+}
+
+func command(t *testing.T, name string, args ...string) string {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s %s: %v: %s", name, strings.Join(args, " "), err, output)
+	}
+	return string(output)
+}
+
+func TestCreateVPNPeer(t *testing.T) {
+	driver := newVPNDriver(t)
+	ctx := context.Background()
+	resp, err := driver.CreateVPNPeer(ctx, &api.CreateVPNPeerRequest{Name: "laptop", NetworkIds: []string{"network"}})
+	if err != nil {
+		t.Fatalf("CreateVPNPeer() = %v", err)
+	}
+	peer := resp.VpnPeer
+	if peers := command(t, "wg", "show", testVPNDevice, "peers"); !strings.Contains(peers, peer.PublicKey) {
+		t.Errorf("wireguard peers %q do not contain %s", peers, peer.PublicKey)
+	}
+	if rules := command(t, "iptables", "-S", "SOX-FORWARD"); strings.Count(rules, "sox-vpn-"+peer.Id) != 2 {
+		t.Errorf("forward rules of peer missing:\n%s", rules)
+	}
+
+	if _, err := driver.DeleteVPNPeer(ctx, &api.DeleteVPNPeerRequest{Id: peer.Id}); err != nil {
+		t.Fatalf("DeleteVPNPeer() = %v", err)
+	}
+	if peers := command(t, "wg", "show", testVPNDevice, "peers"); strings.Contains(peers, peer.PublicKey) {
+		t.Errorf("wireguard peer %s was not removed", peer.PublicKey)
+	}
+	if rules := command(t, "iptables", "-S", "SOX-FORWARD"); strings.Contains(rules, "sox-vpn-"+peer.Id) {
+		t.Errorf("forward rules of peer were not removed:\n%s", rules)
+	}
+}
+
+func TestCreateVPNPeerRollback(t *testing.T) {
+	driver := newVPNDriver(t)
+	// Without the forward chain the rules of the peer can not be added
+	command(t, "iptables", "-D", "FORWARD", "-j", "SOX-FORWARD")
+	command(t, "iptables", "-X", "SOX-FORWARD")
+
+	if _, err := driver.CreateVPNPeer(context.Background(), &api.CreateVPNPeerRequest{Name: "laptop", NetworkIds: []string{"network"}}); err == nil {
+		t.Fatal("CreateVPNPeer() succeeded without forward chain")
+	}
+	var records int64
+	if err := driver.db.Model(&models.VPNPeer{}).Count(&records).Error; err != nil {
+		t.Fatalf("count vpn peers: %v", err)
+	}
+	if records != 0 {
+		t.Errorf("%d vpn peer records left after failure", records)
+	}
+	if peers := strings.TrimSpace(command(t, "wg", "show", testVPNDevice, "peers")); peers != "" {
+		t.Errorf("wireguard peers %q left after failure", peers)
+	}
+}
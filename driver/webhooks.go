@@ -0,0 +1,401 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"github.com/lnsp/sox/events"
+	"github.com/lnsp/sox/webhooks"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// webhookTimeout limits a single delivery attempt.
+	webhookTimeout = 10 * time.Second
+	// webhookBatchSize is the number of due deliveries fetched at once.
+	webhookBatchSize = 100
+	// webhookWorkers is the number of deliveries attempted concurrently.
+	webhookWorkers = 10
+	// webhookIdleInterval is the longest time between checks for due deliveries.
+	webhookIdleInterval = time.Minute
+)
+
+// WebhookRetries configures how often and when failed deliveries are retried.
+type WebhookRetries struct {
+	// MaxAttempts is the number of attempts after which a delivery fails.
+	MaxAttempts int
+	// Delay before the first retry, doubled with every further attempt up to MaxDelay.
+	Delay    time.Duration
+	MaxDelay time.Duration
+}
+
+// DefaultWebhookRetries gives up on deliveries after about three hours.
+var DefaultWebhookRetries = WebhookRetries{
+	MaxAttempts: 10,
+	Delay:       30 * time.Second,
+	MaxDelay:    time.Hour,
+}
+
+// backoff returns the time to wait after the given number of failed attempts.
+func (r WebhookRetries) backoff(attempts int32) time.Duration {
+	delay := r.Delay
+	for i := int32(1); i < attempts && delay < r.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+	return delay
+}
+
+func webhookRef(id string) resource {
+	return resource{kind: "webhook", id: id, model: &models.Webhook{}, where: "id = ? OR name = ?", column: "project_id"}
+}
+
+func webhookToApi(hook *models.Webhook) *api.Webhook {
+	filter := &api.Webhook_Filter{}
+	for _, name := range splitNames(hook.EventTypes) {
+		filter.Types = append(filter.Types, api.Event_Type(api.Event_Type_value[name]))
+	}
+	for _, name := range splitNames(hook.ActivityTypes) {
+		filter.ActivityTypes = append(filter.ActivityTypes, api.Activity_Type(api.Activity_Type_value[name]))
+	}
+	for _, name := range splitNames(hook.MachineStatuses) {
+		filter.MachineStatuses = append(filter.MachineStatuses, api.Machine_Status(api.Machine_Status_value[name]))
+	}
+	return &api.Webhook{
+		Id:        hook.ID,
+		Name:      hook.Name,
+		Url:       hook.URL,
+		Filter:    filter,
+		ProjectId: hook.ProjectID,
+		CreatedAt: timestamppb.New(hook.CreatedAt),
+	}
+}
+
+func splitNames(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// matchesName reports whether the comma-separated names contain the name, empty names match all.
+func matchesName(names, name string) bool {
+	if names == "" {
+		return true
+	}
+	for _, n := range strings.Split(names, ",") {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (driver *Driver) CreateWebhook(ctx context.Context, request *api.CreateWebhookRequest) (*api.CreateWebhookResponse, error) {
+	if request.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name must not be empty")
+	}
+	if err := webhooks.ValidateURL(ctx, request.Url, driver.webhookAllowPrivate); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid url: %v", err)
+	}
+	hook := models.Webhook{
+		ID:        uuid.New().String(),
+		Name:      request.Name,
+		ProjectID: projectFrom(ctx),
+		URL:       request.Url,
+		Secret:    request.Secret,
+	}
+	var eventTypes, activityTypes, machineStatuses []string
+	for _, t := range request.Filter.GetTypes() {
+		if t == api.Event_TYPE_UNSPECIFIED {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown event type")
+		}
+		eventTypes = append(eventTypes, t.String())
+	}
+	for _, t := range request.Filter.GetActivityTypes() {
+		if t == api.Activity_UNKNOWN {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown activity type")
+		}
+		activityTypes = append(activityTypes, t.String())
+	}
+	for _, s := range request.Filter.GetMachineStatuses() {
+		machineStatuses = append(machineStatuses, s.String())
+	}
+	hook.EventTypes = strings.Join(eventTypes, ",")
+	hook.ActivityTypes = strings.Join(activityTypes, ",")
+	hook.MachineStatuses = strings.Join(machineStatuses, ",")
+	if hook.Secret == "" {
+		secret, err := webhooks.GenerateSecret()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "generate secret: %v", err)
+		}
+		hook.Secret = secret
+	}
+	if err := driver.db.Create(&hook).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "create webhook record: %v", err)
+	}
+	log.Println("created webhook", hook.Name, "posting to", hook.URL)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_WEBHOOK_CREATED, hook.ID)
+	return &api.CreateWebhookResponse{
+		Webhook: webhookToApi(&hook),
+		Secret:  hook.Secret,
+	}, nil
+}
+
+func (driver *Driver) ListWebhooks(ctx context.Context, request *api.ListWebhooksRequest) (*api.ListWebhooksResponse, error) {
+	hooks := []models.Webhook{}
+	if err := scoped(ctx, driver.db).Order("name").Find(&hooks).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve webhooks: %v", err)
+	}
+	apiHooks := make([]*api.Webhook, len(hooks))
+	for i := range hooks {
+		apiHooks[i] = webhookToApi(&hooks[i])
+	}
+	return &api.ListWebhooksResponse{
+		Webhooks: apiHooks,
+	}, nil
+}
+
+func (driver *Driver) DeleteWebhook(ctx context.Context, request *api.DeleteWebhookRequest) (*api.DeleteWebhookResponse, error) {
+	var hook models.Webhook
+	if err := driver.db.Where("id = ? OR name = ?", request.Id, request.Id).First(&hook).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve webhook: %v", err)
+	}
+	if err := driver.db.Where("webhook_id = ?", hook.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete webhook deliveries: %v", err)
+	}
+	if err := driver.db.Delete(&hook).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "delete webhook record: %v", err)
+	}
+	log.Println("deleted webhook", hook.Name)
+	// Record activity
+	driver.recordActivity(ctx, api.Activity_WEBHOOK_DELETED, hook.ID)
+	return &api.DeleteWebhookResponse{}, nil
+}
+
+func (driver *Driver) ListWebhookDeliveries(ctx context.Context, request *api.ListWebhookDeliveriesRequest) (*api.ListWebhookDeliveriesResponse, error) {
+	var hook models.Webhook
+	if err := driver.db.Where("id = ? OR name = ?", request.WebhookId, request.WebhookId).First(&hook).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "retrieve webhook: %v", err)
+	}
+	query := driver.db.Where("webhook_id = ?", hook.ID)
+	if request.Status != api.WebhookDelivery_STATUS_UNSPECIFIED {
+		query = query.Where("status = ?", request.Status.String())
+	}
+	if request.PageToken != "" {
+		last, err := strconv.ParseUint(request.PageToken, 10, 64)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token")
+		}
+		query = query.Where("id < ?", last)
+	}
+	size := activityPageSize(request.PageSize)
+	deliveries := []models.WebhookDelivery{}
+	if err := query.Order("id DESC").Limit(size + 1).Find(&deliveries).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieve webhook deliveries: %v", err)
+	}
+	var nextPageToken string
+	if len(deliveries) > size {
+		deliveries = deliveries[:size]
+		nextPageToken = strconv.FormatUint(uint64(deliveries[size-1].ID), 10)
+	}
+	apiDeliveries := make([]*api.WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		event := &api.Event{}
+		if err := protojson.Unmarshal([]byte(delivery.Event), event); err != nil {
+			return nil, status.Errorf(codes.Internal, "decode event of delivery %d: %v", delivery.ID, err)
+		}
+		apiDeliveries[i] = &api.WebhookDelivery{
+			Id:           strconv.FormatUint(uint64(delivery.ID), 10),
+			WebhookId:    delivery.WebhookID,
+			Event:        event,
+			Status:       api.WebhookDelivery_Status(api.WebhookDelivery_Status_value[delivery.Status]),
+			Attempts:     delivery.Attempts,
+			ResponseCode: delivery.ResponseCode,
+			Error:        delivery.Error,
+			CreatedAt:    timestamppb.New(delivery.CreatedAt),
+		}
+		if delivery.LastAttemptAt != nil {
+			apiDeliveries[i].LastAttemptAt = timestamppb.New(*delivery.LastAttemptAt)
+		}
+		if delivery.Status == api.WebhookDelivery_PENDING.String() {
+			apiDeliveries[i].NextAttemptAt = timestamppb.New(delivery.NextAttemptAt)
+		}
+	}
+	return &api.ListWebhookDeliveriesResponse{
+		Deliveries:    apiDeliveries,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// queueWebhookDeliveries queues deliveries of the published events to the matching webhooks of their project.
+func (driver *Driver) queueWebhookDeliveries() {
+	var last string
+	for {
+		watcher, err := driver.events.Watch(last)
+		if errors.Is(err, events.ErrExpired) {
+			log.Println("webhook deliveries of events after", last, "were lost")
+			last = ""
+			continue
+		} else if err != nil {
+			log.Println("watch events for webhooks failed:", err)
+			return
+		}
+		for event := range watcher.C {
+			last = event.Id
+			driver.queueDeliveries(event)
+		}
+		// The watcher fell behind, continue after the last queued event
+	}
+}
+
+func (driver *Driver) queueDeliveries(event *api.Event) {
+	if event.ProjectId == "" {
+		return
+	}
+	var hooks []models.Webhook
+	if err := driver.db.Where("project_id = ?", event.ProjectId).Find(&hooks).Error; err != nil {
+		log.Println("find webhooks of event", event.Id, "failed:", err)
+		return
+	}
+	var payload []byte
+	for _, hook := range hooks {
+		if !webhookMatches(&hook, event) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			if payload, err = protojson.Marshal(event); err != nil {
+				log.Println("encode event", event.Id, "failed:", err)
+				return
+			}
+		}
+		delivery := models.WebhookDelivery{
+			WebhookID:     hook.ID,
+			Event:         string(payload),
+			EventType:     event.Type.String(),
+			Status:        api.WebhookDelivery_PENDING.String(),
+			NextAttemptAt: time.Now(),
+		}
+		if err := driver.db.Create(&delivery).Error; err != nil {
+			log.Println("queue delivery of event", event.Id, "to webhook", hook.Name, "failed:", err)
+		}
+	}
+	if payload != nil {
+		select {
+		case driver.webhookQueued <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// webhookMatches reports whether the event passes the filter of the webhook.
+func webhookMatches(hook *models.Webhook, event *api.Event) bool {
+	if !matchesName(hook.EventTypes, event.Type.String()) {
+		return false
+	}
+	switch event.Type {
+	case api.Event_ACTIVITY_RECORDED:
+		return matchesName(hook.ActivityTypes, event.Activity.GetType().String())
+	case api.Event_MACHINE_STATE_CHANGED:
+		return matchesName(hook.MachineStatuses, event.MachineStatus.String())
+	}
+	return true
+}
+
+// deliverWebhooks attempts due deliveries whenever deliveries are queued or the next retry is due.
+func (driver *Driver) deliverWebhooks() {
+	for {
+		wait := driver.attemptDueDeliveries()
+		select {
+		case <-driver.webhookQueued:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// attemptDueDeliveries attempts the pending deliveries which are due and returns the time until the next one is.
+func (driver *Driver) attemptDueDeliveries() time.Duration {
+	pending := api.WebhookDelivery_PENDING.String()
+	var due []models.WebhookDelivery
+	if err := driver.db.Where("status = ? AND next_attempt_at <= ?", pending, time.Now()).Order("id").Limit(webhookBatchSize).Find(&due).Error; err != nil {
+		log.Println("find due webhook deliveries failed:", err)
+		return webhookIdleInterval
+	}
+	// Slow receivers only hold up their own deliveries
+	jobs := make(chan *models.WebhookDelivery)
+	var wg sync.WaitGroup
+	for i := 0; i < webhookWorkers && i < len(due); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range jobs {
+				driver.attemptDelivery(delivery)
+			}
+		}()
+	}
+	for i := range due {
+		jobs <- &due[i]
+	}
+	close(jobs)
+	wg.Wait()
+	if len(due) == webhookBatchSize {
+		return 0
+	}
+	var next models.WebhookDelivery
+	if result := driver.db.Where("status = ?", pending).Order("next_attempt_at").Limit(1).Find(&next); result.Error != nil || result.RowsAffected == 0 {
+		return webhookIdleInterval
+	}
+	if wait := time.Until(next.NextAttemptAt); wait < webhookIdleInterval {
+		return wait
+	}
+	return webhookIdleInterval
+}
+
+func (driver *Driver) attemptDelivery(delivery *models.WebhookDelivery) {
+	var hook models.Webhook
+	if result := driver.db.Where("id = ?", delivery.WebhookID).Limit(1).Find(&hook); result.Error != nil {
+		log.Println("retrieve webhook", delivery.WebhookID, "failed:", result.Error)
+		return
+	} else if result.RowsAffected == 0 {
+		// The webhook was deleted while its deliveries were attempted
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	id := strconv.FormatUint(uint64(delivery.ID), 10)
+	code, err := webhooks.Send(ctx, driver.webhookClient, hook.URL, hook.Secret, id, delivery.EventType, []byte(delivery.Event))
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseCode = int32(code)
+	delivery.Error = ""
+	switch {
+	case err == nil:
+		delivery.Status = api.WebhookDelivery_DELIVERED.String()
+	case int(delivery.Attempts) >= driver.webhookRetries.MaxAttempts:
+		delivery.Status = api.WebhookDelivery_FAILED.String()
+		delivery.Error = webhooks.Describe(err)
+		log.Println("delivery", id, "to webhook", hook.Name, "failed after", delivery.Attempts, "attempts:", err)
+	default:
+		delivery.Error = webhooks.Describe(err)
+		delivery.NextAttemptAt = now.Add(driver.webhookRetries.backoff(delivery.Attempts))
+	}
+	if err := driver.db.Save(delivery).Error; err != nil {
+		log.Println("update delivery", id, "failed:", err)
+	}
+}
@@ -0,0 +1,200 @@
+//go:build cgo
+// +build cgo
+
+package driver
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lnsp/sox/api"
+	"github.com/lnsp/sox/driver/models"
+	"github.com/lnsp/sox/webhooks"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testSecret = "secret"
+
+func newWebhookDriver(t *testing.T, retries WebhookRetries) *Driver {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "sox.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Webhook{}, &models.WebhookDelivery{}); err != nil {
+		t.Fatalf("migrate db: %v", err)
+	}
+	return &Driver{
+		db:             db,
+		webhookRetries: retries,
+		// The test servers listen on loopback
+		webhookClient:       webhooks.NewClient(webhookTimeout, true),
+		webhookAllowPrivate: true,
+	}
+}
+
+func queueTestDeliveries(t *testing.T, driver *Driver, url string, n int) {
+	hook := models.Webhook{ID: "hook", Name: "hook", URL: url, Secret: testSecret}
+	if err := driver.db.Create(&hook).Error; err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if err := driver.db.Create(&models.WebhookDelivery{
+			WebhookID:     hook.ID,
+			Event:         `{"type":"RESOURCE_CREATED"}`,
+			EventType:     api.Event_RESOURCE_CREATED.String(),
+			Status:        api.WebhookDelivery_PENDING.String(),
+			NextAttemptAt: time.Now(),
+		}).Error; err != nil {
+			t.Fatalf("create delivery: %v", err)
+		}
+	}
+}
+
+func loadDelivery(t *testing.T, driver *Driver) models.WebhookDelivery {
+	var delivery models.WebhookDelivery
+	if err := driver.db.First(&delivery).Error; err != nil {
+		t.Fatalf("retrieve delivery: %v", err)
+	}
+	return delivery
+}
+
+func TestWebhookRetry(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !webhooks.Verify(testSecret, body, r.Header.Get(webhooks.SignatureHeader)) {
+			t.Errorf("signature %q does not verify", r.Header.Get(webhooks.SignatureHeader))
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	driver := newWebhookDriver(t, WebhookRetries{MaxAttempts: 3, Delay: 30 * time.Second, MaxDelay: time.Hour})
+	queueTestDeliveries(t, driver, server.URL, 1)
+
+	// The first attempt fails and is retried after the delay
+	if wait := driver.attemptDueDeliveries(); wait <= 0 || wait > 30*time.Second {
+		t.Errorf("wait after failed attempt = %v, want up to 30s", wait)
+	}
+	delivery := loadDelivery(t, driver)
+	if delivery.Status != api.WebhookDelivery_PENDING.String() || delivery.Attempts != 1 || delivery.ResponseCode != http.StatusInternalServerError {
+		t.Fatalf("delivery after failed attempt = %s, %d attempts, code %d, want PENDING, 1 attempt, code 500", delivery.Status, delivery.Attempts, delivery.ResponseCode)
+	}
+	if delivery.Error != "unexpected status 500 Internal Server Error" {
+		t.Errorf("delivery error = %q", delivery.Error)
+	}
+	if delivery.LastAttemptAt == nil || !delivery.NextAttemptAt.Equal(delivery.LastAttemptAt.Add(30*time.Second)) {
+		t.Errorf("next attempt at %v, want 30s after last attempt at %v", delivery.NextAttemptAt, delivery.LastAttemptAt)
+	}
+
+	// Deliveries are not attempted before they are due
+	driver.attemptDueDeliveries()
+	if delivery := loadDelivery(t, driver); delivery.Attempts != 1 {
+		t.Fatalf("delivery attempted %d times before it was due", delivery.Attempts)
+	}
+
+	if err := driver.db.Model(&delivery).Update("next_attempt_at", time.Now()).Error; err != nil {
+		t.Fatalf("update delivery: %v", err)
+	}
+	driver.attemptDueDeliveries()
+	delivery = loadDelivery(t, driver)
+	if delivery.Status != api.WebhookDelivery_DELIVERED.String() || delivery.Attempts != 2 || delivery.ResponseCode != http.StatusOK || delivery.Error != "" {
+		t.Errorf("delivery after retry = %s, %d attempts, code %d, error %q, want DELIVERED, 2 attempts, code 200", delivery.Status, delivery.Attempts, delivery.ResponseCode, delivery.Error)
+	}
+}
+
+func TestWebhookFailure(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+	driver := newWebhookDriver(t, WebhookRetries{MaxAttempts: 1, Delay: time.Second, MaxDelay: time.Second})
+	queueTestDeliveries(t, driver, url, 1)
+
+	driver.attemptDueDeliveries()
+	delivery := loadDelivery(t, driver)
+	if delivery.Status != api.WebhookDelivery_FAILED.String() || delivery.Attempts != 1 {
+		t.Errorf("delivery = %s, %d attempts, want FAILED, 1 attempt", delivery.Status, delivery.Attempts)
+	}
+	// Dial errors are not passed on to the owner of the webhook
+	if delivery.Error != "connection failed" {
+		t.Errorf("delivery error = %q, want connection failed", delivery.Error)
+	}
+}
+
+func TestWebhookPrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("delivery to loopback address was sent")
+	}))
+	defer server.Close()
+	driver := newWebhookDriver(t, WebhookRetries{MaxAttempts: 1, Delay: time.Second, MaxDelay: time.Second})
+	driver.webhookClient = webhooks.NewClient(webhookTimeout, false)
+	queueTestDeliveries(t, driver, server.URL, 1)
+
+	driver.attemptDueDeliveries()
+	if delivery := loadDelivery(t, driver); delivery.Error != "address not allowed" {
+		t.Errorf("delivery error = %q, want address not allowed", delivery.Error)
+	}
+}
+
+func TestWebhookConcurrentDeliveries(t *testing.T) {
+	var mu sync.Mutex
+	var active, peak int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > peak {
+			peak = active
+		}
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+	}))
+	defer server.Close()
+	driver := newWebhookDriver(t, DefaultWebhookRetries)
+	queueTestDeliveries(t, driver, server.URL, 3*webhookWorkers)
+
+	driver.attemptDueDeliveries()
+	var delivered int64
+	if err := driver.db.Model(&models.WebhookDelivery{}).Where("status = ?", api.WebhookDelivery_DELIVERED.String()).Count(&delivered).Error; err != nil {
+		t.Fatalf("count deliveries: %v", err)
+	}
+	if delivered != 3*webhookWorkers {
+		t.Errorf("%d deliveries delivered, want %d", delivered, 3*webhookWorkers)
+	}
+	if peak < 2 || peak > webhookWorkers {
+		t.Errorf("%d deliveries attempted at once, want between 2 and %d", peak, webhookWorkers)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	retries := WebhookRetries{MaxAttempts: 10, Delay: 30 * time.Second, MaxDelay: 5 * time.Minute}
+	for _, test := range []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{5, 5 * time.Minute},
+		{9, 5 * time.Minute},
+	} {
+		if got := retries.backoff(test.attempts); got != test.want {
+			t.Errorf("backoff(%d) = %v, want %v", test.attempts, got, test.want)
+		}
+	}
+}
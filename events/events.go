@@ -0,0 +1,115 @@
+// Package events hands resource change events to watchers and keeps recent events for resuming watches.
+package events
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lnsp/sox/api"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// historySize is the number of recent events watches can be resumed from.
+	historySize = 1000
+	// watcherBuffer is the number of events a watcher may fall behind before it is stopped.
+	watcherBuffer = 256
+)
+
+// ErrExpired is returned for resume tokens whose following events are no longer kept.
+var ErrExpired = errors.New("events after the resume token are no longer available")
+
+// Bus numbers published events and hands them to all watchers.
+type Bus struct {
+	node string
+	// epoch tells apart the event IDs of different runs.
+	epoch string
+
+	mu       sync.Mutex
+	seq      uint64
+	history  []*api.Event
+	watchers map[*Watcher]struct{}
+}
+
+// Watcher receives events on C, which is closed once the watcher is stopped or fell behind.
+type Watcher struct {
+	C <-chan *api.Event
+	c chan *api.Event
+}
+
+// NewBus returns a bus for the events of the named node.
+func NewBus(node string) *Bus {
+	return &Bus{
+		node:     node,
+		epoch:    strconv.FormatInt(time.Now().UnixNano(), 36),
+		watchers: make(map[*Watcher]struct{}),
+	}
+}
+
+// Publish assigns the next ID to the event and hands it to all watchers. Events must not be modified afterwards.
+func (bus *Bus) Publish(event *api.Event) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.seq++
+	event.Id = bus.epoch + "." + strconv.FormatUint(bus.seq, 10)
+	event.Node = bus.node
+	if event.Timestamp == nil {
+		event.Timestamp = timestamppb.Now()
+	}
+	bus.history = append(bus.history, event)
+	if len(bus.history) > historySize {
+		bus.history = bus.history[len(bus.history)-historySize:]
+	}
+	for w := range bus.watchers {
+		select {
+		case w.c <- event:
+		default:
+			// Let slow watchers resume instead of blocking everyone else
+			delete(bus.watchers, w)
+			close(w.c)
+		}
+	}
+}
+
+// Watch returns a watcher receiving the events following the one with the resume token,
+// or only new events if the token is empty.
+func (bus *Bus) Watch(resumeToken string) (*Watcher, error) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	var backlog []*api.Event
+	if resumeToken != "" {
+		epoch, seq := "", ""
+		if i := strings.LastIndexByte(resumeToken, '.'); i >= 0 {
+			epoch, seq = resumeToken[:i], resumeToken[i+1:]
+		}
+		last, err := strconv.ParseUint(seq, 10, 64)
+		if err != nil || epoch != bus.epoch || last > bus.seq {
+			return nil, ErrExpired
+		}
+		first := bus.seq - uint64(len(bus.history)) + 1
+		if last+1 < first {
+			return nil, ErrExpired
+		}
+		backlog = bus.history[last+1-first:]
+	}
+	c := make(chan *api.Event, watcherBuffer+len(backlog))
+	for _, event := range backlog {
+		c <- event
+	}
+	w := &Watcher{C: c, c: c}
+	bus.watchers[w] = struct{}{}
+	return w, nil
+}
+
+// Stop stops the watcher and closes its channel.
+func (bus *Bus) Stop(w *Watcher) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	if _, ok := bus.watchers[w]; ok {
+		delete(bus.watchers, w)
+		close(w.c)
+	}
+}
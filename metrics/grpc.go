@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// GRPC counts the requests handled by a gRPC server and measures their durations.
+type GRPC struct {
+	requests  *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+}
+
+func NewGRPC() *GRPC {
+	return &GRPC{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "sox_grpc_requests_total",
+			Help: "Number of handled gRPC requests by method and status code.",
+		}, []string{"method", "code"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "sox_grpc_request_duration_seconds",
+			Help:    "Time taken to handle unary gRPC requests by method.",
+			Buckets: DefaultBuckets,
+		}, []string{"method"}),
+	}
+}
+
+func (g *GRPC) Describe(ch chan<- *prometheus.Desc) {
+	g.requests.Describe(ch)
+	g.durations.Describe(ch)
+}
+
+func (g *GRPC) Collect(ch chan<- prometheus.Metric) {
+	g.requests.Collect(ch)
+	g.durations.Collect(ch)
+}
+
+// UnaryInterceptor counts and times unary requests, it should be chained first to include the time of other interceptors.
+func (g *GRPC) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		g.durations.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		g.requests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		return resp, err
+	}
+}
+
+// StreamInterceptor counts streaming requests once they are done. Their durations are not
+// measured, since watches last as long as their clients want.
+func (g *GRPC) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, stream)
+		g.requests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		return err
+	}
+}
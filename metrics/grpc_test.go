@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCRequests(t *testing.T) {
+	g := NewGRPC()
+	unary := g.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/sox.v1.Sox/ListMachines"}
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+	denied := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Errorf(codes.PermissionDenied, "denied")
+	}
+	for _, handler := range []grpc.UnaryHandler{ok, ok, denied} {
+		unary(context.Background(), nil, info, handler)
+	}
+	stream := g.StreamInterceptor()
+	stream(nil, nil, &grpc.StreamServerInfo{FullMethod: "/sox.v1.Sox/WatchEvents"}, func(srv interface{}, stream grpc.ServerStream) error { return nil })
+
+	expected := `
+# HELP sox_grpc_requests_total Number of handled gRPC requests by method and status code.
+# TYPE sox_grpc_requests_total counter
+sox_grpc_requests_total{code="OK",method="/sox.v1.Sox/ListMachines"} 2
+sox_grpc_requests_total{code="PermissionDenied",method="/sox.v1.Sox/ListMachines"} 1
+sox_grpc_requests_total{code="OK",method="/sox.v1.Sox/WatchEvents"} 1
+`
+	if err := testutil.CollectAndCompare(g, strings.NewReader(expected), "sox_grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+	// Only unary requests are timed
+	if n := testutil.CollectAndCount(g, "sox_grpc_request_duration_seconds"); n != 1 {
+		t.Errorf("%d duration histograms, want 1", n)
+	}
+}
@@ -0,0 +1,5 @@
+// Package metrics holds the Prometheus metrics shared by aggregators and nodes.
+package metrics
+
+// DefaultBuckets are the upper bounds of durations in seconds, from 5ms to one hour.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300, 900, 3600}
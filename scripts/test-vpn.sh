@@ -0,0 +1,19 @@
+#!/usr/bin/env bash
+# Runs the VPN peer tests in a new network namespace, so the WireGuard device and
+# firewall rules they create never touch the host. Needs root, wg, iptables and
+# either the wireguard kernel module or wireguard-go.
+set -euo pipefail
+
+if [ "$(id -u)" -ne 0 ]; then
+	echo "must be run as root" >&2
+	exit 1
+fi
+for tool in wg iptables unshare; do
+	if ! command -v "$tool" >/dev/null; then
+		echo "$tool is required" >&2
+		exit 1
+	fi
+done
+
+cd "$(dirname "$0")/.."
+exec unshare --net sh -c 'ip link set lo up && SOX_NETNS_TEST=1 CGO_ENABLED=1 go test -count=1 -v -run VPNPeer ./driver/'
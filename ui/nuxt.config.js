@@ -26,6 +26,8 @@ export default {
 
   // Plugins to run before rendering page: https://go.nuxtjs.dev/config-plugins
   plugins: [
+    { src: '~/plugins/session.js', mode: 'client' },
+    { src: '~/plugins/events.js', mode: 'client' },
   ],
 
   // Auto import components: https://go.nuxtjs.dev/config-components
@@ -52,6 +54,8 @@ export default {
   // Axios module configuration: https://go.nuxtjs.dev/config-axios
   axios: {
     baseURL: process.env.NODE_ENV !== 'production' ? (process.env.API_URL || 'http://localhost:9877/api') : '/api',
+    // the session cookie has to be sent to the UI server in dev mode as well
+    credentials: true,
   },
 
   // Build Configuration: https://go.nuxtjs.dev/config-build
@@ -0,0 +1,27 @@
+// Keeps the store up to date with the events streamed by the UI server.
+// The browser reconnects to the stream on its own and resumes after the last event.
+export default function ({ $axios, store }) {
+  const source = new EventSource($axios.defaults.baseURL.replace(/\/$/, '') + '/events', { withCredentials: true })
+  source.onmessage = (message) => {
+    const event = JSON.parse(message.data)
+    switch (event.type) {
+      case 'MACHINE_STATE_CHANGED':
+        store.commit('api/machineStatus', { id: event.subject, status: event.status })
+        break
+      case 'RESOURCE_CREATED':
+      case 'RESOURCE_DELETED':
+        if (event.kind === 'machine') {
+          store.dispatch('api/machines')
+        }
+        break
+      case 'ACTIVITY_RECORDED':
+        store.commit('api/addActivity', event.activity)
+        break
+    }
+  }
+  // Events were missed, so everything shown may be outdated
+  source.addEventListener('expired', () => {
+    store.dispatch('api/machines')
+    store.dispatch('api/activities')
+  })
+}
@@ -0,0 +1,17 @@
+// Sends users to the login page of the UI server once their session is gone
+// and attaches the CSRF token of the session to all requests.
+export default async function ({ $axios, store }) {
+  const loginURL = $axios.defaults.baseURL.replace(/\/api\/?$/, '') + '/login'
+  $axios.onError((error) => {
+    if (error.response && error.response.status === 401) {
+      window.location.href = loginURL
+    }
+  })
+  try {
+    const session = await $axios.$get('/session')
+    $axios.setHeader('X-CSRF-Token', session.csrfToken)
+    store.commit('api/user', session.user)
+  } catch (error) {
+    // handled by the error interceptor
+  }
+}
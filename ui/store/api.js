@@ -2,24 +2,46 @@ import Vue from 'vue';
 
 export const state = () => ({
   version: '',
+  user: '',
   machines: [],
   sshKeys: [],
   images: [],
   networks: [],
   machineDetails: {},
+  machineStats: {},
+  portForwards: {},
   activities: [],
+  quota: null,
+  hosts: [],
 });
 
 export const mutations = {
   version(state, version) {
     state.version = version
   },
+  user(state, user) {
+    state.user = user
+  },
   machines(state, machines) {
     state.machines = machines
   },
   machineDetails(state, { id, details }) {
     Vue.set(state.machineDetails, id, details)
   },
+  machineStatus(state, { id, status }) {
+    state.machines.filter((machine) => machine.id === id).forEach((machine) => {
+      machine.status = status
+    })
+    if (state.machineDetails[id]) {
+      state.machineDetails[id].status = status
+    }
+  },
+  machineStats(state, { id, stats }) {
+    Vue.set(state.machineStats, id, stats)
+  },
+  portForwards(state, { id, portForwards }) {
+    Vue.set(state.portForwards, id, portForwards)
+  },
   sshKeys(state, sshKeys) {
     state.sshKeys = sshKeys
   },
@@ -31,6 +53,15 @@ export const mutations = {
   },
   activities(state, activities) {
     state.activities = activities
+  },
+  addActivity(state, activity) {
+    state.activities.push(activity)
+  },
+  quota(state, quota) {
+    state.quota = quota
+  },
+  hosts(state, hosts) {
+    state.hosts = hosts
   }
 }
 
@@ -45,6 +76,10 @@ export const actions = {
     let response = await this.$axios.$get('/')
     commit('version', response.version)
   },
+  async logout() {
+    await this.$axios.$post('/logout')
+    window.location.href = this.$axios.defaults.baseURL.replace(/\/api\/?$/, '') + '/login'
+  },
   async machines({ commit }) {
     let response = await this.$axios.$get('/machines')
     commit('machines', response)
@@ -65,8 +100,24 @@ export const actions = {
     let details = await this.$axios.$get('/machines/' + id)
     commit('machineDetails', { id, details })
   },
+  async machineStats({ commit }, id) {
+    let stats = await this.$axios.$get('/machines/' + id + '/stats')
+    commit('machineStats', { id, stats })
+  },
+  async portForwards({ commit }, id) {
+    let portForwards = await this.$axios.$get('/machines/' + id + '/port-forwards')
+    commit('portForwards', { id, portForwards })
+  },
   async activities({ commit }) {
     let response = await this.$axios.$get('/activities')
     commit('activities', response.activities)
+  },
+  async quota({ commit }) {
+    let response = await this.$axios.$get('/quota')
+    commit('quota', response)
+  },
+  async hosts({ commit }) {
+    let response = await this.$axios.$get('/hosts')
+    commit('hosts', response)
   }
 }
\ No newline at end of file
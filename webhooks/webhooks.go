@@ -0,0 +1,204 @@
+// Package webhooks signs webhook deliveries, sends them and verifies their signatures on the receiving end.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lnsp/sox/meta"
+)
+
+const (
+	// SignatureHeader holds the hex-encoded HMAC-SHA256 of the body keyed with the webhook secret, prefixed with sha256=.
+	SignatureHeader = "X-Sox-Signature"
+	// DeliveryHeader holds the ID of the delivery, which stays the same across retries.
+	DeliveryHeader = "X-Sox-Delivery"
+	// EventHeader holds the type of the delivered event.
+	EventHeader = "X-Sox-Event"
+
+	signaturePrefix = "sha256="
+)
+
+// GenerateSecret returns a random secret to sign deliveries with.
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("read random: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// ErrAddressNotAllowed is returned for hosts resolving to loopback, link-local or private addresses
+// unless those are allowed.
+var ErrAddressNotAllowed = errors.New("address not allowed")
+
+// privateNetworks are the ranges besides loopback and link-local addresses which are not reachable
+// from the internet, including the shared address space of carrier-grade NAT.
+var privateNetworks = parseNetworks("0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7")
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// allowedIP reports whether deliveries may be sent to the address.
+func allowedIP(ip net.IP, allowPrivate bool) bool {
+	if ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+	if allowPrivate {
+		return true
+	}
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+		return false
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateURL checks that deliveries can be posted to the URL. The host must only resolve to
+// public addresses unless allowPrivate is set.
+func ValidateURL(ctx context.Context, rawURL string, allowPrivate bool) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("host must not be empty")
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("resolve host %s failed", u.Hostname())
+	}
+	for _, addr := range addrs {
+		if !allowedIP(addr.IP, allowPrivate) {
+			return fmt.Errorf("host %s resolves to %s: %w", u.Hostname(), addr.IP, ErrAddressNotAllowed)
+		}
+	}
+	return nil
+}
+
+// NewClient returns a client for sending deliveries. It checks the address of every connection it
+// makes, so hosts resolving to other addresses after validating their URL are refused as well.
+// Redirects are not followed and proxies from the environment are not used.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allowedIP(ip, allowPrivate) {
+				return ErrAddressNotAllowed
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// StatusError is returned for responses with a status code outside of 2xx.
+type StatusError struct {
+	Status string
+}
+
+func (err *StatusError) Error() string {
+	return "unexpected status " + err.Status
+}
+
+// Describe returns a description of the error returned by Send which is safe to show to the owner
+// of the webhook. Connection errors are not described in detail, they would reveal which addresses
+// and ports are reachable from the sender.
+func Describe(err error) string {
+	var statusErr *StatusError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &statusErr):
+		return statusErr.Error()
+	case errors.Is(err, ErrAddressNotAllowed):
+		return ErrAddressNotAllowed.Error()
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timed out"
+	default:
+		return "connection failed"
+	}
+}
+
+// Sign returns the signature of the body as sent in the SignatureHeader.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature of the body was made with the secret.
+func Verify(secret string, body []byte, signature string) bool {
+	return strings.HasPrefix(signature, signaturePrefix) && hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Send posts the signed body to the URL and returns the status code of the response.
+// Responses with a status code outside of 2xx, including redirects, are returned as StatusError.
+func Send(ctx context.Context, client *http.Client, url, secret, delivery, event string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sox-webhooks/"+meta.Version)
+	req.Header.Set(SignatureHeader, Sign(secret, body))
+	req.Header.Set(DeliveryHeader, delivery)
+	req.Header.Set(EventHeader, event)
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &StatusError{Status: resp.Status}
+	}
+	return resp.StatusCode, nil
+}
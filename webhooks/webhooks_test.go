@@ -0,0 +1,137 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSendSignature(t *testing.T) {
+	const secret = "secret"
+	body := []byte(`{"id":"1"}`)
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		if !Verify(secret, payload, r.Header.Get(SignatureHeader)) {
+			t.Errorf("signature %q does not verify", r.Header.Get(SignatureHeader))
+		}
+		if Verify("other", payload, r.Header.Get(SignatureHeader)) {
+			t.Errorf("signature verifies with another secret")
+		}
+		received <- r
+	}))
+	defer server.Close()
+
+	code, err := Send(context.Background(), NewClient(time.Second, true), server.URL, secret, "42", "MACHINE_CREATED", body)
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("Send() = %d, %v, want 200", code, err)
+	}
+	r := <-received
+	if got := r.Header.Get(DeliveryHeader); got != "42" {
+		t.Errorf("delivery header = %q, want 42", got)
+	}
+	if got := r.Header.Get(EventHeader); got != "MACHINE_CREATED" {
+		t.Errorf("event header = %q, want MACHINE_CREATED", got)
+	}
+}
+
+func TestSendStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		code    int
+		error   string
+	}{
+		{
+			name:    "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			code:    http.StatusInternalServerError,
+			error:   "unexpected status 500 Internal Server Error",
+		},
+		{
+			name: "redirect",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "http://169.254.169.254/", http.StatusFound)
+			},
+			code:  http.StatusFound,
+			error: "unexpected status 302 Found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(test.handler)
+			defer server.Close()
+			code, err := Send(context.Background(), NewClient(time.Second, true), server.URL, "secret", "1", "event", nil)
+			if code != test.code {
+				t.Errorf("code = %d, want %d", code, test.code)
+			}
+			if got := Describe(err); got != test.error {
+				t.Errorf("Describe() = %q, want %q", got, test.error)
+			}
+		})
+	}
+}
+
+func TestSendPrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request to loopback address was sent")
+	}))
+	defer server.Close()
+	_, err := Send(context.Background(), NewClient(time.Second, false), server.URL, "secret", "1", "event", nil)
+	if !errors.Is(err, ErrAddressNotAllowed) {
+		t.Fatalf("Send() error = %v, want %v", err, ErrAddressNotAllowed)
+	}
+	if got := Describe(err); got != "address not allowed" {
+		t.Errorf("Describe() = %q, want address not allowed", got)
+	}
+}
+
+func TestDescribeConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+	_, err := Send(context.Background(), NewClient(time.Second, true), url, "secret", "1", "event", nil)
+	if err == nil {
+		t.Fatal("Send() to closed server succeeded")
+	}
+	if got := Describe(err); got != "connection failed" {
+		t.Errorf("Describe() = %q, want connection failed", got)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		allowPrivate bool
+		valid        bool
+	}{
+		{"https://203.0.113.10/hook", false, true},
+		{"http://[2001:db8::1]:8080/hook", false, true},
+		{"ftp://203.0.113.10/hook", false, false},
+		{"http:///hook", false, false},
+		{"http://127.0.0.1:8080/hook", false, false},
+		{"http://127.0.0.1:8080/hook", true, true},
+		{"http://[::1]/hook", false, false},
+		{"http://169.254.169.254/latest/meta-data", false, false},
+		{"http://10.1.2.3/hook", false, false},
+		{"http://172.20.0.1/hook", false, false},
+		{"http://192.168.1.1/hook", false, false},
+		{"http://100.64.0.1/hook", false, false},
+		{"http://[fd00::1]/hook", false, false},
+		{"http://0.0.0.0/hook", true, false},
+		{"http://localhost/hook", false, false},
+	}
+	for _, test := range tests {
+		err := ValidateURL(context.Background(), test.url, test.allowPrivate)
+		if valid := err == nil; valid != test.valid {
+			t.Errorf("ValidateURL(%q, %v) = %v, want valid %v", test.url, test.allowPrivate, err, test.valid)
+		}
+	}
+}